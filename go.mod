@@ -6,6 +6,9 @@ require (
 	github.com/expr-lang/expr v1.17.5
 	github.com/joho/godotenv v1.5.1
 	github.com/mark3labs/mcp-go v0.38.0
+	github.com/santhosh-tekuri/jsonschema/v6 v6.0.3
+	go.etcd.io/bbolt v1.4.3
+	golang.org/x/text v0.14.0
 )
 
 require (
@@ -17,5 +20,6 @@ require (
 	github.com/spf13/cast v1.7.1 // indirect
 	github.com/wk8/go-ordered-map/v2 v2.1.8 // indirect
 	github.com/yosida95/uritemplate/v3 v3.0.2 // indirect
+	golang.org/x/sys v0.29.0 // indirect
 	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
@@ -4,15 +4,21 @@ import (
 	"context"
 	"log"
 	"net/http"
+	"os"
+	"os/signal"
 	"strings"
+	"syscall"
+	"time"
+	_ "time/tzdata" // embedded zone database for NWS station timezones
 
+	"github.com/Riddlerrr/lazymcp/storage"
 	"github.com/Riddlerrr/lazymcp/tools"
 	"github.com/joho/godotenv"
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
 )
 
-func NewMCPServer() *server.MCPServer {
+func NewMCPServer() (*server.MCPServer, *tools.ToolSet) {
 	hooks := &server.Hooks{}
 
 	hooks.AddOnSuccess(func(ctx context.Context, id any, method mcp.MCPMethod, message any, result any) {
@@ -23,6 +29,8 @@ func NewMCPServer() *server.MCPServer {
 		log.Printf("Error: %s, %v, %v, %v\n", method, id, message, err)
 	})
 
+	hooks.AddAfterCallTool(tools.RecordRecentArguments)
+
 	hooks.AddOnRegisterSession(func(ctx context.Context, session server.ClientSession) {
 		log.Printf("onRegisterSession: %s\n", session.SessionID())
 	})
@@ -30,28 +38,96 @@ func NewMCPServer() *server.MCPServer {
 	mcpServer := server.NewMCPServer(
 		"LazyMCP",
 		"1.0.0",
-		server.WithToolCapabilities(false),
+		server.WithToolCapabilities(true),
+		server.WithPromptCapabilities(false),
+		server.WithResourceCapabilities(true, false),
 		server.WithRecovery(),
 		server.WithLogging(),
 		server.WithHooks(hooks),
 	)
 
+	// Tools are listed through the tool set, which drops those the configuration cannot serve
+	toolSet := tools.NewToolSet(mcpServer)
+	hooks.AddBeforeCallTool(toolSet.ApplyPreferences)
+
 	calculator := tools.NewCalculatorTool()
-	mcpServer.AddTool(calculator.Tool, calculator.Handler)
+	toolSet.Add(calculator.Tool, calculator.Handler)
 
 	ipTool := tools.NewIPTool()
-	mcpServer.AddTool(ipTool.Tool, ipTool.Handler)
+	toolSet.Add(ipTool.Tool, ipTool.Handler)
 
 	ipDataTool := tools.NewIPDataTool()
-	mcpServer.AddTool(ipDataTool.Tool, ipDataTool.Handler)
+	toolSet.Add(ipDataTool.Tool, ipDataTool.Handler)
 
 	weatherTool := tools.NewWeatherTool()
-	mcpServer.AddTool(weatherTool.Tool, weatherTool.Handler)
+	toolSet.Add(weatherTool.Tool, weatherTool.Handler)
 
 	weatherForecastTool := tools.NewWeatherForecastTool()
-	mcpServer.AddTool(weatherForecastTool.Tool, weatherForecastTool.Handler)
+	toolSet.Add(weatherForecastTool.Tool, weatherForecastTool.Handler)
+
+	geocodeTool := tools.NewGeocodeTool()
+	toolSet.Add(geocodeTool.Tool, geocodeTool.Handler)
+
+	weatherAlertsTool := tools.NewWeatherAlertsTool()
+	toolSet.Add(weatherAlertsTool.Tool, weatherAlertsTool.Handler)
+
+	airQualityTool := tools.NewAirQualityTool()
+	toolSet.Add(airQualityTool.Tool, airQualityTool.Handler)
+
+	weatherHistoryTool := tools.NewWeatherHistoryTool()
+	toolSet.Add(weatherHistoryTool.Tool, weatherHistoryTool.Handler)
+
+	compareWeatherTool := tools.NewCompareWeatherTool()
+	toolSet.Add(compareWeatherTool.Tool, compareWeatherTool.Handler)
+
+	astronomyTool := tools.NewAstronomyTool()
+	toolSet.Add(astronomyTool.Tool, astronomyTool.Handler)
+
+	weatherSuitabilityTool := tools.NewWeatherSuitabilityTool()
+	toolSet.Add(weatherSuitabilityTool.Tool, weatherSuitabilityTool.Handler)
+
+	saveLocationTool := tools.NewSaveLocationTool()
+	toolSet.Add(saveLocationTool.Tool, saveLocationTool.Handler)
+
+	listSavedLocationsTool := tools.NewListSavedLocationsTool()
+	toolSet.Add(listSavedLocationsTool.Tool, listSavedLocationsTool.Handler)
+
+	renameSavedLocationTool := tools.NewRenameSavedLocationTool()
+	toolSet.Add(renameSavedLocationTool.Tool, renameSavedLocationTool.Handler)
 
-	return mcpServer
+	deleteSavedLocationTool := tools.NewDeleteSavedLocationTool()
+	toolSet.Add(deleteSavedLocationTool.Tool, deleteSavedLocationTool.Handler)
+
+	setPreferencesTool := tools.NewSetPreferencesTool()
+	toolSet.Add(setPreferencesTool.Tool, setPreferencesTool.Handler)
+
+	toolSet.Refresh()
+
+	activityPrompt := tools.NewActivityPrompt()
+	mcpServer.AddPrompt(activityPrompt.Prompt, activityPrompt.Handler)
+
+	dailyBriefingPrompt := tools.NewDailyBriefingPrompt()
+	mcpServer.AddPrompt(dailyBriefingPrompt.Prompt, dailyBriefingPrompt.Handler)
+
+	diagnoseIPPrompt := tools.NewDiagnoseIPPrompt()
+	mcpServer.AddPrompt(diagnoseIPPrompt.Prompt, diagnoseIPPrompt.Handler)
+
+	tripWeatherPrompt := tools.NewTripWeatherPrompt()
+	mcpServer.AddPrompt(tripWeatherPrompt.Prompt, tripWeatherPrompt.Handler)
+
+	mathProblemPrompt := tools.NewMathProblemPrompt()
+	mcpServer.AddPrompt(mathProblemPrompt.Prompt, mathProblemPrompt.Handler)
+
+	currentWeatherResource := tools.NewCurrentWeatherResource()
+	mcpServer.AddResourceTemplate(currentWeatherResource.Template, currentWeatherResource.Handler)
+
+	forecastResource := tools.NewForecastResource()
+	mcpServer.AddResourceTemplate(forecastResource.Template, forecastResource.Handler)
+
+	ipResource := tools.NewIPResource()
+	mcpServer.AddResourceTemplate(ipResource.Template, ipResource.Handler)
+
+	return mcpServer, toolSet
 }
 
 func getClientIP(r *http.Request) string {
@@ -82,25 +158,115 @@ func getClientIP(r *http.Request) string {
 	return r.RemoteAddr
 }
 
+const (
+	// stateCleanupInterval is how often expired values are removed from the state file
+	stateCleanupInterval = time.Hour
+	// shutdownTimeout is how long requests in progress may take to finish on shutdown
+	shutdownTimeout = 10 * time.Second
+)
+
 func main() {
 	// Load .env file
 	if err := godotenv.Load(); err != nil {
 		log.Println("No .env file found, using system environment variables")
 	}
 
-	s := NewMCPServer()
+	// How often subscribed weather resources are polled
+	pollInterval := tools.DefaultPollInterval
+	if value := os.Getenv("RESOURCE_POLL_INTERVAL"); value != "" {
+		interval, err := time.ParseDuration(value)
+		if err != nil || interval < time.Minute {
+			log.Fatalf("Invalid RESOURCE_POLL_INTERVAL %q: must be a duration of at least 1m", value)
+		}
+		pollInterval = interval
+	}
+
+	// Stop on SIGINT or SIGTERM, letting requests finish and closing the state file
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// Keep preferences and saved locations in a state file, shared with other instances when
+	// STATE_SHARED is set
+	stateFile := os.Getenv("STATE_FILE")
+	if stateFile == "" {
+		stateFile = "lazymcp.db"
+	}
+	shared := os.Getenv("STATE_SHARED") == "true"
+	store, err := storage.Open(stateFile, shared)
+	if err != nil {
+		log.Fatalf("Failed to open state file %s: %v", stateFile, err)
+	}
+
+	from, to, err := tools.UseStore(store)
+	if err != nil {
+		store.Close()
+		log.Fatalf("Failed to migrate state file %s: %v", stateFile, err)
+	}
+	if from != to {
+		log.Printf("Migrated state file %s from schema version %d to %d", stateFile, from, to)
+	}
+	go storage.CleanupEvery(ctx, store, stateCleanupInterval)
+
+	s, toolSet := NewMCPServer()
+
+	// Reload .env on SIGHUP and re-evaluate which tools are available
+	reload := make(chan os.Signal, 1)
+	signal.Notify(reload, syscall.SIGHUP)
+	go func() {
+		for range reload {
+			if err := godotenv.Overload(); err != nil {
+				log.Printf("Failed to reload .env file: %v", err)
+			}
+			log.Println("Configuration reloaded")
+			toolSet.Refresh()
+		}
+	}()
+
+	contextFunc := func(ctx context.Context, r *http.Request) context.Context {
+		clientIP := getClientIP(r)
+		ctx = context.WithValue(ctx, tools.ClientIPKey, clientIP)
+		// The authenticated user, from a header set by a trusted reverse proxy
+		if header := os.Getenv("PRINCIPAL_HEADER"); header != "" {
+			ctx = context.WithValue(ctx, tools.PrincipalKey, r.Header.Get(header))
+		}
+		return context.WithValue(ctx, tools.AcceptLanguageKey, r.Header.Get("Accept-Language"))
+	}
 
 	// Create HTTP transport server with custom context function
-	httpServer := server.NewStreamableHTTPServer(s,
-		server.WithHTTPContextFunc(func(ctx context.Context, r *http.Request) context.Context {
-			clientIP := getClientIP(r)
-			return context.WithValue(ctx, tools.ClientIPKey, clientIP)
-		}),
-	)
+	httpServer := server.NewStreamableHTTPServer(s, server.WithHTTPContextFunc(contextFunc))
+
+	// Poll subscribed weather resources in the background, and forget per-session state when a
+	// session ends
+	sessions := tools.NewSessionTracker()
+	subscriptions := tools.NewSubscriptionManager(s, sessions, pollInterval)
+	go subscriptions.Run(ctx)
+
+	mux := http.NewServeMux()
+	mux.Handle("/mcp", tools.RPCMiddleware(sessions.Middleware(subscriptions.Middleware(contextFunc, tools.CompletionMiddleware(contextFunc, tools.CancellationMiddleware(tools.ElicitationMiddleware(httpServer)))))))
 
 	// Start the server on port 3000
-	log.Printf("HTTP server starting on http://localhost:3000/mcp")
-	if err := httpServer.Start(":3000"); err != nil {
+	srv := &http.Server{Addr: ":3000", Handler: mux}
+	serveErr := make(chan error, 1)
+	go func() {
+		log.Printf("HTTP server starting on http://localhost:3000/mcp")
+		serveErr <- srv.ListenAndServe()
+	}()
+
+	select {
+	case err := <-serveErr:
+		store.Close()
 		log.Fatal(err)
+	case <-ctx.Done():
+	}
+
+	// Open GET streams never finish on their own, so they are cut after the grace period
+	log.Println("Shutting down")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		srv.Close()
+	}
+	if err := store.Close(); err != nil {
+		log.Printf("Failed to close state file %s: %v", stateFile, err)
 	}
 }
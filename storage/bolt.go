@@ -0,0 +1,224 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+const (
+	// exclusiveLockTimeout is how long opening a file another process holds open may take
+	exclusiveLockTimeout = time.Second
+	// sharedLockTimeout is how long a transaction on a shared file waits for other processes
+	sharedLockTimeout = 10 * time.Second
+	// modTimeResolution is the coarsest modification time step of the filesystems BoltDB runs on;
+	// two writes within it may leave the same time
+	modTimeResolution = 2 * time.Second
+)
+
+// Bolt is a Store backed by a BoltDB file. BoltDB locks the file while it is open, so by default
+// one process owns it. A shared Bolt opens the file for each transaction and closes it right after,
+// so several processes take turns; this trades throughput for sharing.
+type Bolt struct {
+	path   string
+	shared bool
+
+	mu     sync.Mutex
+	db     *bolt.DB // nil when shared or closed
+	closed bool
+}
+
+// OpenBolt opens or creates the BoltDB file at path
+func OpenBolt(path string, shared bool) (*Bolt, error) {
+	b := &Bolt{path: path, shared: shared}
+	if shared {
+		// Create the file and check that it can be opened
+		db, err := b.open()
+		if err != nil {
+			return nil, err
+		}
+		return b, db.Close()
+	}
+
+	db, err := b.open()
+	if err != nil {
+		if errors.Is(err, bolt.ErrTimeout) {
+			return nil, fmt.Errorf("%s is in use by another process; open it as shared to use it from several processes", path)
+		}
+		return nil, err
+	}
+	b.db = db
+	return b, nil
+}
+
+func (b *Bolt) open() (*bolt.DB, error) {
+	timeout := exclusiveLockTimeout
+	if b.shared {
+		timeout = sharedLockTimeout
+	}
+	return bolt.Open(b.path, 0o600, &bolt.Options{Timeout: timeout})
+}
+
+// with runs fn on the open database, opening it for the duration of fn when it is shared
+func (b *Bolt) with(fn func(db *bolt.DB) error) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return errClosed
+	}
+	if !b.shared {
+		return fn(b.db)
+	}
+
+	db, err := b.open()
+	if err != nil {
+		return err
+	}
+	err = fn(db)
+	if closeErr := db.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+func (b *Bolt) View(fn func(tx Tx) error) error {
+	return b.with(func(db *bolt.DB) error {
+		return db.View(func(tx *bolt.Tx) error {
+			return fn(&boltTx{tx: tx})
+		})
+	})
+}
+
+func (b *Bolt) Update(fn func(tx Tx) error) error {
+	return b.with(func(db *bolt.DB) error {
+		return db.Update(func(tx *bolt.Tx) error {
+			return fn(&boltTx{tx: tx})
+		})
+	})
+}
+
+func (b *Bolt) Cleanup() (int, error) {
+	removed := 0
+	err := b.with(func(db *bolt.DB) error {
+		removed = 0
+		return db.Update(func(tx *bolt.Tx) error {
+			now := time.Now()
+			return tx.ForEach(func(name []byte, bucket *bolt.Bucket) error {
+				var expired [][]byte
+				err := bucket.ForEach(func(key []byte, data []byte) error {
+					if data != nil && expiredRecord(data, now) {
+						expired = append(expired, append([]byte(nil), key...))
+					}
+					return nil
+				})
+				if err != nil {
+					return err
+				}
+				for _, key := range expired {
+					if err := bucket.Delete(key); err != nil {
+						return err
+					}
+				}
+				removed += len(expired)
+				return nil
+			})
+		})
+	})
+	return removed, err
+}
+
+// fileVersion identifies the content of a BoltDB file: every commit rewrites a meta page, which
+// updates the modification time
+type fileVersion struct {
+	modTime time.Time
+	size    int64
+}
+
+// Version returns the modification time and size of the file. It is settled once the file is
+// older than the filesystem's time resolution, as a write after that moves the time.
+func (b *Bolt) Version() (any, bool, error) {
+	info, err := os.Stat(b.path)
+	if err != nil {
+		return nil, false, err
+	}
+	version := fileVersion{modTime: info.ModTime(), size: info.Size()}
+	return version, time.Since(version.modTime) > modTimeResolution, nil
+}
+
+func (b *Bolt) Close() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return nil
+	}
+	b.closed = true
+	if b.db != nil {
+		return b.db.Close()
+	}
+	return nil
+}
+
+type boltTx struct {
+	tx *bolt.Tx
+}
+
+func (t *boltTx) Get(bucket string, key string, value any) error {
+	b := t.tx.Bucket([]byte(bucket))
+	if b == nil {
+		return ErrNotFound
+	}
+	data := b.Get([]byte(key))
+	if data == nil {
+		return ErrNotFound
+	}
+	return decodeValue(data, value)
+}
+
+func (t *boltTx) Put(bucket string, key string, value any, ttl time.Duration) error {
+	if !t.tx.Writable() {
+		return errReadOnly
+	}
+	data, err := encodeRecord(value, ttl)
+	if err != nil {
+		return err
+	}
+	b, err := t.tx.CreateBucketIfNotExists([]byte(bucket))
+	if err != nil {
+		return err
+	}
+	return b.Put([]byte(key), data)
+}
+
+func (t *boltTx) Delete(bucket string, key string) error {
+	if !t.tx.Writable() {
+		return errReadOnly
+	}
+	b := t.tx.Bucket([]byte(bucket))
+	if b == nil {
+		return nil
+	}
+	return b.Delete([]byte(key))
+}
+
+func (t *boltTx) Keys(bucket string) ([]string, error) {
+	b := t.tx.Bucket([]byte(bucket))
+	if b == nil {
+		return nil, nil
+	}
+
+	var keys []string
+	err := b.ForEach(func(key []byte, data []byte) error {
+		if data == nil {
+			return nil
+		}
+		if value, err := decodeRecord(data); err == nil && value != nil {
+			keys = append(keys, string(key))
+		}
+		return nil
+	})
+	return keys, err // BoltDB iterates in byte order, as sort.Strings orders the memory keys
+}
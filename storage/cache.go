@@ -0,0 +1,133 @@
+package storage
+
+import (
+	"encoding/json"
+	"sync"
+	"time"
+)
+
+const maxCachedValues = 1000
+
+// Cached is a Store that answers reads from values it read before, so a shared Bolt file is not
+// opened for every lookup. A cached value is used only while the version of the store is the one
+// it was read at, so reads see every write committed before them, by this process or another.
+// Reads in View do not see a consistent snapshot, unlike in Update.
+type Cached struct {
+	store Versioned
+
+	mu         sync.Mutex
+	values     map[cacheKey]cachedValue
+	generation int // counts updates, so a read that overlapped one is not cached
+}
+
+type cacheKey struct {
+	bucket string
+	key    string
+}
+
+type cachedValue struct {
+	value   json.RawMessage // nil when the key was missing
+	version any
+}
+
+func NewCached(store Versioned) *Cached {
+	return &Cached{store: store, values: make(map[cacheKey]cachedValue)}
+}
+
+func (c *Cached) View(fn func(tx Tx) error) error {
+	return fn(&cachedTx{cache: c})
+}
+
+func (c *Cached) Update(fn func(tx Tx) error) error {
+	c.clear()
+	defer c.clear()
+	return c.store.Update(fn)
+}
+
+func (c *Cached) Cleanup() (int, error) {
+	return c.store.Cleanup()
+}
+
+func (c *Cached) Close() error {
+	c.clear()
+	return c.store.Close()
+}
+
+func (c *Cached) clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.values = make(map[cacheKey]cachedValue)
+	c.generation++
+}
+
+// get returns the raw value of key, reading it from the store when the store changed since the
+// cached copy was read
+func (c *Cached) get(bucket string, key string) (json.RawMessage, error) {
+	// The version is taken before the read, so a write in between makes the copy outdated
+	// rather than the copy hiding the write
+	version, settled, err := c.store.Version()
+	if err != nil {
+		return nil, err
+	}
+	k := cacheKey{bucket, key}
+	c.mu.Lock()
+	cached, ok := c.values[k]
+	generation := c.generation
+	c.mu.Unlock()
+	if ok && cached.version == version {
+		return cached.value, nil
+	}
+
+	var value json.RawMessage
+	err = c.store.View(func(tx Tx) error {
+		return tx.Get(bucket, key, &value)
+	})
+	if err != nil && err != ErrNotFound {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !settled || generation != c.generation {
+		return value, nil
+	}
+	if len(c.values) >= maxCachedValues {
+		c.values = make(map[cacheKey]cachedValue)
+	}
+	c.values[k] = cachedValue{value: value, version: version}
+	return value, nil
+}
+
+// cachedTx reads through the cache; each read that misses is a transaction of its own
+type cachedTx struct {
+	cache *Cached
+}
+
+func (tx *cachedTx) Get(bucket string, key string, value any) error {
+	raw, err := tx.cache.get(bucket, key)
+	if err != nil {
+		return err
+	}
+	if raw == nil {
+		return ErrNotFound
+	}
+	return json.Unmarshal(raw, value)
+}
+
+func (tx *cachedTx) Put(bucket string, key string, value any, ttl time.Duration) error {
+	return errReadOnly
+}
+
+func (tx *cachedTx) Delete(bucket string, key string) error {
+	return errReadOnly
+}
+
+func (tx *cachedTx) Keys(bucket string) ([]string, error) {
+	var keys []string
+	err := tx.cache.store.View(func(inner Tx) error {
+		var err error
+		keys, err = inner.Keys(bucket)
+		return err
+	})
+	return keys, err
+}
@@ -0,0 +1,172 @@
+package storage
+
+import (
+	"encoding/json"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Memory is a Store that keeps its values in memory, for a single process that does not need its
+// state after a restart
+type Memory struct {
+	mu      sync.RWMutex
+	buckets map[string]map[string][]byte
+	closed  bool
+}
+
+func NewMemory() *Memory {
+	return &Memory{buckets: make(map[string]map[string][]byte)}
+}
+
+func (m *Memory) View(fn func(tx Tx) error) error {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if m.closed {
+		return errClosed
+	}
+	return fn(&memoryTx{store: m})
+}
+
+func (m *Memory) Update(fn func(tx Tx) error) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.closed {
+		return errClosed
+	}
+
+	tx := &memoryTx{store: m, writable: true, changes: make(map[string]map[string][]byte)}
+	if err := fn(tx); err != nil {
+		return err
+	}
+	for bucket, changes := range tx.changes {
+		values := m.buckets[bucket]
+		if values == nil {
+			values = make(map[string][]byte)
+			m.buckets[bucket] = values
+		}
+		for key, data := range changes {
+			if data == nil {
+				delete(values, key)
+			} else {
+				values[key] = data
+			}
+		}
+		if len(values) == 0 {
+			delete(m.buckets, bucket)
+		}
+	}
+	return nil
+}
+
+func (m *Memory) Cleanup() (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	removed := 0
+	for bucket, values := range m.buckets {
+		for key, data := range values {
+			if expiredRecord(data, now) {
+				delete(values, key)
+				removed++
+			}
+		}
+		if len(values) == 0 {
+			delete(m.buckets, bucket)
+		}
+	}
+	return removed, nil
+}
+
+func (m *Memory) Close() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.closed = true
+	m.buckets = nil
+	return nil
+}
+
+// memoryTx stages the changes of an update until it commits; a nil value marks a deletion
+type memoryTx struct {
+	store    *Memory
+	writable bool
+	changes  map[string]map[string][]byte
+}
+
+// raw returns the encoded record of key as the transaction sees it
+func (tx *memoryTx) raw(bucket string, key string) ([]byte, bool) {
+	if data, ok := tx.changes[bucket][key]; ok {
+		return data, data != nil
+	}
+	data, ok := tx.store.buckets[bucket][key]
+	return data, ok
+}
+
+func (tx *memoryTx) Get(bucket string, key string, value any) error {
+	data, ok := tx.raw(bucket, key)
+	if !ok {
+		return ErrNotFound
+	}
+	return decodeValue(data, value)
+}
+
+func (tx *memoryTx) Put(bucket string, key string, value any, ttl time.Duration) error {
+	if !tx.writable {
+		return errReadOnly
+	}
+	data, err := encodeRecord(value, ttl)
+	if err != nil {
+		return err
+	}
+	tx.stage(bucket, key, data)
+	return nil
+}
+
+func (tx *memoryTx) Delete(bucket string, key string) error {
+	if !tx.writable {
+		return errReadOnly
+	}
+	tx.stage(bucket, key, nil)
+	return nil
+}
+
+func (tx *memoryTx) stage(bucket string, key string, data []byte) {
+	if tx.changes[bucket] == nil {
+		tx.changes[bucket] = make(map[string][]byte)
+	}
+	tx.changes[bucket][key] = data
+}
+
+func (tx *memoryTx) Keys(bucket string) ([]string, error) {
+	seen := make(map[string]bool)
+	for key := range tx.store.buckets[bucket] {
+		seen[key] = true
+	}
+	for key := range tx.changes[bucket] {
+		seen[key] = true
+	}
+
+	var keys []string
+	for key := range seen {
+		if data, ok := tx.raw(bucket, key); ok {
+			if value, err := decodeRecord(data); err == nil && value != nil {
+				keys = append(keys, key)
+			}
+		}
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
+
+// decodeValue decodes an encoded record into value, returning ErrNotFound when it has expired
+func decodeValue(data []byte, value any) error {
+	raw, err := decodeRecord(data)
+	if err != nil {
+		return err
+	}
+	if raw == nil {
+		return ErrNotFound
+	}
+	return json.Unmarshal(raw, value)
+}
@@ -0,0 +1,63 @@
+package storage
+
+import (
+	"fmt"
+	"sort"
+)
+
+// schemaVersionKey is where the meta bucket records the version of the last applied migration
+const schemaVersionKey = "schemaVersion"
+
+// Migration brings the stored state from the previous schema version to Version
+type Migration struct {
+	Version     int
+	Description string
+	Apply       func(tx Tx) error
+}
+
+// Migrate applies the migrations newer than the store's schema version, in order and in a single
+// transaction, so a failing migration leaves the store as it was. It returns the versions before
+// and after. A store written by a newer schema is refused rather than misread.
+func Migrate(store Store, migrations []Migration) (int, int, error) {
+	sorted := append([]Migration(nil), migrations...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Version < sorted[j].Version })
+	for i, migration := range sorted {
+		if migration.Version <= 0 || (i > 0 && migration.Version == sorted[i-1].Version) {
+			return 0, 0, fmt.Errorf("invalid migration version %d", migration.Version)
+		}
+	}
+	latest := 0
+	if len(sorted) > 0 {
+		latest = sorted[len(sorted)-1].Version
+	}
+
+	var from, to int
+	err := store.Update(func(tx Tx) error {
+		from, to = 0, 0
+		if err := tx.Get(metaBucket, schemaVersionKey, &from); err != nil && err != ErrNotFound {
+			return err
+		}
+		if from > latest {
+			return fmt.Errorf("state has schema version %d, newer than the latest known version %d", from, latest)
+		}
+
+		to = from
+		for _, migration := range sorted {
+			if migration.Version <= from {
+				continue
+			}
+			if err := migration.Apply(tx); err != nil {
+				return fmt.Errorf("migration %d (%s) failed: %v", migration.Version, migration.Description, err)
+			}
+			to = migration.Version
+		}
+		if to == from {
+			return nil
+		}
+		return tx.Put(metaBucket, schemaVersionKey, to, 0)
+	})
+	if err != nil {
+		return 0, 0, err
+	}
+	return from, to, nil
+}
@@ -0,0 +1,139 @@
+// Package storage keeps server state that outlives a request: values grouped in buckets, encoded
+// as JSON, optionally expiring after a time to live. State lives in memory or in a BoltDB file,
+// which several server instances can share.
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+)
+
+// MemoryLocation selects the in-memory store in Open
+const MemoryLocation = ":memory:"
+
+// metaBucket holds the storage package's own records, such as the schema version
+const metaBucket = "_meta"
+
+// ErrNotFound is returned by Get when a key does not exist or has expired
+var ErrNotFound = errors.New("not found")
+
+var (
+	errClosed   = errors.New("store is closed")
+	errReadOnly = errors.New("write in a read-only transaction")
+)
+
+// Store is a key-value store of JSON values grouped in buckets. Transactions passed to Update are
+// atomic: either every change is applied or, when the function returns an error, none is.
+type Store interface {
+	// View runs fn in a read-only transaction
+	View(fn func(tx Tx) error) error
+	// Update runs fn in a read-write transaction and commits it when fn returns nil
+	Update(fn func(tx Tx) error) error
+	// Cleanup removes expired values and returns how many it removed
+	Cleanup() (int, error)
+	Close() error
+}
+
+// Tx reads and writes values within a transaction. Expired values are treated as missing.
+type Tx interface {
+	// Get decodes the value of key into value, returning ErrNotFound if there is none
+	Get(bucket string, key string, value any) error
+	// Put stores value under key. A positive ttl makes it expire after that long.
+	Put(bucket string, key string, value any, ttl time.Duration) error
+	// Delete removes key; deleting a missing key is not an error
+	Delete(bucket string, key string) error
+	// Keys returns the keys of a bucket in sorted order
+	Keys(bucket string) ([]string, error)
+}
+
+// Versioned is a Store that can tell cheaply whether it was written to, so reads can be cached
+type Versioned interface {
+	Store
+	// Version returns a comparable value that changes with every committed write, by this
+	// process or another. settled is false while a further write might not change the version
+	// yet; values read then must not be cached.
+	Version() (version any, settled bool, err error)
+}
+
+// Open returns the in-memory store for MemoryLocation and the BoltDB store at path otherwise. A
+// shared BoltDB file is opened for each transaction only, so other processes can use it too, and
+// its reads are cached until the file changes.
+func Open(path string, shared bool) (Store, error) {
+	if path == MemoryLocation {
+		return NewMemory(), nil
+	}
+	store, err := OpenBolt(path, shared)
+	if err != nil || !shared {
+		return store, err
+	}
+	return NewCached(store), nil
+}
+
+// record is how a value is encoded in either store
+type record struct {
+	Value     json.RawMessage `json:"value"`
+	ExpiresAt *time.Time      `json:"expiresAt,omitempty"`
+}
+
+func encodeRecord(value any, ttl time.Duration) ([]byte, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	rec := record{Value: data}
+	if ttl > 0 {
+		expiresAt := time.Now().Add(ttl).UTC()
+		rec.ExpiresAt = &expiresAt
+	}
+	return json.Marshal(rec)
+}
+
+// decodeRecord returns the value of an encoded record, or nil when it has expired
+func decodeRecord(data []byte) (json.RawMessage, error) {
+	var rec record
+	if err := json.Unmarshal(data, &rec); err != nil {
+		return nil, fmt.Errorf("corrupt record: %v", err)
+	}
+	if rec.expired(time.Now()) {
+		return nil, nil
+	}
+	return rec.Value, nil
+}
+
+func (r record) expired(now time.Time) bool {
+	return r.ExpiresAt != nil && !now.Before(*r.ExpiresAt)
+}
+
+// expiredRecord reports whether an encoded record has expired; corrupt records count as expired
+// so that cleanup removes them
+func expiredRecord(data []byte, now time.Time) bool {
+	var rec record
+	if err := json.Unmarshal(data, &rec); err != nil {
+		return true
+	}
+	return rec.expired(now)
+}
+
+// CleanupEvery removes expired values from store at every interval until ctx is done
+func CleanupEvery(ctx context.Context, store Store, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			removed, err := store.Cleanup()
+			if err != nil {
+				log.Printf("State cleanup failed: %v", err)
+			} else if removed > 0 {
+				log.Printf("State cleanup removed %d expired values", removed)
+			}
+		}
+	}
+}
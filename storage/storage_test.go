@@ -0,0 +1,273 @@
+package storage
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type testValue struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+// forEachStore runs a test against every Store implementation
+func forEachStore(t *testing.T, test func(t *testing.T, store Store)) {
+	t.Run("memory", func(t *testing.T) {
+		test(t, NewMemory())
+	})
+	t.Run("cached", func(t *testing.T) {
+		store, err := OpenBolt(filepath.Join(t.TempDir(), "state.db"), true)
+		if err != nil {
+			t.Fatalf("Failed to open the store: %v", err)
+		}
+		defer store.Close()
+		test(t, NewCached(store))
+	})
+	for _, shared := range []bool{false, true} {
+		name := "bolt"
+		if shared {
+			name = "bolt shared"
+		}
+		t.Run(name, func(t *testing.T) {
+			store, err := OpenBolt(filepath.Join(t.TempDir(), "state.db"), shared)
+			if err != nil {
+				t.Fatalf("Failed to open the store: %v", err)
+			}
+			defer store.Close()
+			test(t, store)
+		})
+	}
+}
+
+func TestStore_PutGetDelete(t *testing.T) {
+	forEachStore(t, func(t *testing.T, store Store) {
+		err := store.Update(func(tx Tx) error {
+			if err := tx.Put("things", "b", testValue{Name: "bee", Count: 2}, 0); err != nil {
+				return err
+			}
+			return tx.Put("things", "a", testValue{Name: "ay", Count: 1}, 0)
+		})
+		if err != nil {
+			t.Fatalf("Update failed: %v", err)
+		}
+
+		err = store.View(func(tx Tx) error {
+			var value testValue
+			if err := tx.Get("things", "a", &value); err != nil || value != (testValue{Name: "ay", Count: 1}) {
+				t.Errorf("Expected the stored value, got %+v, %v", value, err)
+			}
+			if err := tx.Get("things", "c", &value); err != ErrNotFound {
+				t.Errorf("Expected ErrNotFound for a missing key, got %v", err)
+			}
+			if err := tx.Get("other", "a", &value); err != ErrNotFound {
+				t.Errorf("Expected ErrNotFound for a missing bucket, got %v", err)
+			}
+			if keys, _ := tx.Keys("things"); !reflect.DeepEqual(keys, []string{"a", "b"}) {
+				t.Errorf("Expected sorted keys, got %v", keys)
+			}
+			if err := tx.Put("things", "c", testValue{}, 0); err == nil {
+				t.Error("Expected a write in a read-only transaction to fail")
+			}
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("View failed: %v", err)
+		}
+
+		store.Update(func(tx Tx) error { return tx.Delete("things", "a") })
+		store.View(func(tx Tx) error {
+			if keys, _ := tx.Keys("things"); !reflect.DeepEqual(keys, []string{"b"}) {
+				t.Errorf("Expected the key to be deleted, got %v", keys)
+			}
+			return nil
+		})
+	})
+}
+
+func TestStore_UpdateRollsBack(t *testing.T) {
+	forEachStore(t, func(t *testing.T, store Store) {
+		failure := errors.New("failure")
+		err := store.Update(func(tx Tx) error {
+			tx.Put("things", "a", testValue{Name: "ay"}, 0)
+
+			// The transaction sees its own writes
+			var value testValue
+			if err := tx.Get("things", "a", &value); err != nil || value.Name != "ay" {
+				t.Errorf("Expected the uncommitted value, got %+v, %v", value, err)
+			}
+			return failure
+		})
+		if err != failure {
+			t.Fatalf("Expected the error of the function, got %v", err)
+		}
+
+		store.View(func(tx Tx) error {
+			if err := tx.Get("things", "a", &testValue{}); err != ErrNotFound {
+				t.Errorf("Expected nothing to be written, got %v", err)
+			}
+			return nil
+		})
+	})
+}
+
+func TestStore_TTL(t *testing.T) {
+	forEachStore(t, func(t *testing.T, store Store) {
+		store.Update(func(tx Tx) error {
+			tx.Put("things", "short", testValue{Name: "short"}, 50*time.Millisecond)
+			tx.Put("things", "long", testValue{Name: "long"}, time.Hour)
+			return tx.Put("things", "forever", testValue{Name: "forever"}, 0)
+		})
+		time.Sleep(100 * time.Millisecond)
+
+		store.View(func(tx Tx) error {
+			if err := tx.Get("things", "short", &testValue{}); err != ErrNotFound {
+				t.Errorf("Expected an expired value to be missing, got %v", err)
+			}
+			if keys, _ := tx.Keys("things"); !reflect.DeepEqual(keys, []string{"forever", "long"}) {
+				t.Errorf("Expected expired keys to be left out, got %v", keys)
+			}
+			return nil
+		})
+
+		if removed, err := store.Cleanup(); err != nil || removed != 1 {
+			t.Errorf("Expected one expired value to be removed, got %d, %v", removed, err)
+		}
+		if removed, _ := store.Cleanup(); removed != 0 {
+			t.Errorf("Expected nothing left to remove, got %d", removed)
+		}
+	})
+}
+
+func TestMigrate(t *testing.T) {
+	forEachStore(t, func(t *testing.T, store Store) {
+		var applied []int
+		migration := func(version int) Migration {
+			return Migration{Version: version, Description: "test", Apply: func(tx Tx) error {
+				applied = append(applied, version)
+				return tx.Put("things", "version", version, 0)
+			}}
+		}
+
+		from, to, err := Migrate(store, []Migration{migration(2), migration(1)})
+		if err != nil || from != 0 || to != 2 || !reflect.DeepEqual(applied, []int{1, 2}) {
+			t.Fatalf("Expected migrations 1 and 2 in order, got %d -> %d, %v, %v", from, to, applied, err)
+		}
+
+		// Only newer migrations run later
+		applied = nil
+		from, to, err = Migrate(store, []Migration{migration(1), migration(2), migration(3)})
+		if err != nil || from != 2 || to != 3 || !reflect.DeepEqual(applied, []int{3}) {
+			t.Errorf("Expected only migration 3, got %d -> %d, %v, %v", from, to, applied, err)
+		}
+
+		// A failing migration leaves the store as it was
+		failing := Migration{Version: 4, Description: "failing", Apply: func(tx Tx) error {
+			tx.Put("things", "version", 4, 0)
+			return errors.New("broken")
+		}}
+		if _, _, err := Migrate(store, []Migration{migration(3), failing}); err == nil {
+			t.Error("Expected the failing migration to be reported")
+		}
+		store.View(func(tx Tx) error {
+			var version int
+			if tx.Get("things", "version", &version); version != 3 {
+				t.Errorf("Expected the failed migration to be rolled back, got version %d", version)
+			}
+			return nil
+		})
+
+		// An older binary refuses state written by a newer one
+		if _, _, err := Migrate(store, []Migration{migration(1)}); err == nil {
+			t.Error("Expected a newer schema version to be refused")
+		}
+	})
+}
+
+func TestBolt_Persistent(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.db")
+	store, err := OpenBolt(path, false)
+	if err != nil {
+		t.Fatalf("Failed to open the store: %v", err)
+	}
+	store.Update(func(tx Tx) error { return tx.Put("things", "a", testValue{Name: "ay"}, 0) })
+
+	// The file is locked while an exclusive store has it open
+	if _, err := OpenBolt(path, false); err == nil {
+		t.Error("Expected a second exclusive open to fail")
+	}
+	store.Close()
+
+	// Shared stores see each other's writes
+	first, err := Open(path, true)
+	if err != nil {
+		t.Fatalf("Failed to reopen the store: %v", err)
+	}
+	defer first.Close()
+	second, _ := Open(path, true)
+	defer second.Close()
+
+	second.Update(func(tx Tx) error { return tx.Put("things", "b", testValue{Name: "bee"}, 0) })
+	first.View(func(tx Tx) error {
+		var value testValue
+		if err := tx.Get("things", "a", &value); err != nil || value.Name != "ay" {
+			t.Errorf("Expected the value written before the restart, got %+v, %v", value, err)
+		}
+		if err := tx.Get("things", "b", &value); err != nil || value.Name != "bee" {
+			t.Errorf("Expected the value written by the other store, got %+v, %v", value, err)
+		}
+		return nil
+	})
+}
+
+func TestCached(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.db")
+	first, err := Open(path, true)
+	if err != nil {
+		t.Fatalf("Failed to open the store: %v", err)
+	}
+	defer first.Close()
+	second, _ := Open(path, true)
+	defer second.Close()
+	if _, ok := first.(*Cached); !ok {
+		t.Fatalf("Expected a shared file to be cached, got %T", first)
+	}
+
+	get := func(store Store) string {
+		var value testValue
+		store.View(func(tx Tx) error { return tx.Get("things", "a", &value) })
+		return value.Name
+	}
+
+	first.Update(func(tx Tx) error { return tx.Put("things", "a", testValue{Name: "ay"}, 0) })
+	if name := get(second); name != "ay" {
+		t.Fatalf("Expected the value, got %q", name)
+	}
+
+	// A process reads its own writes and those of others right away
+	second.Update(func(tx Tx) error { return tx.Put("things", "a", testValue{Name: "bee"}, 0) })
+	if name := get(second); name != "bee" {
+		t.Errorf("Expected the own write, got %q", name)
+	}
+	if name := get(first); name != "bee" {
+		t.Errorf("Expected the other write, got %q", name)
+	}
+
+	// Values read from a settled file are cached until the file changes
+	settled := time.Now().Add(-time.Minute)
+	os.Chtimes(path, settled, settled)
+	if name := get(first); name != "bee" {
+		t.Fatalf("Expected the value, got %q", name)
+	}
+	second.Update(func(tx Tx) error { return tx.Put("things", "a", testValue{Name: "sea"}, 0) })
+	if name := get(first); name != "sea" {
+		t.Errorf("Expected the other write once the file changed, got %q", name)
+	}
+	os.Chtimes(path, settled, settled)
+	if name := get(first); name != "bee" {
+		t.Errorf("Expected the cached value while the file looks unchanged, got %q", name)
+	}
+}
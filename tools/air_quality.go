@@ -0,0 +1,272 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+type AirQualityTool struct {
+	Tool    mcp.Tool
+	Handler func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error)
+}
+
+// AirPollutionData is the response of OpenWeatherMap's current and forecast air pollution endpoints
+type AirPollutionData struct {
+	Coord struct {
+		Lon float64 `json:"lon"`
+		Lat float64 `json:"lat"`
+	} `json:"coord"`
+	List []AirPollutionItem `json:"list"`
+}
+
+type AirPollutionItem struct {
+	Main struct {
+		AQI int `json:"aqi"`
+	} `json:"main"`
+	Components AirPollutionComponents `json:"components"`
+	Dt         int64                  `json:"dt"`
+}
+
+// AirPollutionComponents are pollutant concentrations in µg/m³
+type AirPollutionComponents struct {
+	CO   float64 `json:"co"`
+	NO   float64 `json:"no"`
+	NO2  float64 `json:"no2"`
+	O3   float64 `json:"o3"`
+	SO2  float64 `json:"so2"`
+	PM25 float64 `json:"pm2_5"`
+	PM10 float64 `json:"pm10"`
+	NH3  float64 `json:"nh3"`
+}
+
+// pollutant describes one reported pollutant and the concentrations (µg/m³) at which
+// OpenWeatherMap's qualitative index moves from Good to Fair, Moderate, Poor and Very Poor
+type pollutant struct {
+	Code  string
+	Name  string
+	Bands [4]float64
+	Value func(c AirPollutionComponents) float64
+}
+
+var pollutants = []pollutant{
+	{"pm2_5", "PM2.5", [4]float64{10, 25, 50, 75}, func(c AirPollutionComponents) float64 { return c.PM25 }},
+	{"pm10", "PM10", [4]float64{20, 50, 100, 200}, func(c AirPollutionComponents) float64 { return c.PM10 }},
+	{"o3", "O3", [4]float64{60, 100, 140, 180}, func(c AirPollutionComponents) float64 { return c.O3 }},
+	{"no2", "NO2", [4]float64{40, 70, 150, 200}, func(c AirPollutionComponents) float64 { return c.NO2 }},
+	{"so2", "SO2", [4]float64{20, 80, 250, 350}, func(c AirPollutionComponents) float64 { return c.SO2 }},
+	{"co", "CO", [4]float64{4400, 9400, 12400, 15400}, func(c AirPollutionComponents) float64 { return c.CO }},
+}
+
+// Level rates a concentration on the 1 (Good) to 5 (Very Poor) scale
+func (p pollutant) Level(value float64) int {
+	for i, limit := range p.Bands {
+		if value < limit {
+			return i + 1
+		}
+	}
+	return len(p.Bands) + 1
+}
+
+// aqiCategories and aqiGuidance are indexed by OpenWeatherMap's 1-5 air quality index
+var aqiCategories = [...]string{"", "Good", "Fair", "Moderate", "Poor", "Very Poor"}
+
+var aqiGuidance = [...]string{
+	"",
+	"Air quality is good. Enjoy outdoor activities.",
+	"Air quality is acceptable. Unusually sensitive people should consider limiting prolonged outdoor exertion.",
+	"Sensitive groups (children, older adults and people with heart or lung disease) should reduce prolonged or heavy outdoor exertion.",
+	"Everyone may begin to feel health effects: sensitive groups should avoid outdoor exertion and everyone else should limit it.",
+	"Health alert: everyone should avoid outdoor exertion and sensitive groups should stay indoors.",
+}
+
+// aqiLevel clamps an index into the 1-5 range so it can index the category tables
+func aqiLevel(aqi int) int {
+	return min(max(aqi, 1), len(aqiCategories)-1)
+}
+
+// mainPollutant returns the pollutant with the worst level, preferring particulates on ties
+func mainPollutant(components AirPollutionComponents) pollutant {
+	main := pollutants[0]
+	for _, p := range pollutants[1:] {
+		if p.Level(p.Value(components)) > main.Level(main.Value(components)) {
+			main = p
+		}
+	}
+	return main
+}
+
+func NewAirQualityTool() *AirQualityTool {
+	return &AirQualityTool{
+		Tool:    airQualityTool(),
+		Handler: airQualityToolHandler,
+	}
+}
+
+func airQualityTool() mcp.Tool {
+	return mcp.NewTool("get_air_quality",
+		mcp.WithDescription("Get current air quality for a location: the air quality index with its category, PM2.5, PM10, O3, NO2, SO2 and CO concentrations, and health guidance. Uses client's IP location by default, or accepts a custom location parameter (city name or 'lat,lon' coordinates)"),
+		readOnlyTool("Air Quality", true),
+		mcp.WithString("location",
+			mcp.Description("Location to get air quality for (optional). Can be city name (e.g., 'London' or 'New York,US'), coordinates (e.g., '40.7128,-74.0060') or a saved location (e.g., '@home'). Uses client IP location if not provided."),
+		),
+		mcp.WithBoolean("forecast",
+			mcp.Description("Also summarize the air quality forecast for the next days (optional, default false)"),
+		),
+		withLanguage(),
+		withFormat(),
+		mcp.WithOutputSchema[AirQualityReport](),
+	)
+}
+
+func airQualityToolHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	params, err := resolveWeatherRequest(ctx, request)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	current, err := fetchAirPollution(ctx, buildOpenWeatherMapURL("air_pollution", params.Coordinates(), params.APIKey, unitsMetric))
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	if len(current.List) == 0 {
+		return mcp.NewToolResultError("no air quality data available for this location"), nil
+	}
+
+	var forecast *AirPollutionData
+	if request.GetBool("forecast", false) {
+		data, err := fetchAirPollution(ctx, buildOpenWeatherMapURL("air_pollution/forecast", params.Coordinates(), params.APIKey, unitsMetric))
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		forecast = &data
+	}
+
+	loc := airQualityTimezone(ctx, params)
+	result := FormatAirQualityAsMarkdown(current, forecast, params.LocationName, loc, params.Lang) + formatAlternativesNote(params.Resolved)
+	return renderResult(params.Format, result, NewAirQualityReport(current, forecast, params.LocationName, loc)), nil
+}
+
+// airQualityTimezone returns the zone local times are shown in. The air pollution API reports UTC
+// timestamps only, so locations without a known timezone, such as coordinates, take the UTC offset
+// OpenWeatherMap reports with the current weather, as the weather tool does. UTC is the last resort.
+func airQualityTimezone(ctx context.Context, params *weatherRequest) *time.Location {
+	if params.Resolved.Timezone != "" {
+		if tz, err := time.LoadLocation(params.Resolved.Timezone); err == nil {
+			return tz
+		}
+	}
+
+	body, err := fetchWeatherData(ctx, buildOpenWeatherMapURL("weather", params.Coordinates(), params.APIKey, unitsMetric))
+	if err != nil {
+		return time.UTC
+	}
+	var weather WeatherData
+	if err := json.Unmarshal(body, &weather); err != nil {
+		return time.UTC
+	}
+	return offsetLocation(weather.Timezone)
+}
+
+func fetchAirPollution(ctx context.Context, url string) (AirPollutionData, error) {
+	var data AirPollutionData
+	body, err := fetchWeatherData(ctx, url)
+	if err != nil {
+		return data, err
+	}
+	if err := json.Unmarshal(body, &data); err != nil {
+		return data, fmt.Errorf("Failed to parse air pollution response: %v", err)
+	}
+	return data, nil
+}
+
+// airQualityDay summarizes the forecast hours of one local day
+type airQualityDay struct {
+	Date   time.Time
+	MinAQI int
+	MaxAQI int
+	Main   pollutant
+	Peak   float64
+}
+
+// summarizeAirQualityForecast groups forecast hours by local day. The main pollutant of a day is the
+// one driving its worst hour, and Peak is that pollutant's highest concentration over the day.
+func summarizeAirQualityForecast(items []AirPollutionItem, loc *time.Location) []airQualityDay {
+	var dates []time.Time
+	var groups [][]AirPollutionItem
+	for _, item := range items {
+		timestamp := time.Unix(item.Dt, 0).In(loc)
+		date := time.Date(timestamp.Year(), timestamp.Month(), timestamp.Day(), 0, 0, 0, 0, loc)
+
+		if n := len(dates); n > 0 && dates[n-1].Equal(date) {
+			groups[n-1] = append(groups[n-1], item)
+			continue
+		}
+		dates = append(dates, date)
+		groups = append(groups, []AirPollutionItem{item})
+	}
+
+	days := make([]airQualityDay, 0, len(groups))
+	for i, group := range groups {
+		day := airQualityDay{Date: dates[i], MinAQI: group[0].Main.AQI, MaxAQI: group[0].Main.AQI}
+		worst := group[0]
+		for _, item := range group {
+			day.MinAQI = min(day.MinAQI, item.Main.AQI)
+			day.MaxAQI = max(day.MaxAQI, item.Main.AQI)
+			if item.Main.AQI > worst.Main.AQI {
+				worst = item
+			}
+		}
+
+		day.Main = mainPollutant(worst.Components)
+		for _, item := range group {
+			day.Peak = max(day.Peak, day.Main.Value(item.Components))
+		}
+		days = append(days, day)
+	}
+
+	return days
+}
+
+func FormatAirQualityAsMarkdown(current AirPollutionData, forecast *AirPollutionData, locationName string, loc *time.Location, lang string) string {
+	var builder strings.Builder
+	l := newLocalizer(lang)
+	now := current.List[0]
+	level := aqiLevel(now.Main.AQI)
+
+	builder.WriteString(fmt.Sprintf("# %s: %s\n\n", l.T("Air Quality"), locationName))
+
+	builder.WriteString(fmt.Sprintf("## %s\n", l.T("Current Air Quality")))
+	builder.WriteString(fmt.Sprintf("- **%s:** %s\n", l.T("Air Quality Index"), l.T("%d of 5 (%s)", level, l.T(aqiCategories[level]))))
+	builder.WriteString(fmt.Sprintf("- **%s:** %s\n", l.T("Main Pollutant"), mainPollutant(now.Components).Name))
+	builder.WriteString(fmt.Sprintf("- **%s:** %s\n", l.T("Measured"), l.DateTime(time.Unix(now.Dt, 0).In(loc))))
+	builder.WriteString(fmt.Sprintf("- **%s:** %s\n", l.T("Health Guidance"), l.T(aqiGuidance[level])))
+
+	builder.WriteString(fmt.Sprintf("\n## %s\n", l.T("Pollutants (µg/m³)")))
+	for _, p := range pollutants {
+		value := p.Value(now.Components)
+		builder.WriteString(fmt.Sprintf("- **%s:** %s (%s)\n", p.Name, l.Number(value, 1), l.T(aqiCategories[p.Level(value)])))
+	}
+
+	if forecast != nil && len(forecast.List) > 0 {
+		builder.WriteString(fmt.Sprintf("\n## %s\n\n", l.T("Air Quality Forecast")))
+		for _, day := range summarizeAirQualityForecast(forecast.List, loc) {
+			level := aqiLevel(day.MaxAQI)
+			builder.WriteString(fmt.Sprintf("**%s**: %s, %s\n",
+				l.Day(day.Date),
+				l.T("%d of 5 (%s)", level, l.T(aqiCategories[level])),
+				l.T("%s up to %s µg/m³", day.Main.Name, l.Number(day.Peak, 1)),
+			))
+		}
+	}
+
+	builder.WriteString(fmt.Sprintf("\n## %s\n", l.T("Location")))
+	builder.WriteString(fmt.Sprintf("- **%s:** %.4f, %.4f\n", l.T("Coordinates"), current.Coord.Lat, current.Coord.Lon))
+	builder.WriteString(fmt.Sprintf("- **%s:** %s\n", l.T("Timezone"), loc.String()))
+
+	return builder.String()
+}
@@ -0,0 +1,115 @@
+package tools
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestPollutantLevel(t *testing.T) {
+	pm25 := pollutants[0]
+
+	testCases := []struct {
+		value    float64
+		expected int
+	}{
+		{0, 1},
+		{9.9, 1},
+		{10, 2},
+		{30, 3},
+		{60, 4},
+		{75, 5},
+		{300, 5},
+	}
+
+	for _, tc := range testCases {
+		if got := pm25.Level(tc.value); got != tc.expected {
+			t.Errorf("PM2.5 level of %.1f = %d, expected %d", tc.value, got, tc.expected)
+		}
+	}
+}
+
+func TestAirQualityTool(t *testing.T) {
+	os.Setenv("OPENWEATHER_API_KEY", "test_api_key")
+	defer os.Unsetenv("OPENWEATHER_API_KEY")
+	newOpenWeatherMapTestServer(t)
+
+	airQualityTool := NewAirQualityTool()
+
+	t.Run("Current", func(t *testing.T) {
+		content, isError := callToolText(t, airQualityTool.Handler, map[string]any{"location": "39.4697,-0.3763"})
+		if isError {
+			t.Fatalf("Expected success, got error: %s", content)
+		}
+
+		expected := []string{
+			"# Air Quality: 39.4697,-0.3763",
+			"**Air Quality Index:** 2 of 5 (Fair)",
+			"**Main Pollutant:** O3",
+			"**Measured:** Tue Sep 2 3:00 PM", // coordinates take the UTC offset of the current weather
+			"**Health Guidance:** Air quality is acceptable.",
+			"**PM2.5:** 8.1 (Good)",
+			"**O3:** 71.3 (Fair)",
+			"**CO:** 230.3 (Good)",
+			"**Timezone:** UTC+02:00",
+		}
+		for _, text := range expected {
+			if !strings.Contains(content, text) {
+				t.Errorf("Expected %q in output:\n%s", text, content)
+			}
+		}
+		if strings.Contains(content, "Air Quality Forecast") {
+			t.Errorf("Forecast should only be included on request:\n%s", content)
+		}
+	})
+
+	t.Run("Forecast", func(t *testing.T) {
+		content, isError := callToolText(t, airQualityTool.Handler, map[string]any{"location": "39.4697,-0.3763", "forecast": true})
+		if isError {
+			t.Fatalf("Expected success, got error: %s", content)
+		}
+
+		if !strings.Contains(content, "## Air Quality Forecast") {
+			t.Errorf("Missing forecast section:\n%s", content)
+		}
+		if !strings.Contains(content, "**Wed Sep 3**: 3 of 5 (Moderate), PM2.5 up to 30.4 µg/m³") {
+			t.Errorf("Expected Wednesday's particulate episode:\n%s", content)
+		}
+		if got := strings.Count(content, " of 5 ("); got != 5 {
+			t.Errorf("Expected current index and 4 forecast days, got %d:\n%s", got, content)
+		}
+	})
+
+	t.Run("Language", func(t *testing.T) {
+		content, isError := callToolText(t, airQualityTool.Handler, map[string]any{"location": "39.4697,-0.3763", "lang": "fr"})
+		if isError {
+			t.Fatalf("Expected success, got error: %s", content)
+		}
+
+		if !strings.Contains(content, "# Qualité de l'air:") || !strings.Contains(content, "2 sur 5 (Correcte)") || !strings.Contains(content, "**PM2.5:** 8,1 (Bonne)") {
+			t.Errorf("Expected French output:\n%s", content)
+		}
+	})
+
+	t.Run("Structured", func(t *testing.T) {
+		content := callToolStructured(t, airQualityTool.Tool, airQualityTool.Handler, map[string]any{"location": "39.4697,-0.3763", "forecast": true})
+		pollutantReadings, _ := content["pollutants"].([]any)
+		forecastDays, _ := content["forecast"].([]any)
+		if content["aqi"] != 2.0 || content["category"] != "Fair" || content["mainPollutant"] != "O3" || len(pollutantReadings) != 6 {
+			t.Errorf("Unexpected air quality structured content: %v", content)
+		}
+		if len(forecastDays) != 4 || forecastDays[1].(map[string]any)["aqiMax"] != 3.0 {
+			t.Errorf("Unexpected air quality forecast: %v", forecastDays)
+		}
+	})
+
+	t.Run("MissingAPIKey", func(t *testing.T) {
+		os.Unsetenv("OPENWEATHER_API_KEY")
+		defer os.Setenv("OPENWEATHER_API_KEY", "test_api_key")
+
+		content, isError := callToolText(t, airQualityTool.Handler, map[string]any{"location": "39.4697,-0.3763"})
+		if !isError || !strings.Contains(content, "OPENWEATHER_API_KEY") {
+			t.Errorf("Expected API key error, got: %s", content)
+		}
+	})
+}
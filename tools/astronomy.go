@@ -0,0 +1,513 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// astronomyNow is the clock 'today' and 'tomorrow' are resolved against
+var astronomyNow = time.Now
+
+// The formulas lose accuracy far from the J2000 epoch, so dates are limited to two centuries
+const (
+	earliestAstronomyYear = 1900
+	latestAstronomyYear   = 2100
+)
+
+// Sun altitudes in degrees for sunrise and sunset (the disc's upper edge, with refraction) and the
+// three twilights
+const (
+	sunriseAltitude              = -0.833
+	civilTwilightAltitude        = -6.0
+	nauticalTwilightAltitude     = -12.0
+	astronomicalTwilightAltitude = -18.0
+)
+
+const (
+	radians        = math.Pi / 180
+	dayMillis      = 24 * 60 * 60 * 1000
+	julian1970     = 2440588.0
+	julian2000     = 2451545.0
+	obliquity      = radians * 23.4397
+	synodicMonth   = 29.530588853
+	sunDistanceKm  = 149598000.0
+	moonHorizonAlt = 0.133 * radians // moon's parallax less semi-diameter and refraction
+)
+
+// moonPhases are the eight named phases, each centered on its phase fraction (0 new, 0.5 full)
+var moonPhases = [...]string{
+	"New Moon", "Waxing Crescent", "First Quarter", "Waxing Gibbous",
+	"Full Moon", "Waning Gibbous", "Last Quarter", "Waning Crescent",
+}
+
+type AstronomyTool struct {
+	Tool    mcp.Tool
+	Handler func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error)
+}
+
+// horizonCrossing is when the sun passes an altitude on a given day. When it doesn't, AlwaysAbove
+// or AlwaysBelow says which side it stays on.
+type horizonCrossing struct {
+	Rise        time.Time
+	Set         time.Time
+	AlwaysAbove bool
+	AlwaysBelow bool
+}
+
+// sunDay holds the sun's events for one local day
+type sunDay struct {
+	Noon         time.Time
+	Horizon      horizonCrossing
+	Civil        horizonCrossing
+	Nautical     horizonCrossing
+	Astronomical horizonCrossing
+}
+
+// DayLength is the time between sunrise and sunset, a full day or none when the sun doesn't set or rise
+func (s sunDay) DayLength() time.Duration {
+	switch {
+	case s.Horizon.AlwaysAbove:
+		return 24 * time.Hour
+	case s.Horizon.AlwaysBelow:
+		return 0
+	default:
+		return s.Horizon.Set.Sub(s.Horizon.Rise)
+	}
+}
+
+// moonDay holds the moon's phase at local noon and its rise and set on one local day. Rise and Set
+// are zero when the moon doesn't rise or set that day.
+type moonDay struct {
+	Phase        float64
+	Illumination float64
+	Rise         time.Time
+	Set          time.Time
+	AlwaysUp     bool
+	AlwaysDown   bool
+}
+
+// PhaseName names the phase, e.g. "Waxing Gibbous"
+func (m moonDay) PhaseName() string {
+	return moonPhases[int(math.Floor(m.Phase*8+0.5))%8]
+}
+
+// Age is the number of days since the last new moon
+func (m moonDay) Age() float64 {
+	return m.Phase * synodicMonth
+}
+
+func NewAstronomyTool() *AstronomyTool {
+	return &AstronomyTool{
+		Tool:    astronomyTool(),
+		Handler: astronomyToolHandler,
+	}
+}
+
+func astronomyTool() mcp.Tool {
+	return mcp.NewTool("get_astronomy",
+		mcp.WithDescription("Get sunrise, sunset, solar noon, day length, civil, nautical and astronomical twilight, moon phase, illumination, moonrise and moonset for a location and date. Uses client's IP location by default, or accepts a custom location parameter (city name or 'lat,lon' coordinates). Computed locally, no API key required."),
+		readOnlyTool("Sun and Moon", true),
+		mcp.WithString("location",
+			mcp.Description("Location to compute for (optional). Can be city name (e.g., 'London' or 'New York,US'), coordinates (e.g., '40.7128,-74.0060') or a saved location (e.g., '@home'). Uses client IP location if not provided."),
+		),
+		mcp.WithString("date",
+			mcp.Description("Day to compute (optional): 'today' (default), 'tomorrow', 'yesterday' or a date (YYYY-MM-DD) between 1900 and 2100, in the location's local time"),
+		),
+		mcp.WithString("timezone",
+			mcp.Description("IANA timezone for local times (optional, e.g., 'Europe/Madrid'). Defaults to the location's timezone when known, otherwise one estimated from longitude."),
+		),
+		withLanguage(),
+		withFormat(),
+		mcp.WithOutputSchema[AstronomyReport](),
+	)
+}
+
+func astronomyToolHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	format, err := parseFormat(request)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	resolved, err := resolveLocationInteractively(ctx, request.GetString("location", ""))
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	locationName := resolved.Query
+	if locationName == "" {
+		locationName = resolved.Name
+	}
+
+	lang, err := resolveLanguage(ctx, request, resolved)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	loc, estimated, err := astronomyTimezone(request.GetString("timezone", ""), resolved)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	date, err := parseAstronomyDate(request.GetString("date", ""), astronomyNow().In(loc))
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	sun := computeSunDay(date, resolved.Lat, resolved.Lon)
+	moon := computeMoonDay(date, resolved.Lat, resolved.Lon)
+	location := ReportLocation{
+		Name:      locationName,
+		Country:   resolved.Country,
+		Lat:       resolved.Lat,
+		Lon:       resolved.Lon,
+		Timezone:  loc.String(),
+		Requested: request.GetString("location", ""),
+	}
+
+	result := FormatAstronomyAsMarkdown(location, date, sun, moon, estimated, lang) + formatAlternativesNote(resolved)
+	return renderResult(format, result, NewAstronomyReport(location, date, sun, moon)), nil
+}
+
+// astronomyTimezone picks the zone local times are shown in: the timezone argument, the resolved
+// location's zone, or a whole-hour offset estimated from longitude, which it reports as estimated
+func astronomyTimezone(name string, resolved *ResolvedLocation) (*time.Location, bool, error) {
+	if name = strings.TrimSpace(name); name != "" {
+		loc, err := time.LoadLocation(name)
+		if err != nil {
+			return nil, false, fmt.Errorf("invalid timezone %q: use an IANA name such as 'Europe/Madrid'", name)
+		}
+		return loc, false, nil
+	}
+
+	if resolved.Timezone != "" {
+		if loc, err := time.LoadLocation(resolved.Timezone); err == nil {
+			return loc, false, nil
+		}
+	}
+
+	return offsetLocation(int(math.Round(resolved.Lon/15)) * 3600), true, nil
+}
+
+// parseAstronomyDate resolves the date argument to local midnight of the day, relative to today
+func parseAstronomyDate(value string, today time.Time) (time.Time, error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	loc := today.Location()
+	midnight := time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, loc)
+
+	switch value {
+	case "", "today":
+		return midnight, nil
+	case "tomorrow":
+		return midnight.AddDate(0, 0, 1), nil
+	case "yesterday":
+		return midnight.AddDate(0, 0, -1), nil
+	}
+
+	date, err := time.ParseInLocation(time.DateOnly, value, loc)
+	if err != nil {
+		return date, fmt.Errorf("invalid date %q: use 'today', 'tomorrow', 'yesterday' or a date (YYYY-MM-DD)", value)
+	}
+	if date.Year() < earliestAstronomyYear || date.Year() > latestAstronomyYear {
+		return date, fmt.Errorf("date %s is out of range: astronomy is computed for %d to %d", value, earliestAstronomyYear, latestAstronomyYear)
+	}
+	return date, nil
+}
+
+// The sun and moon positions follow the low-precision formulas from Astronomy Answers and Jean
+// Meeus' "Astronomical Algorithms", as popularized by SunCalc. Times are good to a minute or two
+// for the sun and a few minutes for the moon.
+
+func toJulian(t time.Time) float64 {
+	return float64(t.UnixMilli())/dayMillis - 0.5 + julian1970
+}
+
+func fromJulian(j float64) time.Time {
+	return time.UnixMilli(int64(math.Round((j + 0.5 - julian1970) * dayMillis)))
+}
+
+// daysSinceJ2000 counts days from the J2000 epoch
+func daysSinceJ2000(t time.Time) float64 {
+	return toJulian(t) - julian2000
+}
+
+func rightAscension(l, b float64) float64 {
+	return math.Atan2(math.Sin(l)*math.Cos(obliquity)-math.Tan(b)*math.Sin(obliquity), math.Cos(l))
+}
+
+func declination(l, b float64) float64 {
+	return math.Asin(math.Sin(b)*math.Cos(obliquity) + math.Cos(b)*math.Sin(obliquity)*math.Sin(l))
+}
+
+func altitude(hourAngle, phi, dec float64) float64 {
+	return math.Asin(math.Sin(phi)*math.Sin(dec) + math.Cos(phi)*math.Cos(dec)*math.Cos(hourAngle))
+}
+
+func siderealTime(d, lw float64) float64 {
+	return radians*(280.16+360.9856235*d) - lw
+}
+
+// atmosphericRefraction raises an altitude (radians) by the refraction near the horizon
+func atmosphericRefraction(h float64) float64 {
+	h = max(h, 0)
+	return 0.0002967 / math.Tan(h+0.00312536/(h+0.08901179))
+}
+
+func solarMeanAnomaly(d float64) float64 {
+	return radians * (357.5291 + 0.98560028*d)
+}
+
+func eclipticLongitude(m float64) float64 {
+	center := radians * (1.9148*math.Sin(m) + 0.02*math.Sin(2*m) + 0.0003*math.Sin(3*m))
+	perihelion := radians * 102.9372
+	return m + center + perihelion + math.Pi
+}
+
+// sunCoords returns the sun's right ascension and declination
+func sunCoords(d float64) (float64, float64) {
+	l := eclipticLongitude(solarMeanAnomaly(d))
+	return rightAscension(l, 0), declination(l, 0)
+}
+
+// computeSunDay finds solar noon and the times the sun crosses the horizon and twilight altitudes
+// on the local day starting at midnight
+func computeSunDay(midnight time.Time, lat, lon float64) sunDay {
+	const j0 = 0.0009
+
+	loc := midnight.Location()
+	lw := -radians * lon
+	phi := radians * lat
+
+	// The solar transit nearest local noon, and the sun's position then
+	d := daysSinceJ2000(midnight.Add(12 * time.Hour))
+	cycle := math.Round(d - j0 - lw/(2*math.Pi))
+	transitApprox := j0 + lw/(2*math.Pi) + cycle
+	m := solarMeanAnomaly(transitApprox)
+	l := eclipticLongitude(m)
+	dec := declination(l, 0)
+	transit := julian2000 + transitApprox + 0.0053*math.Sin(m) - 0.0069*math.Sin(2*l)
+
+	crossing := func(altitudeDeg float64) horizonCrossing {
+		cosHourAngle := (math.Sin(altitudeDeg*radians) - math.Sin(phi)*math.Sin(dec)) / (math.Cos(phi) * math.Cos(dec))
+		switch {
+		case cosHourAngle < -1:
+			return horizonCrossing{AlwaysAbove: true}
+		case cosHourAngle > 1:
+			return horizonCrossing{AlwaysBelow: true}
+		}
+		hourAngle := math.Acos(cosHourAngle)
+		setApprox := j0 + (hourAngle+lw)/(2*math.Pi) + cycle
+		set := julian2000 + setApprox + 0.0053*math.Sin(m) - 0.0069*math.Sin(2*l)
+		rise := transit - (set - transit)
+		return horizonCrossing{Rise: fromJulian(rise).In(loc), Set: fromJulian(set).In(loc)}
+	}
+
+	return sunDay{
+		Noon:         fromJulian(transit).In(loc),
+		Horizon:      crossing(sunriseAltitude),
+		Civil:        crossing(civilTwilightAltitude),
+		Nautical:     crossing(nauticalTwilightAltitude),
+		Astronomical: crossing(astronomicalTwilightAltitude),
+	}
+}
+
+// moonCoords returns the moon's right ascension, declination and distance in km
+func moonCoords(d float64) (float64, float64, float64) {
+	meanLongitude := radians * (218.316 + 13.176396*d)
+	meanAnomaly := radians * (134.963 + 13.064993*d)
+	latitudeArgument := radians * (93.272 + 13.229350*d)
+
+	l := meanLongitude + radians*6.289*math.Sin(meanAnomaly)
+	b := radians * 5.128 * math.Sin(latitudeArgument)
+	distance := 385001 - 20905*math.Cos(meanAnomaly)
+
+	return rightAscension(l, b), declination(l, b), distance
+}
+
+// moonAltitude is the moon's apparent altitude in radians, with refraction
+func moonAltitude(t time.Time, lat, lon float64) float64 {
+	d := daysSinceJ2000(t)
+	ra, dec, _ := moonCoords(d)
+	h := altitude(siderealTime(d, -radians*lon)-ra, radians*lat, dec)
+	return h + atmosphericRefraction(h)
+}
+
+// moonIllumination returns the moon's phase (0 new, 0.25 first quarter, 0.5 full, 0.75 last
+// quarter) and illuminated fraction at a time
+func moonIllumination(t time.Time) (float64, float64) {
+	d := daysSinceJ2000(t)
+	sunRA, sunDec := sunCoords(d)
+	moonRA, moonDec, moonDistance := moonCoords(d)
+
+	elongation := math.Acos(math.Sin(sunDec)*math.Sin(moonDec) + math.Cos(sunDec)*math.Cos(moonDec)*math.Cos(sunRA-moonRA))
+	inclination := math.Atan2(sunDistanceKm*math.Sin(elongation), moonDistance-sunDistanceKm*math.Cos(elongation))
+	angle := math.Atan2(math.Cos(sunDec)*math.Sin(sunRA-moonRA),
+		math.Sin(sunDec)*math.Cos(moonDec)-math.Cos(sunDec)*math.Sin(moonDec)*math.Cos(sunRA-moonRA))
+
+	sign := 1.0
+	if angle < 0 {
+		sign = -1
+	}
+	return 0.5 + 0.5*inclination*sign/math.Pi, (1 + math.Cos(inclination)) / 2
+}
+
+// computeMoonDay finds the moon's phase at local noon and its rise and set during the local day,
+// fitting a parabola through the moon's altitude every two hours to find horizon crossings
+func computeMoonDay(midnight time.Time, lat, lon float64) moonDay {
+	var moon moonDay
+	moon.Phase, moon.Illumination = moonIllumination(midnight.Add(12 * time.Hour))
+
+	altitudeAt := func(hours float64) float64 {
+		return moonAltitude(midnight.Add(time.Duration(hours*float64(time.Hour))), lat, lon) - moonHorizonAlt
+	}
+
+	var rise, set, extreme float64
+	h0 := altitudeAt(0)
+	for i := 1.0; i <= 24; i += 2 {
+		h1, h2 := altitudeAt(i), altitudeAt(i+1)
+
+		a := (h0+h2)/2 - h1
+		b := (h2 - h0) / 2
+		xe := -b / (2 * a)
+		extreme = (a*xe+b)*xe + h1
+		discriminant := b*b - 4*a*h1
+
+		roots := 0
+		var x1, x2 float64
+		if discriminant >= 0 {
+			dx := math.Sqrt(discriminant) / (math.Abs(a) * 2)
+			x1, x2 = xe-dx, xe+dx
+			if math.Abs(x1) <= 1 {
+				roots++
+			}
+			if math.Abs(x2) <= 1 {
+				roots++
+			}
+			if x1 < -1 {
+				x1 = x2
+			}
+		}
+
+		switch roots {
+		case 1:
+			if h0 < 0 {
+				rise = i + x1
+			} else {
+				set = i + x1
+			}
+		case 2:
+			if extreme < 0 {
+				rise, set = i+x2, i+x1
+			} else {
+				rise, set = i+x1, i+x2
+			}
+		}
+
+		if rise != 0 && set != 0 {
+			break
+		}
+		h0 = h2
+	}
+
+	at := func(hours float64) time.Time {
+		return midnight.Add(time.Duration(hours * float64(time.Hour)))
+	}
+	if rise != 0 {
+		moon.Rise = at(rise)
+	}
+	if set != 0 {
+		moon.Set = at(set)
+	}
+	if rise == 0 && set == 0 {
+		moon.AlwaysUp = extreme > 0
+		moon.AlwaysDown = !moon.AlwaysUp
+	}
+
+	return moon
+}
+
+// FormatAstronomyAsMarkdown renders the sun and moon events of a day in the location's local time
+func FormatAstronomyAsMarkdown(location ReportLocation, date time.Time, sun sunDay, moon moonDay, estimatedZone bool, lang string) string {
+	l := newLocalizer(lang)
+
+	var builder strings.Builder
+	builder.WriteString(fmt.Sprintf("# %s: %s\n\n", l.T("Astronomy"), location.Name))
+	builder.WriteString(fmt.Sprintf("**%s:** %s\n", l.T("Date"), l.Day(date)))
+
+	builder.WriteString(fmt.Sprintf("\n## %s\n", l.T("Sun")))
+	switch {
+	case sun.Horizon.AlwaysAbove:
+		builder.WriteString(fmt.Sprintf("- **%s:** %s\n", l.T("Sun"), l.T("up all day")))
+	case sun.Horizon.AlwaysBelow:
+		builder.WriteString(fmt.Sprintf("- **%s:** %s\n", l.T("Sun"), l.T("down all day")))
+	default:
+		builder.WriteString(fmt.Sprintf("- **%s:** %s\n", l.T("Sunrise"), l.Clock(sun.Horizon.Rise)))
+		builder.WriteString(fmt.Sprintf("- **%s:** %s\n", l.T("Sunset"), l.Clock(sun.Horizon.Set)))
+	}
+	builder.WriteString(fmt.Sprintf("- **%s:** %s\n", l.T("Solar Noon"), l.Clock(sun.Noon)))
+	builder.WriteString(fmt.Sprintf("- **%s:** %s\n", l.T("Day Length"), formatDayLength(sun.DayLength())))
+	for _, twilight := range []struct {
+		Label    string
+		Crossing horizonCrossing
+	}{
+		{"Civil Twilight", sun.Civil},
+		{"Nautical Twilight", sun.Nautical},
+		{"Astronomical Twilight", sun.Astronomical},
+	} {
+		builder.WriteString(fmt.Sprintf("- **%s:** %s\n", l.T(twilight.Label), formatTwilight(twilight.Crossing, l)))
+	}
+
+	builder.WriteString(fmt.Sprintf("\n## %s\n", l.T("Moon")))
+	builder.WriteString(fmt.Sprintf("- **%s:** %s\n", l.T("Phase"), l.T("%s, %s days old", l.T(moon.PhaseName()), l.Number(moon.Age(), 1))))
+	builder.WriteString(fmt.Sprintf("- **%s:** %s%%\n", l.T("Illumination"), l.Number(moon.Illumination*100, 0)))
+	switch {
+	case moon.AlwaysUp:
+		builder.WriteString(fmt.Sprintf("- **%s:** %s\n", l.T("Moon"), l.T("up all day")))
+	case moon.AlwaysDown:
+		builder.WriteString(fmt.Sprintf("- **%s:** %s\n", l.T("Moon"), l.T("down all day")))
+	default:
+		builder.WriteString(fmt.Sprintf("- **%s:** %s\n", l.T("Moonrise"), formatOptionalClock(moon.Rise, l)))
+		builder.WriteString(fmt.Sprintf("- **%s:** %s\n", l.T("Moonset"), formatOptionalClock(moon.Set, l)))
+	}
+
+	builder.WriteString(fmt.Sprintf("\n## %s\n", l.T("Location")))
+	builder.WriteString(fmt.Sprintf("- **%s:** %.4f, %.4f\n", l.T("Coordinates"), location.Lat, location.Lon))
+	timezone := location.Timezone
+	if estimatedZone {
+		timezone = fmt.Sprintf("%s (%s)", timezone, l.T("estimated from longitude"))
+	}
+	builder.WriteString(fmt.Sprintf("- **%s:** %s\n", l.T("Timezone"), timezone))
+	builder.WriteString(fmt.Sprintf("- **%s:** %s\n", l.T("Source"), l.T("Computed locally from astronomical formulas")))
+
+	return builder.String()
+}
+
+// formatDayLength renders a duration as hours and minutes, e.g. "13h 07m"
+func formatDayLength(length time.Duration) string {
+	minutes := int(length.Round(time.Minute).Minutes())
+	return fmt.Sprintf("%dh %02dm", minutes/60, minutes%60)
+}
+
+// formatTwilight renders a twilight as its dawn and dusk. A sun that never gets that low makes it
+// last all night; one that never gets that high leaves none.
+func formatTwilight(crossing horizonCrossing, l *Localizer) string {
+	switch {
+	case crossing.AlwaysAbove:
+		return l.T("all night")
+	case crossing.AlwaysBelow:
+		return l.T("none")
+	default:
+		return l.T("dawn %s, dusk %s", l.Clock(crossing.Rise), l.Clock(crossing.Set))
+	}
+}
+
+func formatOptionalClock(t time.Time, l *Localizer) string {
+	if t.IsZero() {
+		return l.T("none")
+	}
+	return l.Clock(t)
+}
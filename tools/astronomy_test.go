@@ -0,0 +1,161 @@
+package tools
+
+import (
+	"math"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestComputeSunDay(t *testing.T) {
+	london, err := time.LoadLocation("Europe/London")
+	if err != nil {
+		t.Fatalf("Failed to load timezone: %v", err)
+	}
+
+	// Published times for London at midsummer, to the minute
+	sun := computeSunDay(time.Date(2024, 6, 21, 0, 0, 0, 0, london), 51.5074, -0.1278)
+	expected := []struct {
+		name     string
+		got      time.Time
+		expected string
+	}{
+		{"sunrise", sun.Horizon.Rise, "04:43"},
+		{"sunset", sun.Horizon.Set, "21:21"},
+		{"solar noon", sun.Noon, "13:02"},
+		{"civil dawn", sun.Civil.Rise, "03:56"},
+		{"civil dusk", sun.Civil.Set, "22:09"},
+	}
+	for _, tc := range expected {
+		want, _ := time.ParseInLocation("2006-01-02 15:04", "2024-06-21 "+tc.expected, london)
+		if diff := tc.got.Sub(want); diff < -2*time.Minute || diff > 2*time.Minute {
+			t.Errorf("London %s = %s, expected %s", tc.name, tc.got.Format("15:04:05"), tc.expected)
+		}
+	}
+	if !sun.Astronomical.AlwaysAbove {
+		t.Errorf("Expected no astronomical night in London at midsummer, got %+v", sun.Astronomical)
+	}
+
+	// Tromsø has midnight sun in June and polar night in December
+	oslo, _ := time.LoadLocation("Europe/Oslo")
+	summer := computeSunDay(time.Date(2024, 6, 21, 0, 0, 0, 0, oslo), 69.6492, 18.9553)
+	if !summer.Horizon.AlwaysAbove || summer.DayLength() != 24*time.Hour {
+		t.Errorf("Expected midnight sun in Tromsø, got %+v", summer.Horizon)
+	}
+	winter := computeSunDay(time.Date(2024, 12, 21, 0, 0, 0, 0, oslo), 69.6492, 18.9553)
+	if !winter.Horizon.AlwaysBelow || winter.DayLength() != 0 || winter.Civil.Rise.IsZero() {
+		t.Errorf("Expected polar night with civil twilight in Tromsø, got %+v", winter)
+	}
+}
+
+func TestMoonIllumination(t *testing.T) {
+	testCases := []struct {
+		name         string
+		time         time.Time
+		phase        string
+		illumination float64
+	}{
+		{"new moon", time.Date(2024, 4, 8, 18, 21, 0, 0, time.UTC), "New Moon", 0},
+		{"first quarter", time.Date(2024, 1, 18, 3, 53, 0, 0, time.UTC), "First Quarter", 0.5},
+		{"full moon", time.Date(2024, 1, 25, 17, 54, 0, 0, time.UTC), "Full Moon", 1},
+		{"waning crescent", time.Date(2024, 4, 5, 12, 0, 0, 0, time.UTC), "Waning Crescent", 0.12},
+	}
+
+	for _, tc := range testCases {
+		phase, illumination := moonIllumination(tc.time)
+		moon := moonDay{Phase: phase, Illumination: illumination}
+		if moon.PhaseName() != tc.phase || math.Abs(illumination-tc.illumination) > 0.05 {
+			t.Errorf("%s: %s at %.2f illuminated, expected %s at %.2f", tc.name, moon.PhaseName(), illumination, tc.phase, tc.illumination)
+		}
+	}
+}
+
+func TestParseAstronomyDate(t *testing.T) {
+	today := time.Date(2025, 9, 3, 15, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		value    string
+		expected string
+		errorMsg string
+	}{
+		{"", "2025-09-03", ""},
+		{"Tomorrow", "2025-09-04", ""},
+		{"yesterday", "2025-09-02", ""},
+		{"1969-07-20", "1969-07-20", ""},
+		{"1850-01-01", "", "out of range"},
+		{"next full moon", "", "invalid date"},
+	}
+
+	for _, tc := range testCases {
+		date, err := parseAstronomyDate(tc.value, today)
+		if tc.errorMsg != "" {
+			if err == nil || !strings.Contains(err.Error(), tc.errorMsg) {
+				t.Errorf("parseAstronomyDate(%q) error = %v, expected %q", tc.value, err, tc.errorMsg)
+			}
+			continue
+		}
+		if err != nil || date.Format(time.DateOnly) != tc.expected || date.Hour() != 0 {
+			t.Errorf("parseAstronomyDate(%q) = %v, %v, expected midnight on %s", tc.value, date, err, tc.expected)
+		}
+	}
+}
+
+func TestAstronomyTool(t *testing.T) {
+	astronomyTool := NewAstronomyTool()
+
+	t.Run("Timezone", func(t *testing.T) {
+		content, isError := callToolText(t, astronomyTool.Handler, map[string]any{"location": "51.5074,-0.1278", "date": "2024-06-21", "timezone": "Europe/London"})
+		if isError {
+			t.Fatalf("Expected success, got error: %s", content)
+		}
+		expected := []string{
+			"**Date:** Fri Jun 21",
+			"**Day Length:** 16h 38m",
+			"**Civil Twilight:** dawn 3:56 AM, dusk 10:10 PM",
+			"**Astronomical Twilight:** all night",
+			"**Phase:** Full Moon, 14.0 days old",
+			"**Illumination:** 99%",
+			"**Timezone:** Europe/London\n",
+		}
+		for _, text := range expected {
+			if !strings.Contains(content, text) {
+				t.Errorf("Expected %q in output:\n%s", text, content)
+			}
+		}
+	})
+
+	t.Run("EstimatedTimezone", func(t *testing.T) {
+		content, isError := callToolText(t, astronomyTool.Handler, map[string]any{"location": "69.6492,18.9553", "date": "2024-12-21", "lang": "de"})
+		if isError {
+			t.Fatalf("Expected success, got error: %s", content)
+		}
+		expected := []string{
+			"**Sonne:** den ganzen Tag unter dem Horizont",
+			"**Tageslänge:** 0h 00m",
+			"**Zeitzone:** UTC+01:00 (aus dem Längengrad geschätzt)",
+		}
+		for _, text := range expected {
+			if !strings.Contains(content, text) {
+				t.Errorf("Expected %q in output:\n%s", text, content)
+			}
+		}
+	})
+
+	t.Run("InvalidTimezone", func(t *testing.T) {
+		content, isError := callToolText(t, astronomyTool.Handler, map[string]any{"location": "51.5074,-0.1278", "timezone": "Mars/Olympus_Mons"})
+		if !isError || !strings.Contains(content, "invalid timezone") {
+			t.Errorf("Expected an invalid timezone error, got: %s", content)
+		}
+	})
+
+	t.Run("Structured", func(t *testing.T) {
+		structured := callToolStructured(t, astronomyTool.Tool, astronomyTool.Handler, map[string]any{"location": "69.6492,18.9553", "date": "2024-06-21", "timezone": "Europe/Oslo"})
+		sun, _ := structured["sun"].(map[string]any)
+		if sun["upAllDay"] != true || sun["sunrise"] != nil || sun["dayLengthMinutes"] != float64(1440) {
+			t.Errorf("Expected midnight sun in the report, got %v", sun)
+		}
+		if moon, _ := structured["moon"].(map[string]any); moon["phaseName"] == nil {
+			t.Errorf("Expected a moon phase in the report, got %v", moon)
+		}
+	})
+}
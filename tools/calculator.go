@@ -14,6 +14,21 @@ type CalculatorTool struct {
 	Handler func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error)
 }
 
+// CalculationResult is the structured form of an evaluated expression
+type CalculationResult struct {
+	Expression string `json:"expression"`
+	Result     any    `json:"result"`
+	Type       string `json:"type" jsonschema:"enum=integer,enum=float,enum=boolean,enum=string,enum=other"`
+}
+
+// CompactSummary renders the calculation as "expression = result"
+func (r CalculationResult) CompactSummary() string {
+	if f, ok := r.Result.(float64); ok {
+		return fmt.Sprintf("%s = %.6g", r.Expression, f)
+	}
+	return fmt.Sprintf("%s = %v", r.Expression, r.Result)
+}
+
 func NewCalculatorTool() *CalculatorTool {
 	return &CalculatorTool{
 		Tool:    calculatorTool(),
@@ -24,10 +39,13 @@ func NewCalculatorTool() *CalculatorTool {
 func calculatorTool() mcp.Tool {
 	return mcp.NewTool("calculate",
 		mcp.WithDescription("Evaluate mathematical expressions using natural syntax (e.g., '2 + 3 * 4', 'sin(pi/4)', 'sqrt(16)')"),
+		readOnlyTool("Calculator", false),
 		mcp.WithString("expression",
 			mcp.Required(),
 			mcp.Description("Mathematical expression to evaluate. Supports +, -, *, /, ^, sqrt(), sin(), cos(), tan(), asin(), acos(), atan(), log(), ln(), abs(), ceil(), floor(), round(), pi, e"),
 		),
+		withFormat(),
+		mcp.WithOutputSchema[CalculationResult](),
 	)
 }
 
@@ -37,6 +55,11 @@ func calculatorToolHandler(ctx context.Context, request mcp.CallToolRequest) (*m
 		return mcp.NewToolResultError(err.Error()), nil
 	}
 
+	format, err := parseFormat(request)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
 	env := map[string]interface{}{
 		"pi":    math.Pi,
 		"e":     math.E,
@@ -66,14 +89,33 @@ func calculatorToolHandler(ctx context.Context, request mcp.CallToolRequest) (*m
 		return mcp.NewToolResultError(fmt.Sprintf("Expression evaluation error: %v", err)), nil
 	}
 
+	structured := CalculationResult{Expression: expression, Result: result}
+	var text string
 	switch v := result.(type) {
 	case float64:
-		return mcp.NewToolResultText(fmt.Sprintf("%.6g", v)), nil
+		text = fmt.Sprintf("%.6g", v)
+		structured.Type = "float"
+		// NaN and Inf have no JSON representation
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			structured.Result = text
+		}
 	case int64:
-		return mcp.NewToolResultText(fmt.Sprintf("%d", v)), nil
+		text = fmt.Sprintf("%d", v)
+		structured.Type = "integer"
 	case int:
-		return mcp.NewToolResultText(fmt.Sprintf("%d", v)), nil
+		text = fmt.Sprintf("%d", v)
+		structured.Type = "integer"
+	case bool:
+		text = fmt.Sprintf("%v", v)
+		structured.Type = "boolean"
+	case string:
+		text = v
+		structured.Type = "string"
 	default:
-		return mcp.NewToolResultText(fmt.Sprintf("%v", v)), nil
+		text = fmt.Sprintf("%v", v)
+		structured.Type = "other"
+		structured.Result = text
 	}
+
+	return renderResult(format, text, structured), nil
 }
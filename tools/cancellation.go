@@ -0,0 +1,89 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// methodNotificationCancelled is sent by clients to abandon a request; mcp-go ignores it
+const methodNotificationCancelled mcp.MCPMethod = "notifications/cancelled"
+
+// inFlightRequests holds the cancel function of every request being handled, by session and
+// request ID
+type inFlightRequests struct {
+	mu      sync.Mutex
+	cancels map[string]context.CancelFunc
+}
+
+var inFlight = &inFlightRequests{cancels: make(map[string]context.CancelFunc)}
+
+func inFlightKey(sessionID string, id mcp.RequestId) string {
+	return sessionID + "/" + id.String()
+}
+
+func (f *inFlightRequests) add(key string, cancel context.CancelFunc) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.cancels[key] = cancel
+}
+
+func (f *inFlightRequests) remove(key string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.cancels, key)
+}
+
+// cancel cancels a request's context, reporting whether the request was still in flight
+func (f *inFlightRequests) cancel(key string) bool {
+	f.mu.Lock()
+	cancel, ok := f.cancels[key]
+	delete(f.cancels, key)
+	f.mu.Unlock()
+
+	if ok {
+		cancel()
+	}
+	return ok
+}
+
+// CancellationMiddleware gives every request a context that a notifications/cancelled from the
+// same session cancels, which aborts the handler's outbound HTTP calls. The notification itself
+// is acknowledged here, as mcp-go does not act on it.
+func CancellationMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		request := peekRPCRequest(r)
+		if request == nil || request.Method == "" || (request.ID.IsNil() && request.Method != methodNotificationCancelled) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		sessionID := r.Header.Get(server.HeaderKeySessionID)
+		if request.Method == methodNotificationCancelled {
+			var params struct {
+				RequestID mcp.RequestId `json:"requestId"`
+				Reason    string        `json:"reason"`
+			}
+			if json.Unmarshal(request.Params, &params) == nil && !params.RequestID.IsNil() {
+				if inFlight.cancel(inFlightKey(sessionID, params.RequestID)) {
+					log.Printf("Cancelled request %v: %s", params.RequestID.Value(), params.Reason)
+				}
+			}
+			w.WriteHeader(http.StatusAccepted)
+			return
+		}
+
+		ctx, cancel := context.WithCancel(r.Context())
+		defer cancel()
+		key := inFlightKey(sessionID, request.ID)
+		inFlight.add(key, cancel)
+		defer inFlight.remove(key)
+
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
@@ -0,0 +1,81 @@
+package tools
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+func TestCancellationMiddleware(t *testing.T) {
+	started := make(chan struct{})
+	cancelled := make(chan error, 1)
+	handler := RPCMiddleware(CancellationMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.Contains(r.URL.Path, "slow") {
+			close(started)
+			select {
+			case <-r.Context().Done():
+				cancelled <- r.Context().Err()
+			case <-time.After(5 * time.Second):
+				cancelled <- nil
+			}
+		}
+	})))
+
+	send := func(path string, body string) *httptest.ResponseRecorder {
+		request := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+		request.Header.Set(server.HeaderKeySessionID, "session-a")
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, request)
+		return recorder
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		send("/mcp/slow", `{"jsonrpc":"2.0","id":"call-1","method":"tools/call","params":{"name":"get_weather"}}`)
+	}()
+	<-started
+
+	// Another session cannot cancel the request, and neither can an unknown request ID
+	other := httptest.NewRequest(http.MethodPost, "/mcp", strings.NewReader(`{"jsonrpc":"2.0","method":"notifications/cancelled","params":{"requestId":"call-1"}}`))
+	other.Header.Set(server.HeaderKeySessionID, "session-b")
+	handler.ServeHTTP(httptest.NewRecorder(), other)
+	send("/mcp", `{"jsonrpc":"2.0","method":"notifications/cancelled","params":{"requestId":"call-2"}}`)
+
+	recorder := send("/mcp", `{"jsonrpc":"2.0","method":"notifications/cancelled","params":{"requestId":"call-1","reason":"user aborted"}}`)
+	if recorder.Code != http.StatusAccepted {
+		t.Errorf("Expected the notification to be accepted, got status %d", recorder.Code)
+	}
+
+	if err := <-cancelled; err != context.Canceled {
+		t.Errorf("Expected the request context to be cancelled, got %v", err)
+	}
+	<-done
+	inFlight.mu.Lock()
+	defer inFlight.mu.Unlock()
+	if len(inFlight.cancels) != 0 {
+		t.Errorf("Expected no requests in flight, got %v", inFlight.cancels)
+	}
+}
+
+func TestHandleWeatherRequest_Cancelled(t *testing.T) {
+	os.Setenv("OPENWEATHER_API_KEY", "test_api_key")
+	defer os.Unsetenv("OPENWEATHER_API_KEY")
+	newOpenWeatherMapTestServer(t)
+
+	ctx, cancel := context.WithCancel(context.WithValue(context.Background(), ClientIPKey, "8.8.8.8"))
+	cancel()
+
+	request := mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: map[string]any{"location": "39.4697,-0.3763"}}}
+	result, _ := weatherToolHandler(ctx, request)
+	if !result.IsError || !strings.Contains(resultText(result), "request cancelled") {
+		t.Errorf("Expected a cancellation error, got: %s", resultText(result))
+	}
+}
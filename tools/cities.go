@@ -0,0 +1,34 @@
+package tools
+
+// popularCities are suggested when completing location arguments: the world's largest cities
+// followed by common destinations, each qualified with its country code
+var popularCities = []string{
+	"Tokyo,JP", "Delhi,IN", "Shanghai,CN", "São Paulo,BR", "Mexico City,MX", "Cairo,EG", "Mumbai,IN",
+	"Beijing,CN", "Dhaka,BD", "Osaka,JP", "New York,US", "Karachi,PK", "Buenos Aires,AR",
+	"Istanbul,TR", "Kolkata,IN", "Manila,PH", "Lagos,NG", "Rio de Janeiro,BR", "Kinshasa,CD",
+	"Los Angeles,US", "Moscow,RU", "Lahore,PK", "Bangalore,IN", "Paris,FR", "Bogotá,CO", "Jakarta,ID",
+	"Chennai,IN", "Lima,PE", "Bangkok,TH", "Seoul,KR", "Nagoya,JP", "Hyderabad,IN", "London,GB",
+	"Tehran,IR", "Chicago,US", "Ho Chi Minh City,VN", "Luanda,AO", "Kuala Lumpur,MY", "Hong Kong,HK",
+	"Riyadh,SA", "Baghdad,IQ", "Santiago,CL", "Madrid,ES", "Toronto,CA", "Singapore,SG",
+	"Johannesburg,ZA", "Barcelona,ES", "Saint Petersburg,RU", "Sydney,AU", "Melbourne,AU",
+	"Berlin,DE", "Rome,IT", "Athens,GR", "Lisbon,PT", "Porto,PT", "Valencia,ES", "Seville,ES",
+	"Bilbao,ES", "Málaga,ES", "Milan,IT", "Naples,IT", "Florence,IT", "Venice,IT", "Vienna,AT",
+	"Zurich,CH", "Geneva,CH", "Amsterdam,NL", "Rotterdam,NL", "Brussels,BE", "Copenhagen,DK",
+	"Stockholm,SE", "Oslo,NO", "Helsinki,FI", "Reykjavik,IS", "Dublin,IE", "Edinburgh,GB",
+	"Manchester,GB", "Birmingham,GB", "Glasgow,GB", "Warsaw,PL", "Kraków,PL", "Prague,CZ",
+	"Budapest,HU", "Bucharest,RO", "Sofia,BG", "Belgrade,RS", "Zagreb,HR", "Kyiv,UA", "Munich,DE",
+	"Hamburg,DE", "Frankfurt,DE", "Cologne,DE", "Lyon,FR", "Marseille,FR", "Nice,FR", "Toulouse,FR",
+	"Bordeaux,FR", "Dubai,AE", "Abu Dhabi,AE", "Doha,QA", "Tel Aviv,IL", "Jerusalem,IL", "Amman,JO",
+	"Beirut,LB", "Casablanca,MA", "Marrakesh,MA", "Tunis,TN", "Nairobi,KE", "Addis Ababa,ET",
+	"Accra,GH", "Cape Town,ZA", "Dakar,SN", "Taipei,TW", "Hanoi,VN", "Shenzhen,CN", "Guangzhou,CN",
+	"Chengdu,CN", "Kyoto,JP", "Sapporo,JP", "Busan,KR", "Perth,AU", "Brisbane,AU", "Adelaide,AU",
+	"Auckland,NZ", "Wellington,NZ", "Honolulu,US", "Anchorage,US", "Seattle,US", "Portland,US",
+	"San Francisco,US", "San Diego,US", "Las Vegas,US", "Phoenix,US", "Denver,US", "Dallas,US",
+	"Houston,US", "Austin,US", "San Antonio,US", "New Orleans,US", "Atlanta,US", "Miami,US",
+	"Orlando,US", "Tampa,US", "Washington,US", "Philadelphia,US", "Boston,US", "Detroit,US",
+	"Minneapolis,US", "St. Louis,US", "Nashville,US", "Salt Lake City,US",
+	"Vancouver,CA", "Montreal,CA", "Calgary,CA", "Ottawa,CA", "Quebec City,CA", "Havana,CU",
+	"Panama City,PA", "San José,CR", "Guatemala City,GT", "Medellín,CO", "Quito,EC", "Caracas,VE",
+	"Montevideo,UY", "Brasília,BR", "Salvador,BR", "Recife,BR", "Guadalajara,MX", "Monterrey,MX",
+	"Cancún,MX",
+}
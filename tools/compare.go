@@ -0,0 +1,442 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const (
+	minComparedLocations = 2
+	maxComparedLocations = 10
+)
+
+// Conditions the comparison treats as ideal: temperatures closest to comfortableTemperature (°C)
+// and humidity closest to comfortableHumidity (%) rank best
+const (
+	comfortableTemperature = 21.0
+	comfortableHumidity    = 45.0
+)
+
+// Kinds of compared values, which decide how they are converted and formatted
+const (
+	metricTemperature   = "temperature"
+	metricPercent       = "percent"
+	metricWind          = "wind"
+	metricPrecipitation = "precipitation"
+)
+
+type CompareWeatherTool struct {
+	Tool    mcp.Tool
+	Handler func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error)
+}
+
+// comparisonMetric is a column of the comparison table. Score ranks metric values, lower being
+// better; metrics without one are shown but not ranked.
+type comparisonMetric struct {
+	Key   string
+	Label string
+	Kind  string
+	Score func(value float64) float64
+}
+
+func closestTo(ideal float64) func(float64) float64 {
+	return func(value float64) float64 { return math.Abs(value - ideal) }
+}
+
+func lowest(value float64) float64 { return value }
+
+// currentMetrics are compared for current conditions
+var currentMetrics = []comparisonMetric{
+	{"temperature", "Temperature", metricTemperature, closestTo(comfortableTemperature)},
+	{"feelsLike", "Feels Like", metricTemperature, closestTo(comfortableTemperature)},
+	{"humidity", "Humidity", metricPercent, closestTo(comfortableHumidity)},
+	{"windSpeed", "Wind", metricWind, lowest},
+	{"cloudiness", "Cloudiness", metricPercent, lowest},
+	{"precipitation", "Precipitation", metricPrecipitation, lowest},
+}
+
+// forecastMetrics are compared for a forecast day, from its daily summary
+var forecastMetrics = []comparisonMetric{
+	{"tempMax", "High", metricTemperature, closestTo(comfortableTemperature)},
+	{"tempMin", "Low", metricTemperature, nil},
+	{"precipitationChance", "Precipitation Chance", metricPercent, lowest},
+	{"precipitation", "Precipitation", metricPrecipitation, lowest},
+	{"maxWindSpeed", "Wind", metricWind, lowest},
+	{"humidity", "Humidity", metricPercent, closestTo(comfortableHumidity)},
+	{"cloudiness", "Cloudiness", metricPercent, lowest},
+}
+
+// convert turns a metric value into the unit system
+func (m comparisonMetric) convert(system UnitSystem, value float64) float64 {
+	switch m.Kind {
+	case metricTemperature:
+		return system.ConvertTemperature(value)
+	case metricWind:
+		return system.ConvertWindSpeed(value)
+	case metricPrecipitation:
+		return system.ConvertPrecipitation(value)
+	default:
+		return value
+	}
+}
+
+// format renders a metric value in the unit system
+func (m comparisonMetric) format(system UnitSystem, value float64) string {
+	switch m.Kind {
+	case metricTemperature:
+		return system.FormatTemperature(value)
+	case metricWind:
+		return system.FormatWindSpeed(value)
+	case metricPrecipitation:
+		return system.FormatPrecipitation(value)
+	default:
+		return system.number(value, 0) + "%"
+	}
+}
+
+// comparisonEntry is one compared location. Values are metric and keyed by comparisonMetric.Key;
+// Err is set instead when the location could not be fetched.
+type comparisonEntry struct {
+	Requested string
+	Location  ReportLocation
+	Date      string
+	Condition string
+	Values    map[string]float64
+	Err       string
+}
+
+func (e comparisonEntry) Name() string {
+	if e.Location.Name == "" {
+		return e.Requested
+	}
+	return e.Location.DisplayName()
+}
+
+func NewCompareWeatherTool() *CompareWeatherTool {
+	return &CompareWeatherTool{
+		Tool:    compareWeatherTool(),
+		Handler: compareWeatherToolHandler,
+	}
+}
+
+func compareWeatherTool() mcp.Tool {
+	return mcp.NewTool("compare_weather",
+		mcp.WithDescription("Compare the weather of several locations side by side, either current conditions or a forecast day, and name the best and worst location for each metric. Locations that can't be fetched are reported without failing the comparison."),
+		readOnlyTool("Compare Weather", true),
+		mcp.WithArray("locations",
+			mcp.Required(),
+			mcp.Description("Locations to compare (2-10). Each can be a city name (e.g., 'London' or 'New York,US') or coordinates (e.g., '40.7128,-74.0060')."),
+			mcp.WithStringItems(),
+			mcp.MinItems(minComparedLocations),
+			mcp.MaxItems(maxComparedLocations),
+		),
+		mcp.WithString("day",
+			mcp.Description("Forecast day to compare (optional): 'today', 'tomorrow', a weekday name (e.g., 'saturday') or a date (YYYY-MM-DD), in each location's local time. Compares current conditions if not provided."),
+		),
+		withUnits("Defaults to the customary units of the first location's country."),
+		withLanguage(),
+		withFormat(),
+		mcp.WithOutputSchema[ComparisonReport](),
+	)
+}
+
+func compareWeatherToolHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	format, err := parseFormat(request)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	locations := request.GetStringSlice("locations", nil)
+	if len(locations) < minComparedLocations || len(locations) > maxComparedLocations {
+		return mcp.NewToolResultError(fmt.Sprintf("locations must list between %d and %d places", minComparedLocations, maxComparedLocations)), nil
+	}
+	for _, location := range locations {
+		if strings.TrimSpace(location) == "" {
+			return mcp.NewToolResultError("locations must not contain empty entries"), nil
+		}
+	}
+
+	day := strings.ToLower(strings.TrimSpace(request.GetString("day", "")))
+	if day == "now" {
+		day = ""
+	}
+	if !isValidForecastStart(day) {
+		return mcp.NewToolResultError(fmt.Sprintf("invalid day %q: use 'today', 'tomorrow', a weekday name or a date (YYYY-MM-DD)", day)), nil
+	}
+
+	units := strings.ToLower(request.GetString("units", ""))
+	if units != "" && !isValidUnits(units) {
+		return mcp.NewToolResultError(fmt.Sprintf("invalid units %q: must be one of metric, imperial, standard, uk", units)), nil
+	}
+
+	lang, err := resolveLanguage(ctx, request, nil)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	entries := fetchComparison(ctx, locations, day, lang)
+
+	failed := 0
+	for _, entry := range entries {
+		if entry.Err != "" {
+			failed++
+		}
+	}
+	if failed == len(entries) {
+		var reasons []string
+		for _, entry := range entries {
+			reasons = append(reasons, fmt.Sprintf("%s: %s", entry.Requested, entry.Err))
+		}
+		return mcp.NewToolResultError("Failed to fetch weather for every location: " + strings.Join(reasons, "; ")), nil
+	}
+
+	// Without explicit units, use the customary units of the first location that could be fetched
+	if units == "" {
+		for _, entry := range entries {
+			if entry.Err == "" {
+				units = unitSystemForCountry(entry.Location.Country)
+				break
+			}
+		}
+	}
+
+	metrics := currentMetrics
+	if day != "" {
+		metrics = forecastMetrics
+	}
+
+	return renderResult(format, FormatComparison(entries, metrics, units, lang), NewComparisonReport(entries, metrics, units)), nil
+}
+
+// fetchComparison fetches every location concurrently through handleWeatherRequest, in metric
+// units, keeping the order of the request. Failed locations come back with Err set. Ambiguous
+// names keep their best match rather than asking the user about every location at once.
+func fetchComparison(ctx context.Context, locations []string, day, lang string) []comparisonEntry {
+	ctx = withoutElicitation(ctx)
+	entries := make([]comparisonEntry, len(locations))
+
+	var wg sync.WaitGroup
+	for i, location := range locations {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			entries[i] = fetchComparisonEntry(ctx, strings.TrimSpace(location), day, lang)
+		}()
+	}
+	wg.Wait()
+
+	return entries
+}
+
+func fetchComparisonEntry(ctx context.Context, location, day, lang string) comparisonEntry {
+	entry := comparisonEntry{Requested: location}
+
+	request := mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: map[string]any{
+		"location": location,
+		"units":    unitsMetric,
+		"lang":     lang,
+		"format":   formatCompact,
+	}}}
+
+	var result *mcp.CallToolResult
+	if day == "" {
+		result, _ = handleWeatherRequest(ctx, request, buildWeatherURLFromLocation, discardMarkdown[WeatherData], NewWeatherReport)
+	} else {
+		opts := ForecastOptions{Days: 1, Granularity: granularityDaily, Start: day, Fields: fieldSet(defaultForecastFields)}
+		result, _ = handleWeatherRequest(ctx, request, buildForecastURLFromLocation, discardMarkdown[ForecastData],
+			func(data ForecastData, originalLocation string, units string) ForecastReport {
+				return NewForecastReport(data, originalLocation, units, opts)
+			},
+		)
+	}
+
+	if result.IsError {
+		entry.Err = resultText(result)
+		return entry
+	}
+
+	switch report := result.StructuredContent.(type) {
+	case WeatherReport:
+		entry.Location = report.Location
+		entry.Condition = report.Condition
+		entry.Values = currentValues(report)
+	case ForecastReport:
+		entry.Location = report.Location
+		if len(report.Summaries) == 0 {
+			entry.Err = report.Message
+			if entry.Err == "" {
+				entry.Err = "no forecast data for this day"
+			}
+			return entry
+		}
+		summary := report.Summaries[0]
+		entry.Date = summary.Date
+		entry.Condition = summary.Condition
+		entry.Values = map[string]float64{
+			"tempMax":             summary.TempMax,
+			"tempMin":             summary.TempMin,
+			"precipitationChance": summary.PrecipitationChance,
+			"precipitation":       summary.Rain + summary.Snow,
+			"maxWindSpeed":        summary.MaxWindSpeed,
+			"humidity":            summary.Humidity,
+			"cloudiness":          summary.Cloudiness,
+		}
+	}
+
+	return entry
+}
+
+// currentValues picks the compared values out of a metric current conditions report
+func currentValues(report WeatherReport) map[string]float64 {
+	values := map[string]float64{}
+	set := func(key string, value *float64) {
+		if value != nil {
+			values[key] = *value
+		}
+	}
+	set("temperature", report.Temperature)
+	set("feelsLike", report.FeelsLike)
+	set("humidity", report.Humidity)
+	set("windSpeed", report.WindSpeed)
+	if report.Cloudiness != nil {
+		values["cloudiness"] = float64(*report.Cloudiness)
+	}
+
+	precipitation := 0.0
+	for _, amount := range []*float64{report.RainLastHour, report.SnowLastHour} {
+		if amount != nil {
+			precipitation += *amount
+		}
+	}
+	values["precipitation"] = precipitation
+
+	return values
+}
+
+// discardMarkdown stands in for a formatter when only the structured report is needed
+func discardMarkdown[T any](T, string, string, string) string {
+	return ""
+}
+
+// resultText returns the text of a tool result, such as its error message
+func resultText(result *mcp.CallToolResult) string {
+	for _, content := range result.Content {
+		if text, ok := mcp.AsTextContent(content); ok {
+			return text.Text
+		}
+	}
+	return ""
+}
+
+// comparisonRanking is the best and worst location for a metric
+type comparisonRanking struct {
+	Metric comparisonMetric
+	Best   comparisonEntry
+	Worst  comparisonEntry
+}
+
+// rankComparison finds the best and worst location for each scored metric. Metrics where every
+// location scores the same are left out, since there is nothing to choose between them.
+func rankComparison(entries []comparisonEntry, metrics []comparisonMetric) []comparisonRanking {
+	var rankings []comparisonRanking
+	for _, metric := range metrics {
+		if metric.Score == nil {
+			continue
+		}
+
+		var best, worst *comparisonEntry
+		for i := range entries {
+			entry := &entries[i]
+			value, ok := entry.Values[metric.Key]
+			if entry.Err != "" || !ok {
+				continue
+			}
+			if best == nil || metric.Score(value) < metric.Score(best.Values[metric.Key]) {
+				best = entry
+			}
+			if worst == nil || metric.Score(value) > metric.Score(worst.Values[metric.Key]) {
+				worst = entry
+			}
+		}
+
+		if best == nil || metric.Score(best.Values[metric.Key]) == metric.Score(worst.Values[metric.Key]) {
+			continue
+		}
+		rankings = append(rankings, comparisonRanking{Metric: metric, Best: *best, Worst: *worst})
+	}
+	return rankings
+}
+
+// FormatComparison renders the compared locations as a table, followed by the best and worst
+// location per metric and the locations that could not be fetched
+func FormatComparison(entries []comparisonEntry, metrics []comparisonMetric, units, lang string) string {
+	l := newLocalizer(lang)
+	system := l.Units(units)
+
+	var builder strings.Builder
+	builder.WriteString(fmt.Sprintf("# %s\n\n", l.T("Weather Comparison")))
+
+	when := l.T("Current Conditions")
+	for _, entry := range entries {
+		if entry.Date != "" {
+			if date, err := time.Parse(time.DateOnly, entry.Date); err == nil {
+				when = l.Day(date)
+			}
+			break
+		}
+	}
+	builder.WriteString(fmt.Sprintf("**%s:** %s\n\n", l.T("When"), when))
+
+	header := []string{l.T("Location"), l.T("Condition")}
+	for _, metric := range metrics {
+		header = append(header, l.T(metric.Label))
+	}
+	builder.WriteString("| " + strings.Join(header, " | ") + " |\n")
+	builder.WriteString(strings.Repeat("|---", len(header)) + "|\n")
+
+	for _, entry := range entries {
+		if entry.Err != "" {
+			continue
+		}
+		row := []string{entry.Name(), l.T(entry.Condition)}
+		for _, metric := range metrics {
+			cell := "-"
+			if value, ok := entry.Values[metric.Key]; ok {
+				cell = metric.format(system, value)
+			}
+			row = append(row, cell)
+		}
+		builder.WriteString("| " + strings.Join(row, " | ") + " |\n")
+	}
+
+	if rankings := rankComparison(entries, metrics); len(rankings) > 0 {
+		builder.WriteString(fmt.Sprintf("\n## %s\n", l.T("Best and Worst")))
+		for _, ranking := range rankings {
+			key := ranking.Metric.Key
+			builder.WriteString(fmt.Sprintf("- **%s:** %s\n", l.T(ranking.Metric.Label), l.T("best %s (%s), worst %s (%s)",
+				ranking.Best.Name(), ranking.Metric.format(system, ranking.Best.Values[key]),
+				ranking.Worst.Name(), ranking.Metric.format(system, ranking.Worst.Values[key]))))
+		}
+	}
+
+	var failed []comparisonEntry
+	for _, entry := range entries {
+		if entry.Err != "" {
+			failed = append(failed, entry)
+		}
+	}
+	if len(failed) > 0 {
+		builder.WriteString(fmt.Sprintf("\n## %s\n", l.T("Unavailable")))
+		for _, entry := range failed {
+			builder.WriteString(fmt.Sprintf("- **%s:** %s\n", entry.Requested, entry.Err))
+		}
+	}
+
+	return builder.String()
+}
@@ -0,0 +1,141 @@
+package tools
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestRankComparison(t *testing.T) {
+	entries := []comparisonEntry{
+		{Requested: "Oslo", Location: ReportLocation{Name: "Oslo", Country: "NO"}, Values: map[string]float64{"temperature": 4, "humidity": 80, "windSpeed": 9}},
+		{Requested: "Valencia", Location: ReportLocation{Name: "Valencia", Country: "ES"}, Values: map[string]float64{"temperature": 22, "humidity": 55, "windSpeed": 3}},
+		{Requested: "Cairo", Location: ReportLocation{Name: "Cairo", Country: "EG"}, Values: map[string]float64{"temperature": 36, "humidity": 20, "windSpeed": 3}},
+		{Requested: "Atlantis", Err: "no locations found matching \"Atlantis\""},
+	}
+	metrics := []comparisonMetric{currentMetrics[0], currentMetrics[2], currentMetrics[3], currentMetrics[4]}
+
+	rankings := rankComparison(entries, metrics)
+
+	expected := map[string][2]string{
+		"temperature": {"Valencia", "Oslo"},
+		"humidity":    {"Valencia", "Oslo"},
+		"windSpeed":   {"Valencia", "Oslo"},
+	}
+	if len(rankings) != len(expected) {
+		t.Fatalf("Expected %d rankings (cloudiness has no values), got %+v", len(expected), rankings)
+	}
+	for _, ranking := range rankings {
+		want := expected[ranking.Metric.Key]
+		if ranking.Best.Requested != want[0] || ranking.Worst.Requested != want[1] {
+			t.Errorf("%s: best %s, worst %s, expected %s and %s", ranking.Metric.Key, ranking.Best.Requested, ranking.Worst.Requested, want[0], want[1])
+		}
+	}
+
+	// A tie between every location ranks nothing
+	tied := []comparisonEntry{entries[1], entries[1]}
+	if rankings := rankComparison(tied, metrics); len(rankings) != 0 {
+		t.Errorf("Expected no rankings for identical locations, got %+v", rankings)
+	}
+}
+
+func TestFormatComparison(t *testing.T) {
+	entries := []comparisonEntry{
+		{Requested: "Oslo", Location: ReportLocation{Name: "Oslo", Country: "NO"}, Condition: "Light Rain", Values: map[string]float64{"temperature": 4, "feelsLike": 1, "humidity": 80, "windSpeed": 9, "cloudiness": 90, "precipitation": 1.2}},
+		{Requested: "Valencia", Location: ReportLocation{Name: "Valencia", Country: "ES"}, Condition: "Clear Sky", Values: map[string]float64{"temperature": 22, "feelsLike": 22, "humidity": 55, "windSpeed": 3, "cloudiness": 0, "precipitation": 0}},
+		{Requested: "95,10", Err: "coordinates out of range: 95,10"},
+	}
+
+	result := FormatComparison(entries, currentMetrics, unitsMetric, langEnglish)
+	expected := []string{
+		"# Weather Comparison",
+		"| Location | Condition | Temperature | Feels Like | Humidity | Wind | Cloudiness | Precipitation |",
+		"| Oslo, NO | Light Rain | 4.0°C | 1.0°C | 80% | 9.0 m/s | 90% | 1.2 mm |",
+		"**Temperature:** best Valencia, ES (22.0°C), worst Oslo, NO (4.0°C)",
+		"**Precipitation:** best Valencia, ES (0.0 mm), worst Oslo, NO (1.2 mm)",
+		"## Unavailable\n- **95,10:** coordinates out of range: 95,10",
+	}
+	for _, text := range expected {
+		if !strings.Contains(result, text) {
+			t.Errorf("Expected %q in output:\n%s", text, result)
+		}
+	}
+
+	report := NewComparisonReport(entries, currentMetrics, unitsImperial)
+	if report.Locations[0].Values["temperature"] != 39.2 || report.Locations[2].Error == "" {
+		t.Errorf("Unexpected report locations: %+v", report.Locations)
+	}
+	if ranking := report.Rankings[0]; ranking.Metric != "temperature" || ranking.Best != "Valencia" || ranking.BestValue != 71.6 {
+		t.Errorf("Unexpected ranking: %+v", ranking)
+	}
+}
+
+func TestCompareWeatherTool(t *testing.T) {
+	os.Setenv("OPENWEATHER_API_KEY", "test_api_key")
+	defer os.Unsetenv("OPENWEATHER_API_KEY")
+	newOpenWeatherMapTestServer(t)
+
+	compareTool := NewCompareWeatherTool()
+
+	t.Run("PartialFailure", func(t *testing.T) {
+		content, isError := callToolText(t, compareTool.Handler, map[string]any{"locations": []any{"Valencia,ES", "95,10"}})
+		if isError {
+			t.Fatalf("Expected success, got error: %s", content)
+		}
+		expected := []string{
+			"**When:** Current Conditions",
+			"| Valencia, ES | Few Clouds | 28.4°C | 29.1°C | 54% | 4.1 m/s | 20% | 0.0 mm |",
+			"- **95,10:** coordinates out of range: 95,10",
+		}
+		for _, text := range expected {
+			if !strings.Contains(content, text) {
+				t.Errorf("Expected %q in output:\n%s", text, content)
+			}
+		}
+	})
+
+	t.Run("ForecastDay", func(t *testing.T) {
+		content, isError := callToolText(t, compareTool.Handler, map[string]any{"locations": []any{"Valencia,ES", "39.4697,-0.3763"}, "day": "tomorrow", "lang": "de"})
+		if isError {
+			t.Fatalf("Expected success, got error: %s", content)
+		}
+		if !strings.Contains(content, "# Wettervergleich") || !strings.Contains(content, "| Ort | Wetterlage | Höchstwert | Tiefstwert |") {
+			t.Errorf("Expected a German forecast day comparison:\n%s", content)
+		}
+	})
+
+	t.Run("AllFailed", func(t *testing.T) {
+		content, isError := callToolText(t, compareTool.Handler, map[string]any{"locations": []any{"95,10", "0,200"}})
+		if !isError || !strings.Contains(content, "95,10: coordinates out of range") {
+			t.Errorf("Expected an error when every location fails, got: %s", content)
+		}
+	})
+
+	t.Run("Validation", func(t *testing.T) {
+		testCases := []struct {
+			args     map[string]any
+			errorMsg string
+		}{
+			{map[string]any{"locations": []any{"Valencia,ES"}}, "between 2 and 10"},
+			{map[string]any{"locations": []any{"Valencia,ES", " "}}, "empty entries"},
+			{map[string]any{"locations": []any{"Valencia,ES", "Oslo"}, "day": "next week"}, "invalid day"},
+		}
+		for _, tc := range testCases {
+			content, isError := callToolText(t, compareTool.Handler, tc.args)
+			if !isError || !strings.Contains(content, tc.errorMsg) {
+				t.Errorf("Expected error %q for %v, got: %s", tc.errorMsg, tc.args, content)
+			}
+		}
+	})
+
+	t.Run("Structured", func(t *testing.T) {
+		structured := callToolStructured(t, compareTool.Tool, compareTool.Handler, map[string]any{"locations": []any{"Valencia,ES", "95,10"}, "units": "imperial"})
+		locations, ok := structured["locations"].([]any)
+		if !ok || len(locations) != 2 {
+			t.Fatalf("Expected 2 locations, got %v", structured["locations"])
+		}
+		if failed := locations[1].(map[string]any); failed["requested"] != "95,10" || failed["error"] == nil {
+			t.Errorf("Expected the failed location to carry its error, got %v", failed)
+		}
+	})
+}
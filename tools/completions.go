@@ -0,0 +1,318 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const (
+	// methodCompletionComplete is the argument completion method, which mcp-go does not route itself
+	methodCompletionComplete mcp.MCPMethod = "completion/complete"
+	maxCompletionValues                    = 100
+
+	maxRecentValues   = 10
+	maxRecentSessions = 1000
+)
+
+// Reference types a completion request can ask about. MCP only defines prompt and resource
+// references; tool references are our extension for clients that complete tool arguments.
+const (
+	refPrompt   = "ref/prompt"
+	refResource = "ref/resource"
+	refTool     = "ref/tool"
+)
+
+// Kinds of recently used values, shared by every argument that takes them
+const (
+	recentLocations = "location"
+	recentIPs       = "ip"
+)
+
+// completionFunc suggests values for an argument from what has been typed so far
+type completionFunc func(ctx context.Context, sessionID string, value string) []string
+
+// promptCompletions holds the suggested values of prompt arguments, by prompt and argument name.
+// Every prompt is listed.
+var promptCompletions = map[string]map[string]completionFunc{
+	"plan_outdoor_activity": {"activity": completeFrom(activityNames...), "location": completeLocation},
+	"daily_briefing":        {"location": completeLocation, "units": completeFrom(unitsMetric, unitsImperial, unitsStandard, unitsUK)},
+	"plan_trip_weather":     {"cities": completeCityList, "units": completeFrom(unitsMetric, unitsImperial, unitsStandard, unitsUK)},
+	"solve_math_problem":    {"level": completeFrom(mathLevels...)},
+	"diagnose_ip":           {"ip": completeIP},
+}
+
+// resourceCompletions holds the suggested values of resource template variables, by URI template
+var resourceCompletions = map[string]map[string]completionFunc{
+	"weather://current/{location}":  {"location": completeLocation},
+	"weather://forecast/{location}": {"location": completeLocation},
+	"ip://{address}":                {"address": completeIP},
+}
+
+// toolCompletions holds the suggested values of tool arguments, by tool name. Tools that are not
+// listed have no arguments to complete.
+var toolCompletions = map[string]map[string]completionFunc{
+	"get_weather":           {"location": completeLocation},
+	"get_weather_forecast":  {"location": completeLocation},
+	"get_weather_history":   {"location": completeLocation},
+	"get_weather_alerts":    {"location": completeLocation},
+	"get_air_quality":       {"location": completeLocation},
+	"get_astronomy":         {"location": completeLocation},
+	"weather_suitability":   {"location": completeLocation, "activity": completeFrom(activityNames...)},
+	"get_ip_data":           {"ip": completeIP},
+	"save_location":         {"location": completeLocation},
+	"rename_saved_location": {"name": completeSavedLocation},
+	"delete_saved_location": {"name": completeSavedLocation},
+	"set_preferences": {
+		"location": completeLocation,
+		"units":    completeFrom(unitsMetric, unitsImperial, unitsStandard, unitsUK),
+		"lang":     completeFrom(supportedLanguages...),
+		"format":   completeFrom(formatMarkdown, formatPlain, formatCompact, formatJSON),
+	},
+}
+
+// recordedArgument is a tool argument whose values are remembered for completion
+type recordedArgument struct {
+	Name string
+	Kind string
+}
+
+// recordedArguments are the tool arguments remembered for completion, by tool name
+var recordedArguments = map[string]recordedArgument{
+	"get_weather":          {"location", recentLocations},
+	"get_weather_forecast": {"location", recentLocations},
+	"get_weather_history":  {"location", recentLocations},
+	"get_weather_alerts":   {"location", recentLocations},
+	"get_air_quality":      {"location", recentLocations},
+	"get_astronomy":        {"location", recentLocations},
+	"weather_suitability":  {"location", recentLocations},
+	"get_ip_data":          {"ip", recentIPs},
+}
+
+// recentStore remembers the values each session used most recently, newest first. The session
+// that has been idle longest is forgotten once maxRecentSessions is reached.
+type recentStore struct {
+	mu       sync.Mutex
+	sessions map[string]*recentSession
+}
+
+type recentSession struct {
+	values   map[string][]string
+	lastUsed time.Time
+}
+
+var recent = &recentStore{sessions: make(map[string]*recentSession)}
+
+func (s *recentStore) add(sessionID string, kind string, value string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	session, ok := s.sessions[sessionID]
+	if !ok {
+		if len(s.sessions) >= maxRecentSessions {
+			s.evictOldest()
+		}
+		session = &recentSession{values: make(map[string][]string)}
+		s.sessions[sessionID] = session
+	}
+	session.lastUsed = time.Now()
+
+	values := []string{value}
+	for _, existing := range session.values[kind] {
+		if !strings.EqualFold(existing, value) && len(values) < maxRecentValues {
+			values = append(values, existing)
+		}
+	}
+	session.values[kind] = values
+}
+
+func (s *recentStore) get(sessionID string, kind string) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if session, ok := s.sessions[sessionID]; ok {
+		return append([]string{}, session.values[kind]...)
+	}
+	return nil
+}
+
+func (s *recentStore) remove(sessionID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.sessions, sessionID)
+}
+
+// evictOldest forgets the session that has been idle longest. The caller holds s.mu.
+func (s *recentStore) evictOldest() {
+	var oldestID string
+	var oldest time.Time
+	for sessionID, session := range s.sessions {
+		if oldestID == "" || session.lastUsed.Before(oldest) {
+			oldestID, oldest = sessionID, session.lastUsed
+		}
+	}
+	delete(s.sessions, oldestID)
+}
+
+// RecordRecentArguments remembers the location or IP address of a successful tool call, so later
+// completions in the same session suggest it first. It is registered as an after-call-tool hook.
+func RecordRecentArguments(ctx context.Context, id any, request *mcp.CallToolRequest, result *mcp.CallToolResult) {
+	recorded, ok := recordedArguments[request.Params.Name]
+	if !ok || result == nil || result.IsError {
+		return
+	}
+	session := server.ClientSessionFromContext(ctx)
+	if session == nil || session.SessionID() == "" {
+		return
+	}
+	if value := strings.TrimSpace(request.GetString(recorded.Name, "")); value != "" {
+		recent.add(session.SessionID(), recorded.Kind, value)
+	}
+}
+
+// completeFrom suggests the options that start with the typed value, ignoring case
+func completeFrom(options ...string) completionFunc {
+	return func(ctx context.Context, sessionID string, value string) []string {
+		return matchingCompletions(options, value)
+	}
+}
+
+// completeLocation suggests the session's recent locations, then saved locations, then popular
+// cities
+func completeLocation(ctx context.Context, sessionID string, value string) []string {
+	options := append(recent.get(sessionID, recentLocations), savedLocationNames(ctx)...)
+	return matchingCompletions(append(options, popularCities...), value)
+}
+
+// completeSavedLocation suggests the names of saved locations
+func completeSavedLocation(ctx context.Context, sessionID string, value string) []string {
+	return matchingCompletions(savedLocationNames(ctx), value)
+}
+
+// completeCityList completes the last city of a semicolon-separated list
+func completeCityList(ctx context.Context, sessionID string, value string) []string {
+	head, last := "", value
+	if i := strings.LastIndex(value, ";"); i >= 0 {
+		head, last = value[:i+1]+" ", value[i+1:]
+	}
+
+	var values []string
+	for _, city := range completeLocation(ctx, sessionID, last) {
+		values = append(values, head+city)
+	}
+	return values
+}
+
+// completeIP suggests the address the client connects from, then the session's recent lookups
+func completeIP(ctx context.Context, sessionID string, value string) []string {
+	var options []string
+	if clientIP, _ := ctx.Value(ClientIPKey).(string); clientIP != "" {
+		options = append(options, clientIP)
+	}
+	return matchingCompletions(append(options, recent.get(sessionID, recentIPs)...), value)
+}
+
+// matchingCompletions returns the options that start with the typed value, ignoring case and
+// duplicates
+func matchingCompletions(options []string, value string) []string {
+	prefix := strings.ToLower(strings.TrimSpace(value))
+	seen := make(map[string]bool)
+	var matches []string
+	for _, option := range options {
+		key := strings.ToLower(option)
+		if strings.HasPrefix(key, prefix) && !seen[key] {
+			seen[key] = true
+			matches = append(matches, option)
+		}
+	}
+	return matches
+}
+
+// completeArgument answers a completion request for an argument of a prompt, resource template
+// or tool
+func completeArgument(ctx context.Context, sessionID string, refType string, refName string, argument string, value string) (*mcp.CompleteResult, error) {
+	var arguments map[string]completionFunc
+	switch refType {
+	case refPrompt:
+		var ok bool
+		if arguments, ok = promptCompletions[refName]; !ok {
+			return nil, fmt.Errorf("unknown prompt %q", refName)
+		}
+	case refResource:
+		var ok bool
+		if arguments, ok = resourceCompletions[refName]; !ok {
+			return nil, fmt.Errorf("unknown resource template %q", refName)
+		}
+	case refTool:
+		arguments = toolCompletions[refName]
+	default:
+		return nil, fmt.Errorf("unsupported reference type %q: must be one of %s, %s, %s", refType, refPrompt, refResource, refTool)
+	}
+
+	var values []string
+	if complete, ok := arguments[argument]; ok {
+		values = complete(ctx, sessionID, value)
+	}
+
+	result := &mcp.CompleteResult{}
+	result.Completion.Total = len(values)
+	if len(values) > maxCompletionValues {
+		values = values[:maxCompletionValues]
+		result.Completion.HasMore = true
+	}
+	result.Completion.Values = append([]string{}, values...)
+	return result, nil
+}
+
+// CompletionMiddleware answers completion/complete requests, which mcp-go does not route.
+// Everything else is passed on to the Streamable HTTP handler.
+func CompletionMiddleware(contextFunc server.HTTPContextFunc, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sessionID := r.Header.Get(server.HeaderKeySessionID)
+		request := peekRPCRequest(r)
+		if request == nil || request.Method != methodCompletionComplete {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		var params struct {
+			Ref struct {
+				Type string `json:"type"`
+				Name string `json:"name"`
+				URI  string `json:"uri"`
+			} `json:"ref"`
+			Argument struct {
+				Name  string `json:"name"`
+				Value string `json:"value"`
+			} `json:"argument"`
+		}
+		if err := json.Unmarshal(request.Params, &params); err != nil || params.Argument.Name == "" {
+			writeRPCResponse(w, r, mcp.NewJSONRPCError(request.ID, mcp.INVALID_PARAMS, "ref and argument.name are required", nil))
+			return
+		}
+
+		ctx := r.Context()
+		if contextFunc != nil {
+			ctx = contextFunc(ctx, r)
+		}
+
+		refName := params.Ref.Name
+		if params.Ref.Type == refResource {
+			refName = params.Ref.URI
+		}
+		result, err := completeArgument(ctx, sessionID, params.Ref.Type, refName, params.Argument.Name, params.Argument.Value)
+		if err != nil {
+			writeRPCResponse(w, r, mcp.NewJSONRPCError(request.ID, mcp.INVALID_PARAMS, err.Error(), nil))
+			return
+		}
+		writeRPCResponse(w, r, mcp.JSONRPCResponse{JSONRPC: mcp.JSONRPC_VERSION, ID: request.ID, Result: result})
+	})
+}
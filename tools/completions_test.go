@@ -0,0 +1,163 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+func TestCompleteArgument(t *testing.T) {
+	ctx := context.WithValue(context.Background(), ClientIPKey, "8.8.8.8")
+
+	testCases := []struct {
+		prompt   string
+		argument string
+		value    string
+		expected []string
+	}{
+		{"plan_outdoor_activity", "activity", "", activityNames},
+		{"plan_outdoor_activity", "activity", "Cy", []string{"cycling"}},
+		{"daily_briefing", "units", "m", []string{"metric"}},
+		{"solve_math_problem", "level", "adv", []string{"advanced"}},
+		{"diagnose_ip", "ip", "8.", []string{"8.8.8.8"}},
+		{"diagnose_ip", "ip", "10.", []string{}},
+		{"daily_briefing", "location", "lon", []string{"London,GB"}},
+		{"plan_trip_weather", "cities", "Lisbon,PT;porto", []string{"Lisbon,PT; Porto,PT"}},
+	}
+
+	for _, tc := range testCases {
+		result, err := completeArgument(ctx, "", refPrompt, tc.prompt, tc.argument, tc.value)
+		if err != nil {
+			t.Errorf("%s.%s: unexpected error: %v", tc.prompt, tc.argument, err)
+			continue
+		}
+		if strings.Join(result.Completion.Values, ",") != strings.Join(tc.expected, ",") || result.Completion.Total != len(tc.expected) {
+			t.Errorf("%s.%s %q = %v, expected %v", tc.prompt, tc.argument, tc.value, result.Completion.Values, tc.expected)
+		}
+	}
+
+	errorCases := []struct {
+		refType  string
+		refName  string
+		errorMsg string
+	}{
+		{refPrompt, "unknown", "unknown prompt"},
+		{refResource, "weather://history/{location}", "unknown resource template"},
+		{"ref/function", "calculate", "unsupported reference type"},
+	}
+	for _, tc := range errorCases {
+		if _, err := completeArgument(ctx, "", tc.refType, tc.refName, "location", ""); err == nil || !strings.Contains(err.Error(), tc.errorMsg) {
+			t.Errorf("Expected error %q for %s %s, got %v", tc.errorMsg, tc.refType, tc.refName, err)
+		}
+	}
+}
+
+func TestCompleteArgument_RecentValues(t *testing.T) {
+	ctx := context.WithValue(context.Background(), ClientIPKey, "8.8.8.8")
+	defer recent.remove("session-a")
+
+	recent.add("session-a", recentLocations, "Londonderry,GB")
+	recent.add("session-a", recentLocations, "London,GB")
+	recent.add("session-a", recentIPs, "1.1.1.1")
+
+	testCases := []struct {
+		sessionID string
+		refType   string
+		refName   string
+		argument  string
+		value     string
+		expected  []string
+	}{
+		{"session-a", refTool, "get_weather", "location", "london", []string{"London,GB", "Londonderry,GB"}},
+		{"session-b", refTool, "get_weather_forecast", "location", "london", []string{"London,GB"}},
+		{"session-a", refResource, "ip://{address}", "address", "", []string{"8.8.8.8", "1.1.1.1"}},
+		{"session-a", refTool, "get_ip_data", "ip", "1", []string{"1.1.1.1"}},
+		{"session-a", refTool, "calculate", "expression", "2", []string{}},
+	}
+
+	for _, tc := range testCases {
+		result, err := completeArgument(ctx, tc.sessionID, tc.refType, tc.refName, tc.argument, tc.value)
+		if err != nil {
+			t.Errorf("%s %s: unexpected error: %v", tc.refName, tc.argument, err)
+			continue
+		}
+		if strings.Join(result.Completion.Values, ",") != strings.Join(tc.expected, ",") {
+			t.Errorf("%s %s %q in %s = %v, expected %v", tc.refName, tc.argument, tc.value, tc.sessionID, result.Completion.Values, tc.expected)
+		}
+	}
+
+	if result, _ := completeArgument(ctx, "", refTool, "get_weather", "location", ""); result.Completion.Total != len(popularCities) || len(result.Completion.Values) != maxCompletionValues || !result.Completion.HasMore {
+		t.Errorf("Expected the first %d of %d cities, got %d of %d", maxCompletionValues, len(popularCities), len(result.Completion.Values), result.Completion.Total)
+	}
+}
+
+func TestRecordRecentArguments(t *testing.T) {
+	defer recent.remove("session-a")
+	ctx := server.NewMCPServer("test", "1.0.0").WithContext(context.Background(), testSession{"session-a"})
+
+	record := func(tool string, args map[string]any, result *mcp.CallToolResult) {
+		request := &mcp.CallToolRequest{Params: mcp.CallToolParams{Name: tool, Arguments: args}}
+		RecordRecentArguments(ctx, 1, request, result)
+	}
+	record("get_weather", map[string]any{"location": "Paris,FR"}, mcp.NewToolResultText("ok"))
+	record("get_weather", map[string]any{"location": "Atlantis"}, mcp.NewToolResultError("location not found"))
+	record("get_ip_data", map[string]any{"ip": "9.9.9.9"}, mcp.NewToolResultText("ok"))
+	record("calculate", map[string]any{"expression": "1+1"}, mcp.NewToolResultText("2"))
+	for i := 0; i < maxRecentValues; i++ {
+		record("get_air_quality", map[string]any{"location": fmt.Sprintf("%d,0", i)}, mcp.NewToolResultText("ok"))
+	}
+	record("get_astronomy", map[string]any{"location": "paris,fr"}, mcp.NewToolResultText("ok"))
+
+	locations := recent.get("session-a", recentLocations)
+	if len(locations) != maxRecentValues || locations[0] != "paris,fr" || locations[1] != "9,0" {
+		t.Errorf("Expected the newest %d locations without duplicates, got %v", maxRecentValues, locations)
+	}
+	if ips := recent.get("session-a", recentIPs); strings.Join(ips, ",") != "9.9.9.9" {
+		t.Errorf("Expected the looked up IP, got %v", ips)
+	}
+}
+
+// testSession is a minimal client session for hooks that read the session ID
+type testSession struct {
+	id string
+}
+
+func (s testSession) Initialize()                                         {}
+func (s testSession) Initialized() bool                                   { return true }
+func (s testSession) NotificationChannel() chan<- mcp.JSONRPCNotification { return nil }
+func (s testSession) SessionID() string                                   { return s.id }
+
+func TestCompletionMiddleware(t *testing.T) {
+	passed := false
+	handler := RPCMiddleware(CompletionMiddleware(nil, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		passed = true
+	})))
+
+	body := `{"jsonrpc":"2.0","id":7,"method":"completion/complete","params":{"ref":{"type":"ref/prompt","name":"plan_outdoor_activity"},"argument":{"name":"activity","value":"h"}}}`
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/mcp", strings.NewReader(body)))
+
+	var response struct {
+		ID     int `json:"id"`
+		Result struct {
+			Completion struct {
+				Values []string `json:"values"`
+			} `json:"completion"`
+		} `json:"result"`
+	}
+	if err := json.Unmarshal(recorder.Body.Bytes(), &response); err != nil || response.ID != 7 || strings.Join(response.Result.Completion.Values, ",") != "hiking" {
+		t.Errorf("Unexpected completion response: %s", recorder.Body.String())
+	}
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/mcp", strings.NewReader(`{"jsonrpc":"2.0","id":8,"method":"prompts/list"}`)))
+	if !passed {
+		t.Error("Expected other methods to pass through")
+	}
+}
@@ -0,0 +1,393 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const (
+	// methodElicitationCreate asks the client to collect input from the user; mcp-go has no
+	// support for it
+	methodElicitationCreate mcp.MCPMethod = "elicitation/create"
+
+	// elicitationTimeout bounds how long a tool call waits for the user to answer
+	elicitationTimeout = 5 * time.Minute
+
+	maxElicitationSessions = 1000
+)
+
+// elicitationAccept is the action of an elicitation answer the user submitted, as opposed to
+// "decline" or "cancel"
+const elicitationAccept = "accept"
+
+// errElicitationUnsupported is returned when the client did not declare the elicitation
+// capability, so tools fall back to their non-interactive behavior
+var errElicitationUnsupported = errors.New("the client does not support elicitation")
+
+// elicitationResult is the client's answer to an elicitation request
+type elicitationResult struct {
+	Action  string         `json:"action"`
+	Content map[string]any `json:"content,omitempty"`
+}
+
+// elicitationResponse is a client's JSON-RPC response to an elicitation request
+type elicitationResponse struct {
+	result *elicitationResult
+	err    error
+}
+
+// elicitationRegistry remembers which sessions support elicitation and routes the client's
+// responses to the tool calls waiting for them
+type elicitationRegistry struct {
+	mu       sync.Mutex
+	sessions map[string]time.Time // session ID -> when it was initialized
+	pending  map[string]chan elicitationResponse
+	nextID   int64
+}
+
+var elicitations = &elicitationRegistry{
+	sessions: make(map[string]time.Time),
+	pending:  make(map[string]chan elicitationResponse),
+}
+
+func (e *elicitationRegistry) addSession(sessionID string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if len(e.sessions) >= maxElicitationSessions {
+		var oldestID string
+		var oldest time.Time
+		for id, initialized := range e.sessions {
+			if oldestID == "" || initialized.Before(oldest) {
+				oldestID, oldest = id, initialized
+			}
+		}
+		delete(e.sessions, oldestID)
+	}
+	e.sessions[sessionID] = time.Now()
+}
+
+func (e *elicitationRegistry) removeSession(sessionID string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	delete(e.sessions, sessionID)
+}
+
+func (e *elicitationRegistry) supports(sessionID string) bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	_, ok := e.sessions[sessionID]
+	return ok
+}
+
+// register reserves a request ID for an elicitation and the channel its response is delivered on
+func (e *elicitationRegistry) register(sessionID string) (mcp.RequestId, chan elicitationResponse) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.nextID++
+	id := mcp.NewRequestId(fmt.Sprintf("elicitation-%d", e.nextID))
+	responses := make(chan elicitationResponse, 1)
+	e.pending[inFlightKey(sessionID, id)] = responses
+	return id, responses
+}
+
+func (e *elicitationRegistry) unregister(sessionID string, id mcp.RequestId) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	delete(e.pending, inFlightKey(sessionID, id))
+}
+
+// deliver hands a client response to the elicitation waiting for it, reporting whether there was one
+func (e *elicitationRegistry) deliver(sessionID string, message *rpcRequest) bool {
+	e.mu.Lock()
+	responses, ok := e.pending[inFlightKey(sessionID, message.ID)]
+	delete(e.pending, inFlightKey(sessionID, message.ID))
+	e.mu.Unlock()
+	if !ok {
+		return false
+	}
+
+	var response elicitationResponse
+	if len(message.Error) > 0 && string(message.Error) != "null" {
+		var rpcError struct {
+			Code    int    `json:"code"`
+			Message string `json:"message"`
+		}
+		json.Unmarshal(message.Error, &rpcError)
+		response.err = fmt.Errorf("elicitation error %d: %s", rpcError.Code, rpcError.Message)
+	} else {
+		response.result = &elicitationResult{}
+		if err := json.Unmarshal(message.Result, response.result); err != nil {
+			response.result, response.err = nil, fmt.Errorf("failed to parse elicitation result: %v", err)
+		}
+	}
+	responses <- response
+	return true
+}
+
+// eventStreamWriter lets the elicitation code write server-to-client requests into the SSE
+// response of a tool call that mcp-go is writing to at the same time. Each Write is one whole
+// event, so holding the lock per Write keeps events intact.
+type eventStreamWriter struct {
+	http.ResponseWriter
+
+	mu          sync.Mutex
+	wroteHeader bool
+}
+
+func (w *eventStreamWriter) WriteHeader(statusCode int) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.wroteHeader {
+		return
+	}
+	w.wroteHeader = true
+	w.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (w *eventStreamWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.wroteHeader = true
+	return w.ResponseWriter.Write(p)
+}
+
+func (w *eventStreamWriter) Flush() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// writeEvent writes a message as an SSE event, starting the event stream if nothing was written yet
+func (w *eventStreamWriter) writeEvent(message any) error {
+	data, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if !w.wroteHeader {
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Connection", "keep-alive")
+		w.Header().Set("Cache-Control", "no-cache")
+		w.ResponseWriter.WriteHeader(http.StatusOK)
+		w.wroteHeader = true
+	}
+	if _, err := fmt.Fprintf(w.ResponseWriter, "event: message\ndata: %s\n\n", data); err != nil {
+		return err
+	}
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+	return nil
+}
+
+// elicitor is how a tool call reaches the user: the session it belongs to and the event stream of
+// its response
+type elicitor struct {
+	sessionID string
+	writer    *eventStreamWriter
+}
+
+type elicitorKey struct{}
+
+// withoutElicitation keeps a context's handlers from asking the user anything, for work that runs
+// several lookups at once
+func withoutElicitation(ctx context.Context) context.Context {
+	return context.WithValue(ctx, elicitorKey{}, (*elicitor)(nil))
+}
+
+// elicit asks the user for input matching a flat JSON schema and waits for the answer. It returns
+// errElicitationUnsupported when the request cannot reach the user.
+func elicit(ctx context.Context, message string, schema map[string]any) (*elicitationResult, error) {
+	e, _ := ctx.Value(elicitorKey{}).(*elicitor)
+	if e == nil {
+		return nil, errElicitationUnsupported
+	}
+
+	// mcp-go writes the tool result as JSON unless the session was upgraded to an event stream
+	if session, ok := server.ClientSessionFromContext(ctx).(server.SessionWithStreamableHTTPConfig); ok {
+		session.UpgradeToSSEWhenReceiveNotification()
+	}
+
+	id, responses := elicitations.register(e.sessionID)
+	defer elicitations.unregister(e.sessionID, id)
+
+	request := mcp.JSONRPCRequest{
+		JSONRPC: mcp.JSONRPC_VERSION,
+		ID:      id,
+		Request: mcp.Request{Method: string(methodElicitationCreate)},
+		Params:  map[string]any{"message": message, "requestedSchema": schema},
+	}
+	if err := e.writer.writeEvent(request); err != nil {
+		return nil, fmt.Errorf("failed to send elicitation request: %v", err)
+	}
+
+	select {
+	case response := <-responses:
+		return response.result, response.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-time.After(elicitationTimeout):
+		return nil, fmt.Errorf("no answer to the elicitation request within %s", elicitationTimeout)
+	}
+}
+
+// ElicitationMiddleware lets tool calls ask the user for input, which mcp-go does not support: it
+// records which sessions declared the elicitation capability on initialize, gives their tool calls
+// an event stream to send elicitation/create requests on, and routes the client's responses back
+// to the waiting call. Everything else is passed on to the Streamable HTTP handler.
+func ElicitationMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sessionID := r.Header.Get(server.HeaderKeySessionID)
+		request := peekRPCRequest(r)
+
+		switch {
+		case request == nil:
+			next.ServeHTTP(w, r)
+
+		case request.Method == mcp.MethodInitialize:
+			var params struct {
+				Capabilities struct {
+					Elicitation json.RawMessage `json:"elicitation"`
+				} `json:"capabilities"`
+			}
+			json.Unmarshal(request.Params, &params)
+
+			next.ServeHTTP(w, r)
+			// The session ID is generated by mcp-go and only known from the response
+			if elicitation := params.Capabilities.Elicitation; len(elicitation) > 0 && string(elicitation) != "null" {
+				if id := w.Header().Get(server.HeaderKeySessionID); id != "" {
+					elicitations.addSession(id)
+				}
+			}
+
+		case request.Method == "" && !request.ID.IsNil() && (request.Result != nil || request.Error != nil):
+			if elicitations.deliver(sessionID, request) {
+				w.WriteHeader(http.StatusAccepted)
+				return
+			}
+			next.ServeHTTP(w, r)
+
+		case request.Method == mcp.MethodToolsCall && !request.ID.IsNil() && elicitations.supports(sessionID):
+			writer := &eventStreamWriter{ResponseWriter: w}
+			ctx := context.WithValue(r.Context(), elicitorKey{}, &elicitor{sessionID: sessionID, writer: writer})
+			next.ServeHTTP(writer, r.WithContext(ctx))
+
+		default:
+			next.ServeHTTP(w, r)
+		}
+	})
+}
+
+// resolveLocationInteractively resolves a location argument like ResolveLocation, asking the user
+// when the client supports elicitation: to pick one of several equally good geocoding matches, or
+// to name a location when the client IP cannot be located. Without elicitation, or when the user
+// declines, ambiguous names keep the best match and the IP failure is returned with a hint.
+func resolveLocationInteractively(ctx context.Context, location string) (*ResolvedLocation, error) {
+	resolved, err := ResolveLocation(ctx, location)
+	if err != nil {
+		if strings.TrimSpace(location) != "" {
+			return nil, err
+		}
+		return elicitLocation(ctx, err)
+	}
+	if len(resolved.Alternatives) > 0 {
+		return elicitLocationChoice(ctx, resolved), nil
+	}
+	return resolved, nil
+}
+
+// elicitLocation asks the user for the location, and optionally the units, that the client IP
+// could not provide
+func elicitLocation(ctx context.Context, cause error) (*ResolvedLocation, error) {
+	fallback := fmt.Errorf("%v. Pass a location argument, e.g. 'London' or '40.7128,-74.0060'", cause)
+
+	result, err := elicit(ctx, "Your location could not be determined from your IP address. Which location do you want the weather for?", map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"location": map[string]any{
+				"type":        "string",
+				"title":       "Location",
+				"description": "City name (e.g., 'London' or 'New York,US') or coordinates (e.g., '40.7128,-74.0060')",
+			},
+			"units": map[string]any{
+				"type":        "string",
+				"title":       "Units",
+				"description": "Unit system (optional): metric, imperial, standard or uk",
+				"enum":        []string{unitsMetric, unitsImperial, unitsStandard, unitsUK},
+			},
+		},
+		"required": []string{"location"},
+	})
+	if err != nil || result == nil || result.Action != elicitationAccept {
+		return nil, fallback
+	}
+
+	location, _ := result.Content["location"].(string)
+	if strings.TrimSpace(location) == "" {
+		return nil, fallback
+	}
+	resolved, err := ResolveLocation(ctx, location)
+	if err != nil {
+		return nil, err
+	}
+	if len(resolved.Alternatives) > 0 {
+		resolved = elicitLocationChoice(ctx, resolved)
+	}
+	if units, _ := result.Content["units"].(string); isValidUnits(units) {
+		resolved.Units = strings.ToLower(units)
+	}
+	return resolved, nil
+}
+
+// elicitLocationChoice asks the user which of the places an ambiguous name matches they meant,
+// keeping the best match if they do not answer
+func elicitLocationChoice(ctx context.Context, resolved *ResolvedLocation) *ResolvedLocation {
+	options := []string{resolved.Name}
+	for _, alternative := range resolved.Alternatives {
+		options = append(options, alternative.DisplayName())
+	}
+
+	result, err := elicit(ctx, fmt.Sprintf("%q matches several places. Which one did you mean?", resolved.Query), map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"location": map[string]any{
+				"type":  "string",
+				"title": "Location",
+				"enum":  options,
+			},
+		},
+		"required": []string{"location"},
+	})
+	if err != nil || result == nil || result.Action != elicitationAccept {
+		return resolved
+	}
+
+	choice, _ := result.Content["location"].(string)
+	if choice == resolved.Name {
+		chosen := *resolved
+		chosen.Query = choice
+		chosen.Alternatives = nil
+		return &chosen
+	}
+	for _, alternative := range resolved.Alternatives {
+		if alternative.DisplayName() == choice {
+			return resolvedFromCandidate(choice, alternative)
+		}
+	}
+	return resolved
+}
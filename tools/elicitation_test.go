@@ -0,0 +1,202 @@
+package tools
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// elicitationClient talks to a Streamable HTTP server with get_weather registered, behind
+// ElicitationMiddleware, as a client that declared the elicitation capability
+type elicitationClient struct {
+	t         *testing.T
+	url       string
+	sessionID string
+}
+
+func newElicitationClient(t *testing.T) *elicitationClient {
+	t.Helper()
+
+	mcpServer := server.NewMCPServer("test", "1.0.0", server.WithToolCapabilities(false))
+	weatherTool := NewWeatherTool()
+	mcpServer.AddTool(weatherTool.Tool, weatherTool.Handler)
+	httpServer := httptest.NewServer(RPCMiddleware(ElicitationMiddleware(server.NewStreamableHTTPServer(mcpServer))))
+	t.Cleanup(httpServer.Close)
+
+	client := &elicitationClient{t: t, url: httpServer.URL}
+	response := client.post(`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-06-18","capabilities":{"elicitation":{}},"clientInfo":{"name":"test","version":"1.0.0"}}}`)
+	response.Body.Close()
+	client.sessionID = response.Header.Get(server.HeaderKeySessionID)
+	if client.sessionID == "" {
+		t.Fatal("Expected a session ID from initialize")
+	}
+	return client
+}
+
+func (c *elicitationClient) post(body string) *http.Response {
+	c.t.Helper()
+
+	request, _ := http.NewRequest(http.MethodPost, c.url, strings.NewReader(body))
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("Accept", "application/json, text/event-stream")
+	if c.sessionID != "" {
+		request.Header.Set(server.HeaderKeySessionID, c.sessionID)
+	}
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		c.t.Fatalf("Request failed: %v", err)
+	}
+	return response
+}
+
+// callWeather calls get_weather and answers every elicitation request with answer, returning the
+// elicitation requests and the tool result text
+func (c *elicitationClient) callWeather(arguments string, answer func(params map[string]any) string) ([]map[string]any, *mcp.CallToolResult) {
+	c.t.Helper()
+
+	response := c.post(`{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"get_weather","arguments":` + arguments + `}}`)
+	defer response.Body.Close()
+
+	var elicitations []map[string]any
+	scanner := bufio.NewScanner(response.Body)
+	scanner.Buffer(make([]byte, 1024*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.HasPrefix(line, "{") {
+			line = "data: " + line // a plain JSON response when nothing was elicited
+		}
+		if !strings.HasPrefix(line, "data: ") {
+			continue
+		}
+
+		var message struct {
+			ID     any                `json:"id"`
+			Method string             `json:"method"`
+			Params map[string]any     `json:"params"`
+			Result mcp.CallToolResult `json:"result"`
+		}
+		if err := json.Unmarshal([]byte(strings.TrimPrefix(line, "data: ")), &message); err != nil {
+			c.t.Fatalf("Failed to parse %s: %v", line, err)
+		}
+
+		switch message.Method {
+		case string(methodElicitationCreate):
+			elicitations = append(elicitations, message.Params)
+			id, _ := json.Marshal(message.ID)
+			reply := c.post(`{"jsonrpc":"2.0","id":` + string(id) + `,"result":` + answer(message.Params) + `}`)
+			reply.Body.Close()
+			if reply.StatusCode != http.StatusAccepted {
+				c.t.Fatalf("Expected the elicitation response to be accepted, got status %d", reply.StatusCode)
+			}
+		case "":
+			return elicitations, &message.Result
+		}
+	}
+	c.t.Fatal("The tool call ended without a result")
+	return nil, nil
+}
+
+func TestElicitation_ClientIPUnavailable(t *testing.T) {
+	os.Setenv("OPENWEATHER_API_KEY", "test_api_key")
+	defer os.Unsetenv("OPENWEATHER_API_KEY")
+	newOpenWeatherMapTestServer(t)
+	client := newElicitationClient(t)
+
+	requests, result := client.callWeather(`{}`, func(params map[string]any) string {
+		return `{"action":"accept","content":{"location":"39.4697,-0.3763","units":"imperial"}}`
+	})
+	if len(requests) != 1 || !strings.Contains(requests[0]["message"].(string), "could not be determined") {
+		t.Fatalf("Expected one request for the location, got %v", requests)
+	}
+	if result.IsError || !strings.Contains(resultText(result), "°F") {
+		t.Errorf("Expected the weather in imperial units, got: %s", resultText(result))
+	}
+
+	// Declining falls back to the error, with a hint
+	_, result = client.callWeather(`{}`, func(params map[string]any) string {
+		return `{"action":"decline"}`
+	})
+	if !result.IsError || !strings.Contains(resultText(result), "Pass a location argument") {
+		t.Errorf("Expected an error asking for a location, got: %s", resultText(result))
+	}
+}
+
+func TestElicitation_AmbiguousLocation(t *testing.T) {
+	os.Setenv("OPENWEATHER_API_KEY", "test_api_key")
+	defer os.Unsetenv("OPENWEATHER_API_KEY")
+
+	var mu sync.Mutex
+	var latitudes []string
+	owmServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/geo/1.0/direct":
+			w.Write(loadFixture(t, "owm_geocoding_direct_response.json"))
+		case "/data/2.5/weather":
+			mu.Lock()
+			latitudes = append(latitudes, r.URL.Query().Get("lat"))
+			mu.Unlock()
+			w.Write(loadFixture(t, "valencia_weather_response.json"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer owmServer.Close()
+	originalURL := openWeatherMapBaseURL
+	openWeatherMapBaseURL = owmServer.URL
+	defer func() { openWeatherMapBaseURL = originalURL }()
+
+	client := newElicitationClient(t)
+	requests, result := client.callWeather(`{"location":"Springfield"}`, func(params map[string]any) string {
+		return `{"action":"accept","content":{"location":"Springfield, Missouri, US"}}`
+	})
+	if len(requests) != 1 {
+		t.Fatalf("Expected one request to pick a place, got %v", requests)
+	}
+	options := requests[0]["requestedSchema"].(map[string]any)["properties"].(map[string]any)["location"].(map[string]any)["enum"].([]any)
+	if len(options) < 2 || options[0] != "Springfield, Illinois, US" || options[1] != "Springfield, Missouri, US" {
+		t.Errorf("Expected the matching places as options, got %v", options)
+	}
+	if result.IsError || strings.Contains(resultText(result), "matches several places") {
+		t.Errorf("Expected the weather without an ambiguity note, got: %s", resultText(result))
+	}
+
+	// Cancelling keeps the best match and the note
+	_, result = client.callWeather(`{"location":"Springfield"}`, func(params map[string]any) string {
+		return `{"action":"cancel"}`
+	})
+	if result.IsError || !strings.Contains(resultText(result), "matches several places") {
+		t.Errorf("Expected the best match with an ambiguity note, got: %s", resultText(result))
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(latitudes) != 2 || latitudes[0] != "37.2082" || latitudes[1] != "39.7990" {
+		t.Errorf("Expected the chosen place and then the best match, got latitudes %v", latitudes)
+	}
+}
+
+func TestElicitation_Unsupported(t *testing.T) {
+	os.Setenv("OPENWEATHER_API_KEY", "test_api_key")
+	defer os.Unsetenv("OPENWEATHER_API_KEY")
+	newOpenWeatherMapTestServer(t)
+
+	request := mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: map[string]any{}}}
+	result, _ := weatherToolHandler(context.Background(), request)
+	if !result.IsError || !strings.Contains(resultText(result), "could not determine client IP address") ||
+		!strings.Contains(resultText(result), "Pass a location argument") {
+		t.Errorf("Expected the IP error with a hint, got: %s", resultText(result))
+	}
+
+	if _, err := elicit(context.Background(), "Which location?", nil); err != errElicitationUnsupported {
+		t.Errorf("Expected errElicitationUnsupported, got %v", err)
+	}
+}
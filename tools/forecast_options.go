@@ -0,0 +1,360 @@
+package tools
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const (
+	granularity3Hourly = "3h"
+	granularityDaily   = "daily"
+	granularityDaypart = "daypart"
+
+	defaultForecastHours = 24
+	defaultForecastDays  = 5
+	maxForecastHours     = 120
+)
+
+const (
+	fieldTemperature   = "temperature"
+	fieldFeelsLike     = "feels_like"
+	fieldConditions    = "conditions"
+	fieldPrecipitation = "precipitation"
+	fieldWind          = "wind"
+	fieldHumidity      = "humidity"
+	fieldPressure      = "pressure"
+	fieldClouds        = "clouds"
+	fieldDewPoint      = "dew_point"
+	fieldApparent      = "apparent"
+	fieldBeaufort      = "beaufort"
+	fieldVisibility    = "visibility"
+)
+
+var forecastFields = []string{
+	fieldTemperature, fieldFeelsLike, fieldConditions, fieldPrecipitation, fieldWind, fieldHumidity, fieldPressure, fieldClouds,
+	fieldDewPoint, fieldApparent, fieldBeaufort, fieldVisibility,
+}
+
+// defaultForecastFields are the fields shown when the caller doesn't pick any
+var defaultForecastFields = []string{fieldTemperature, fieldConditions, fieldPrecipitation}
+
+// forecastNow is the clock a start date is checked against; tests replace it
+var forecastNow = time.Now
+
+// westernmostZone is the last timezone to reach a date, so a date before today there is in the
+// past everywhere
+var westernmostZone = time.FixedZone("UTC-12", -12*60*60)
+
+var weekdays = map[string]time.Weekday{
+	"sunday": time.Sunday, "monday": time.Monday, "tuesday": time.Tuesday, "wednesday": time.Wednesday,
+	"thursday": time.Thursday, "friday": time.Friday, "saturday": time.Saturday,
+}
+
+// ForecastOptions controls which part of a forecast is shown and how it is grouped.
+// An empty Granularity prints the 3-hour slots followed by daily summaries.
+type ForecastOptions struct {
+	Hours       int
+	Days        int
+	Granularity string
+	Start       string
+	Fields      map[string]bool
+}
+
+func defaultForecastOptions() ForecastOptions {
+	return ForecastOptions{
+		Hours:  defaultForecastHours,
+		Days:   defaultForecastDays,
+		Fields: fieldSet(defaultForecastFields),
+	}
+}
+
+func fieldSet(fields []string) map[string]bool {
+	set := make(map[string]bool, len(fields))
+	for _, field := range fields {
+		set[field] = true
+	}
+	return set
+}
+
+// parseForecastOptions reads the horizon, granularity, start and fields arguments of get_weather_forecast
+func parseForecastOptions(request mcp.CallToolRequest) (ForecastOptions, error) {
+	opts := defaultForecastOptions()
+
+	opts.Hours = request.GetInt("hours", defaultForecastHours)
+	if opts.Hours < 3 {
+		opts.Hours = 3
+	} else if opts.Hours > maxForecastHours {
+		opts.Hours = maxForecastHours
+	}
+
+	opts.Days = request.GetInt("days", defaultForecastDays)
+	if opts.Days < 1 {
+		opts.Days = 1
+	} else if opts.Days > defaultForecastDays {
+		opts.Days = defaultForecastDays
+	}
+
+	opts.Granularity = strings.ToLower(request.GetString("granularity", ""))
+	switch opts.Granularity {
+	case "", granularity3Hourly, granularityDaily, granularityDaypart:
+	default:
+		return opts, fmt.Errorf("invalid granularity %q: must be one of 3h, daily, daypart", opts.Granularity)
+	}
+
+	opts.Start = strings.ToLower(strings.TrimSpace(request.GetString("start", "")))
+	if !isValidForecastStart(opts.Start) {
+		return opts, fmt.Errorf("invalid start %q: use 'now', 'today', 'tomorrow', a weekday name or a date (YYYY-MM-DD)", opts.Start)
+	}
+	if date, err := time.Parse(time.DateOnly, opts.Start); err == nil {
+		now := forecastNow().In(westernmostZone)
+		if date.Before(time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)) {
+			return opts, fmt.Errorf("start %s is out of range: the forecast begins today and runs for %d days", opts.Start, defaultForecastDays)
+		}
+	}
+
+	if fields := request.GetStringSlice("fields", nil); len(fields) > 0 {
+		valid := fieldSet(forecastFields)
+		for _, field := range fields {
+			if !valid[field] {
+				return opts, fmt.Errorf("invalid field %q: must be one of %s", field, strings.Join(forecastFields, ", "))
+			}
+		}
+		opts.Fields = fieldSet(fields)
+	}
+
+	return opts, nil
+}
+
+func isValidForecastStart(start string) bool {
+	switch start {
+	case "", "now", "today", "tomorrow":
+		return true
+	}
+	if _, ok := weekdays[start]; ok {
+		return true
+	}
+	_, err := time.Parse(time.DateOnly, start)
+	return err == nil
+}
+
+// forecastStart resolves the start argument against the first forecast time, which stands in for
+// "now" in the location's timezone. It reports whether the start is later than the first slot.
+func forecastStart(start string, first time.Time) (time.Time, bool) {
+	loc := first.Location()
+	midnight := time.Date(first.Year(), first.Month(), first.Day(), 0, 0, 0, 0, loc)
+
+	switch start {
+	case "", "now", "today":
+		return first, false
+	case "tomorrow":
+		return midnight.AddDate(0, 0, 1), true
+	}
+
+	if weekday, ok := weekdays[start]; ok {
+		offset := (int(weekday) - int(first.Weekday()) + 7) % 7
+		if offset == 0 {
+			return first, false
+		}
+		return midnight.AddDate(0, 0, offset), true
+	}
+
+	date, err := time.ParseInLocation(time.DateOnly, start, loc)
+	if err != nil || !date.After(first) {
+		return first, false
+	}
+	return date, true
+}
+
+// dayPart names the quarter of the day a local time falls in
+func dayPart(t time.Time) string {
+	switch hour := t.Hour(); {
+	case hour < 6:
+		return "Night"
+	case hour < 12:
+		return "Morning"
+	case hour < 18:
+		return "Afternoon"
+	default:
+		return "Evening"
+	}
+}
+
+// forecastGroup is a run of slots summarized together: a whole day, or one part of a day
+type forecastGroup struct {
+	Label string
+	Date  string
+	Part  string
+	Items []ForecastItem
+}
+
+// forecastSelection is the part of a forecast chosen by ForecastOptions, in the city's local time.
+// Start is only set when it is later than the first slot, and End is the last slot's time.
+type forecastSelection struct {
+	Location   *time.Location
+	Start      time.Time
+	End        time.Time
+	StartLabel string
+	Empty      string
+	Slots      []ForecastItem
+	Groups     []forecastGroup
+}
+
+// selectForecast applies the start, hours, days and granularity options to a forecast. Slots are
+// only filled when the granularity shows them, and Empty explains why nothing is left after start.
+func selectForecast(data ForecastData, opts ForecastOptions) forecastSelection {
+	// Times and day boundaries follow the forecast city's clock, not the server's
+	selection := forecastSelection{Location: offsetLocation(data.City.Timezone)}
+	if len(data.List) == 0 {
+		return selection
+	}
+
+	loc := selection.Location
+	first := time.Unix(data.List[0].Dt, 0).In(loc)
+	start, later := forecastStart(opts.Start, first)
+
+	items := data.List
+	selection.End = time.Unix(data.List[len(data.List)-1].Dt, 0).In(loc)
+	if later {
+		selection.Start = start
+		selection.StartLabel = start.Format("Mon Jan 2")
+		items = forecastItemsFrom(items, start)
+	}
+	if len(items) == 0 {
+		selection.Empty = fmt.Sprintf("No forecast data available from %s; the forecast runs until %s.", selection.StartLabel, selection.End.Format("Mon Jan 2 3:04 PM"))
+		return selection
+	}
+
+	if opts.Granularity == "" || opts.Granularity == granularity3Hourly {
+		slotsEnd := time.Unix(items[0].Dt, 0).Add(time.Duration(opts.Hours) * time.Hour)
+		for _, item := range items {
+			if !time.Unix(item.Dt, 0).Before(slotsEnd) {
+				break
+			}
+			selection.Slots = append(selection.Slots, item)
+		}
+	}
+
+	if opts.Granularity != granularity3Hourly {
+		groupsEnd := time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, loc).AddDate(0, 0, opts.Days)
+		for _, item := range items {
+			timestamp := time.Unix(item.Dt, 0).In(loc)
+			if !timestamp.Before(groupsEnd) {
+				break
+			}
+
+			group := forecastGroup{Label: timestamp.Format("Mon Jan 2"), Date: timestamp.Format(time.DateOnly)}
+			if opts.Granularity == granularityDaypart {
+				group.Part = dayPart(timestamp)
+				group.Label = fmt.Sprintf("%s %s", group.Label, group.Part)
+			}
+
+			if n := len(selection.Groups); n > 0 && selection.Groups[n-1].Label == group.Label {
+				selection.Groups[n-1].Items = append(selection.Groups[n-1].Items, item)
+				continue
+			}
+			group.Items = []ForecastItem{item}
+			selection.Groups = append(selection.Groups, group)
+		}
+	}
+
+	return selection
+}
+
+// forecastItemsFrom drops the forecast slots before start
+func forecastItemsFrom(items []ForecastItem, start time.Time) []ForecastItem {
+	for i, item := range items {
+		if !time.Unix(item.Dt, 0).Before(start) {
+			return items[i:]
+		}
+	}
+	return nil
+}
+
+// forecastStats condenses several slots into ranges, averages, peaks and the most common condition.
+// Values are metric, like the slots they come from.
+type forecastStats struct {
+	MinTemp      float64
+	MaxTemp      float64
+	MinFeelsLike float64
+	MaxFeelsLike float64
+	MaxPop       float64
+	MaxWind      float64
+	Humidity     float64
+	Pressure     float64
+	Clouds       float64
+	Condition    string
+	MaxDewPoint  float64
+	MinApparent  float64
+	MaxApparent  float64
+
+	// Rain and Snow are accumulations over all slots, and PressureChange runs from the first slot
+	// to the last, Hours apart
+	Rain           float64
+	Snow           float64
+	MaxGust        float64
+	MinVisibility  float64
+	PressureChange float64
+	Hours          float64
+}
+
+func summarizeForecastItems(items []ForecastItem) forecastStats {
+	stats := forecastStats{
+		MinTemp:      items[0].Main.TempMin,
+		MaxTemp:      items[0].Main.TempMax,
+		MinFeelsLike: items[0].Main.FeelsLike,
+		MaxFeelsLike: items[0].Main.FeelsLike,
+		MaxWind:      items[0].Wind.Speed,
+	}
+	last := items[len(items)-1]
+	stats.MinVisibility = float64(items[0].Visibility)
+	stats.PressureChange = float64(last.Main.Pressure - items[0].Main.Pressure)
+	stats.Hours = float64(last.Dt-items[0].Dt) / 3600
+
+	first := items[0].Metrics()
+	stats.MaxDewPoint, stats.MinApparent, stats.MaxApparent = first.DewPoint, first.Apparent, first.Apparent
+	conditionCount := make(map[string]int)
+
+	for _, item := range items {
+		stats.MinTemp = min(stats.MinTemp, item.Main.TempMin)
+		stats.MaxTemp = max(stats.MaxTemp, item.Main.TempMax)
+		stats.MinFeelsLike = min(stats.MinFeelsLike, item.Main.FeelsLike)
+		stats.MaxFeelsLike = max(stats.MaxFeelsLike, item.Main.FeelsLike)
+		stats.MaxPop = max(stats.MaxPop, item.Pop)
+		stats.MaxWind = max(stats.MaxWind, item.Wind.Speed)
+		stats.Humidity += float64(item.Main.Humidity)
+		stats.Pressure += float64(item.Main.Pressure)
+		stats.Clouds += float64(item.Clouds.All)
+
+		stats.MaxGust = max(stats.MaxGust, item.Wind.Gust)
+		stats.MinVisibility = min(stats.MinVisibility, float64(item.Visibility))
+		if item.Rain != nil {
+			stats.Rain += item.Rain.ThreeH
+		}
+		if item.Snow != nil {
+			stats.Snow += item.Snow.ThreeH
+		}
+
+		metrics := item.Metrics()
+		stats.MaxDewPoint = max(stats.MaxDewPoint, metrics.DewPoint)
+		stats.MinApparent = min(stats.MinApparent, metrics.Apparent)
+		stats.MaxApparent = max(stats.MaxApparent, metrics.Apparent)
+
+		if len(item.Weather) > 0 {
+			condition := item.Weather[0].Main
+			conditionCount[condition]++
+			if conditionCount[condition] > conditionCount[stats.Condition] {
+				stats.Condition = condition
+			}
+		}
+	}
+
+	count := float64(len(items))
+	stats.Humidity /= count
+	stats.Pressure /= count
+	stats.Clouds /= count
+
+	return stats
+}
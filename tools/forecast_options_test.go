@@ -0,0 +1,186 @@
+package tools
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func forecastOptionsRequest(args map[string]any) mcp.CallToolRequest {
+	return mcp.CallToolRequest{
+		Params: mcp.CallToolParams{
+			Arguments: args,
+		},
+	}
+}
+
+func TestParseForecastOptions(t *testing.T) {
+	opts, err := parseForecastOptions(forecastOptionsRequest(map[string]any{}))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if opts.Hours != 24 || opts.Days != 5 || opts.Granularity != "" || !opts.Fields[fieldTemperature] || opts.Fields[fieldWind] {
+		t.Errorf("Unexpected default options: %+v", opts)
+	}
+
+	opts, err = parseForecastOptions(forecastOptionsRequest(map[string]any{
+		"hours":       500,
+		"days":        0,
+		"granularity": "daypart",
+		"start":       "Saturday",
+		"fields":      []any{"wind", "humidity"},
+	}))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if opts.Hours != 120 || opts.Days != 1 || opts.Granularity != granularityDaypart || opts.Start != "saturday" {
+		t.Errorf("Unexpected options: %+v", opts)
+	}
+	if !opts.Fields[fieldWind] || !opts.Fields[fieldHumidity] || opts.Fields[fieldTemperature] {
+		t.Errorf("Unexpected fields: %v", opts.Fields)
+	}
+
+	invalid := []struct {
+		args map[string]any
+		want string
+	}{
+		{map[string]any{"granularity": "hourly"}, "invalid granularity"},
+		{map[string]any{"start": "next week"}, "invalid start"},
+		{map[string]any{"fields": []any{"uv"}}, "invalid field"},
+		{map[string]any{"start": "2020-01-01"}, "start 2020-01-01 is out of range"},
+	}
+	for _, tc := range invalid {
+		if _, err := parseForecastOptions(forecastOptionsRequest(tc.args)); err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("parseForecastOptions(%v) error = %v, expected %q", tc.args, err, tc.want)
+		}
+	}
+
+	// A date that is still today anywhere is in range
+	forecastNow = func() time.Time { return time.Date(2024, 6, 15, 3, 0, 0, 0, time.UTC) }
+	defer func() { forecastNow = time.Now }()
+	if _, err := parseForecastOptions(forecastOptionsRequest(map[string]any{"start": "2024-06-14"})); err != nil {
+		t.Errorf("Expected yesterday in UTC to be in range, got %v", err)
+	}
+	if _, err := parseForecastOptions(forecastOptionsRequest(map[string]any{"start": "2024-06-13"})); err == nil {
+		t.Error("Expected a date past in every timezone to be out of range")
+	}
+}
+
+func TestForecastStart(t *testing.T) {
+	loc := offsetLocation(7200)
+	first := time.Date(2025, 9, 2, 11, 0, 0, 0, loc) // a Tuesday
+
+	testCases := []struct {
+		start    string
+		expected time.Time
+		later    bool
+	}{
+		{"", first, false},
+		{"today", first, false},
+		{"tomorrow", time.Date(2025, 9, 3, 0, 0, 0, 0, loc), true},
+		{"tuesday", first, false},
+		{"saturday", time.Date(2025, 9, 6, 0, 0, 0, 0, loc), true},
+		{"2025-09-04", time.Date(2025, 9, 4, 0, 0, 0, 0, loc), true},
+		{"2025-09-01", first, false},
+	}
+
+	for _, tc := range testCases {
+		got, later := forecastStart(tc.start, first)
+		if !got.Equal(tc.expected) || later != tc.later {
+			t.Errorf("forecastStart(%q) = %v, %v, expected %v, %v", tc.start, got, later, tc.expected, tc.later)
+		}
+	}
+}
+
+func TestFormatForecastWithOptions(t *testing.T) {
+	forecastData := loadForecastFixture(t, "valencia_forecast_response.json")
+
+	t.Run("WednesdayByPartOfDay", func(t *testing.T) {
+		opts := defaultForecastOptions()
+		opts.Granularity = granularityDaypart
+		opts.Start = "wednesday"
+		opts.Days = 1
+
+		result := FormatForecastWithOptions(forecastData, "Valencia", "metric", "en", opts)
+
+		if !strings.Contains(result, "## Forecast by Part of Day") || !strings.Contains(result, "**Wed Sep 3 Night**:") || !strings.Contains(result, "**Wed Sep 3 Morning**:") {
+			t.Errorf("Expected Wednesday part-of-day summaries:\n%s", result)
+		}
+		if strings.Contains(result, "Tue Sep 2") || strings.Contains(result, "Fri Sep 5") {
+			t.Errorf("Expected only Wednesday in output:\n%s", result)
+		}
+		if strings.Contains(result, "## Next") {
+			t.Errorf("Daypart granularity should not list 3-hour slots:\n%s", result)
+		}
+	})
+
+	t.Run("HourlyWithFields", func(t *testing.T) {
+		opts := defaultForecastOptions()
+		opts.Granularity = granularity3Hourly
+		opts.Hours = 6
+		opts.Fields = fieldSet([]string{fieldTemperature, fieldWind, fieldHumidity})
+
+		result := FormatForecastWithOptions(forecastData, "Valencia", "metric", "en", opts)
+
+		if !strings.Contains(result, "## Next 6 Hours") {
+			t.Errorf("Missing 3-hour section header:\n%s", result)
+		}
+		if got := strings.Count(result, "**Tue "); got != 2 {
+			t.Errorf("Expected 2 slots in 6 hours, got %d:\n%s", got, result)
+		}
+		if !strings.Contains(result, "wind ") || !strings.Contains(result, "humidity ") {
+			t.Errorf("Expected wind and humidity fields:\n%s", result)
+		}
+		if strings.Contains(result, "Clear Sky") || strings.Contains(result, "Day Forecast") {
+			t.Errorf("Unexpected conditions or daily summaries:\n%s", result)
+		}
+	})
+
+	t.Run("DayWithoutData", func(t *testing.T) {
+		opts := defaultForecastOptions()
+		opts.Granularity = granularityDaily
+		opts.Start = "thursday"
+		opts.Days = 1
+
+		result := FormatForecastWithOptions(forecastData, "Valencia", "metric", "en", opts)
+
+		if !strings.Contains(result, "No forecast data available for the requested days.") || strings.Contains(result, "Fri Sep 5") {
+			t.Errorf("Expected no summaries for a day without slots:\n%s", result)
+		}
+	})
+
+	t.Run("StartBeyondForecast", func(t *testing.T) {
+		opts := defaultForecastOptions()
+		opts.Start = "2025-09-20"
+
+		result := FormatForecastWithOptions(forecastData, "Valencia", "metric", "en", opts)
+
+		if !strings.Contains(result, "No forecast data available from Sat Sep 20") {
+			t.Errorf("Expected out of range message:\n%s", result)
+		}
+	})
+}
+
+func TestNWSForecast_StartAndDays(t *testing.T) {
+	newNWSTestServer(t)
+
+	forecastTool := NewWeatherForecastTool()
+	content, isError := callToolText(t, forecastTool.Handler, map[string]any{
+		"location": "40.7128,-74.0060",
+		"provider": "nws",
+		"start":    "wednesday",
+		"days":     1,
+	})
+	if isError {
+		t.Fatalf("Expected success, got error: %s", content)
+	}
+
+	if !strings.Contains(content, "**Wednesday**:") || !strings.Contains(content, "**Wednesday Night**:") {
+		t.Errorf("Expected Wednesday periods:\n%s", content)
+	}
+	if strings.Contains(content, "**Today**:") || strings.Contains(content, "**Tonight**:") {
+		t.Errorf("Expected periods before the start to be dropped:\n%s", content)
+	}
+}
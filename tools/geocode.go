@@ -0,0 +1,346 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// openWeatherMapBaseURL is the OpenWeatherMap API endpoint (overridden in tests)
+var openWeatherMapBaseURL = "https://api.openweathermap.org"
+
+// openMeteoGeocodingBaseURL is the keyless Open-Meteo geocoding endpoint (overridden in tests)
+var openMeteoGeocodingBaseURL = "https://geocoding-api.open-meteo.com"
+
+const defaultGeocodeLimit = 5
+
+type GeocodeTool struct {
+	Tool    mcp.Tool
+	Handler func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error)
+}
+
+// GeoLocation is a single geocoding candidate normalized across providers
+type GeoLocation struct {
+	Name        string  `json:"name"`
+	State       string  `json:"state,omitempty"`
+	Country     string  `json:"country"`
+	CountryName string  `json:"countryName,omitempty"`
+	Lat         float64 `json:"lat"`
+	Lon         float64 `json:"lon"`
+	Timezone    string  `json:"timezone,omitempty"`
+	Population  int     `json:"population,omitempty"`
+
+	localName string // Name in the requested language, used once the candidates are ranked
+}
+
+// DisplayName renders the candidate as "Name, State, Country", skipping empty parts
+func (l GeoLocation) DisplayName() string {
+	parts := []string{l.Name}
+	if l.State != "" && l.State != l.Name {
+		parts = append(parts, l.State)
+	}
+	if l.Country != "" {
+		parts = append(parts, l.Country)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// GeocodeResult is the structured form of the geocode tool's answer
+type GeocodeResult struct {
+	Query      string        `json:"query"`
+	Reverse    bool          `json:"reverse"`
+	Candidates []GeoLocation `json:"candidates"`
+}
+
+// CompactSummary lists the candidates on one line, best match first
+func (r GeocodeResult) CompactSummary() string {
+	if len(r.Candidates) == 0 {
+		return fmt.Sprintf("%s: no matching locations", r.Query)
+	}
+	entries := make([]string, 0, len(r.Candidates))
+	for _, candidate := range r.Candidates {
+		entries = append(entries, fmt.Sprintf("%s (%.4f,%.4f)", candidate.DisplayName(), candidate.Lat, candidate.Lon))
+	}
+	return fmt.Sprintf("%s: %s", r.Query, strings.Join(entries, "; "))
+}
+
+type owmGeocodingResult struct {
+	Name       string            `json:"name"`
+	LocalNames map[string]string `json:"local_names"`
+	Lat        float64           `json:"lat"`
+	Lon        float64           `json:"lon"`
+	Country    string            `json:"country"`
+	State      string            `json:"state"`
+}
+
+type openMeteoGeocodingResponse struct {
+	Results []struct {
+		ID          int     `json:"id"`
+		Name        string  `json:"name"`
+		Latitude    float64 `json:"latitude"`
+		Longitude   float64 `json:"longitude"`
+		CountryCode string  `json:"country_code"`
+		Country     string  `json:"country"`
+		Admin1      string  `json:"admin1"`
+		Timezone    string  `json:"timezone"`
+		Population  int     `json:"population"`
+	} `json:"results"`
+}
+
+func NewGeocodeTool() *GeocodeTool {
+	return &GeocodeTool{
+		Tool:    geocodeTool(),
+		Handler: geocodeToolHandler,
+	}
+}
+
+func geocodeTool() mcp.Tool {
+	return mcp.NewTool("geocode",
+		mcp.WithDescription("Look up coordinates for a place name, or the place name for 'lat,lon' coordinates (reverse geocoding). Returns ranked candidates with state and country so ambiguous names can be disambiguated"),
+		readOnlyTool("Geocoding", true),
+		mcp.WithString("query",
+			mcp.Required(),
+			mcp.Description("Place name to search for (e.g., 'Springfield', 'Springfield,MO,US', 'São Paulo') or coordinates to reverse geocode (e.g., '40.7128,-74.0060')"),
+		),
+		mcp.WithNumber("limit",
+			mcp.Description("Maximum number of candidates to return (optional, 1-5, default 5)"),
+			mcp.Min(1),
+			mcp.Max(5),
+		),
+		withFormat(),
+		withLanguage(),
+		mcp.WithOutputSchema[GeocodeResult](),
+	)
+}
+
+func geocodeToolHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	query, err := request.RequireString("query")
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	format, err := parseFormat(request)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	limit := request.GetInt("limit", defaultGeocodeLimit)
+	if limit < 1 || limit > defaultGeocodeLimit {
+		limit = defaultGeocodeLimit
+	}
+
+	lang, err := resolveLanguage(ctx, request, nil)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	if lat, lon, ok := parseCoordinates(query); ok {
+		candidates, err := ReverseGeocode(ctx, lat, lon, limit, lang)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		return newGeocodeResult(candidates, query, true, format, lang), nil
+	}
+
+	candidates, err := GeocodeLocation(ctx, query, limit, lang)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	return newGeocodeResult(candidates, query, false, format, lang), nil
+}
+
+func newGeocodeResult(candidates []GeoLocation, query string, reverse bool, format string, lang string) *mcp.CallToolResult {
+	structured := GeocodeResult{Query: query, Reverse: reverse, Candidates: candidates}
+	if structured.Candidates == nil {
+		structured.Candidates = []GeoLocation{}
+	}
+	return renderResult(format, FormatGeocodingAsMarkdown(candidates, query, reverse, lang), structured)
+}
+
+// GeocodeLocation searches for places matching query and returns them best match first.
+// OpenWeatherMap is used when an API key is configured, otherwise the keyless Open-Meteo service.
+// Candidates are ranked on the provider's own names and then named in lang where the provider
+// knows a local name; an empty lang keeps the provider's names.
+func GeocodeLocation(ctx context.Context, query string, limit int, lang string) ([]GeoLocation, error) {
+	name, qualifiers := splitLocationQuery(query)
+	if name == "" {
+		return nil, fmt.Errorf("location query is empty")
+	}
+
+	var candidates []GeoLocation
+	var err error
+	if apiKey := os.Getenv("OPENWEATHER_API_KEY"); apiKey != "" {
+		candidates, err = geocodeWithOpenWeatherMap(ctx, query, apiKey, limit, lang)
+	} else {
+		candidates, err = geocodeWithOpenMeteo(ctx, name, qualifiers, limit, lang)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return localizeNames(rankCandidates(name, qualifiers, candidates)), nil
+}
+
+// ReverseGeocode returns the named places nearest to the coordinates, named in lang where known.
+// It requires an OpenWeatherMap API key.
+func ReverseGeocode(ctx context.Context, lat, lon float64, limit int, lang string) ([]GeoLocation, error) {
+	apiKey, err := validateAPIKey()
+	if err != nil {
+		return nil, err
+	}
+
+	reverseURL := fmt.Sprintf("%s/geo/1.0/reverse?lat=%.4f&lon=%.4f&limit=%d&appid=%s", openWeatherMapBaseURL, lat, lon, limit, apiKey)
+
+	var results []owmGeocodingResult
+	if err := fetchGeocodingData(ctx, reverseURL, &results); err != nil {
+		return nil, err
+	}
+
+	return localizeNames(convertOWMGeocodingResults(results, lang)), nil
+}
+
+// localizeNames replaces the names of candidates with their local names, where there are any
+func localizeNames(candidates []GeoLocation) []GeoLocation {
+	for i := range candidates {
+		if candidates[i].localName != "" {
+			candidates[i].Name = candidates[i].localName
+		}
+	}
+	return candidates
+}
+
+func geocodeWithOpenWeatherMap(ctx context.Context, query, apiKey string, limit int, lang string) ([]GeoLocation, error) {
+	directURL := fmt.Sprintf("%s/geo/1.0/direct?q=%s&limit=%d&appid=%s", openWeatherMapBaseURL, url.QueryEscape(query), limit, apiKey)
+
+	var results []owmGeocodingResult
+	if err := fetchGeocodingData(ctx, directURL, &results); err != nil {
+		return nil, err
+	}
+
+	return convertOWMGeocodingResults(results, lang), nil
+}
+
+// convertOWMGeocodingResults normalizes OpenWeatherMap results, keeping their local name in lang;
+// the geocoding API itself takes no language parameter
+func convertOWMGeocodingResults(results []owmGeocodingResult, lang string) []GeoLocation {
+	candidates := make([]GeoLocation, 0, len(results))
+	for _, result := range results {
+		candidates = append(candidates, GeoLocation{
+			Name:      result.Name,
+			State:     result.State,
+			Country:   result.Country,
+			Lat:       result.Lat,
+			Lon:       result.Lon,
+			localName: result.LocalNames[lang],
+		})
+	}
+	return candidates
+}
+
+func geocodeWithOpenMeteo(ctx context.Context, name string, qualifiers []string, limit int, lang string) ([]GeoLocation, error) {
+	params := url.Values{}
+	params.Set("name", name)
+	params.Set("count", fmt.Sprintf("%d", limit))
+	params.Set("format", "json")
+	for _, qualifier := range qualifiers {
+		if len(qualifier) == 2 && !isUSStateCode(qualifier) {
+			params.Set("countryCode", strings.ToUpper(qualifier))
+		}
+	}
+
+	var response openMeteoGeocodingResponse
+	if err := fetchGeocodingData(ctx, openMeteoGeocodingBaseURL+"/v1/search?"+params.Encode(), &response); err != nil {
+		return nil, err
+	}
+
+	// Names come in one language per search, so a language other than the default English takes
+	// a second search whose results are matched up by GeoNames ID
+	localNames := make(map[int]string)
+	if lang != "" && lang != langEnglish {
+		params.Set("language", lang)
+		var localized openMeteoGeocodingResponse
+		if err := fetchGeocodingData(ctx, openMeteoGeocodingBaseURL+"/v1/search?"+params.Encode(), &localized); err != nil {
+			return nil, err
+		}
+		for _, result := range localized.Results {
+			localNames[result.ID] = result.Name
+		}
+	}
+
+	candidates := make([]GeoLocation, 0, len(response.Results))
+	for _, result := range response.Results {
+		candidates = append(candidates, GeoLocation{
+			Name:        result.Name,
+			State:       result.Admin1,
+			Country:     result.CountryCode,
+			CountryName: result.Country,
+			Lat:         result.Latitude,
+			Lon:         result.Longitude,
+			Timezone:    result.Timezone,
+			Population:  result.Population,
+			localName:   localNames[result.ID],
+		})
+	}
+	return candidates, nil
+}
+
+// fetchGeocodingData performs a GET against a geocoding API and decodes the JSON response into target
+func fetchGeocodingData(ctx context.Context, url string, target any) error {
+	statusCode, body, err := getAPIResponse(ctx, url, "geocoding")
+	if err != nil {
+		return err
+	}
+
+	if statusCode != http.StatusOK {
+		return fmt.Errorf("geocoding API error (status %d): %s", statusCode, string(body))
+	}
+
+	if err := json.Unmarshal(body, target); err != nil {
+		return fmt.Errorf("failed to parse geocoding response: %v", err)
+	}
+	return nil
+}
+
+func FormatGeocodingAsMarkdown(candidates []GeoLocation, query string, reverse bool, lang string) string {
+	var builder strings.Builder
+	l := newLocalizer(lang)
+
+	if reverse {
+		builder.WriteString(fmt.Sprintf("# %s: %s\n\n", l.T("Reverse Geocoding"), query))
+	} else {
+		builder.WriteString(fmt.Sprintf("# %s: %s\n\n", l.T("Geocoding Results"), query))
+	}
+
+	if len(candidates) == 0 {
+		builder.WriteString(l.T("No matching locations found.") + "\n")
+		return builder.String()
+	}
+
+	if len(candidates) > 1 {
+		builder.WriteString(l.T("Found %d matching locations (best match first):", len(candidates)) + "\n\n")
+	}
+
+	for i, candidate := range candidates {
+		builder.WriteString(fmt.Sprintf("%d. **%s** (%.4f, %.4f)", i+1, candidate.DisplayName(), candidate.Lat, candidate.Lon))
+		if candidate.Timezone != "" {
+			builder.WriteString(", " + l.T("timezone %s", candidate.Timezone))
+		}
+		if candidate.Population > 0 {
+			builder.WriteString(", " + l.T("population %d", candidate.Population))
+		}
+		builder.WriteString("\n")
+	}
+
+	if !reverse && len(candidates) > 1 {
+		builder.WriteString("\n*" + l.T("Pass the coordinates of the intended place as `location` to the weather tools to avoid ambiguity.") + "*\n")
+	}
+
+	return builder.String()
+}
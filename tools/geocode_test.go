@@ -0,0 +1,343 @@
+package tools
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+)
+
+// newOpenWeatherMapTestServer serves recorded OpenWeatherMap and Open-Meteo geocoding responses
+// and points both base URLs at it
+func newOpenWeatherMapTestServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	routes := map[string]string{
+		"/geo/1.0/direct":                  "owm_geocoding_direct_response.json",
+		"/geo/1.0/reverse":                 "owm_geocoding_reverse_response.json",
+		"/v1/search":                       "openmeteo_geocoding_response.json",
+		"/data/2.5/weather":                "valencia_weather_response.json",
+		"/data/2.5/forecast":               "valencia_forecast_response.json",
+		"/data/2.5/air_pollution":          "valencia_air_pollution_response.json",
+		"/data/2.5/air_pollution/forecast": "valencia_air_pollution_forecast_response.json",
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fixture, ok := routes[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(loadFixture(t, fixture))
+	}))
+
+	originalOWMURL := openWeatherMapBaseURL
+	originalOpenMeteoURL := openMeteoGeocodingBaseURL
+	openWeatherMapBaseURL = server.URL
+	openMeteoGeocodingBaseURL = server.URL
+	t.Cleanup(func() {
+		openWeatherMapBaseURL = originalOWMURL
+		openMeteoGeocodingBaseURL = originalOpenMeteoURL
+		server.Close()
+	})
+
+	return server
+}
+
+func TestGeocodeTool_OpenWeatherMap(t *testing.T) {
+	os.Setenv("OPENWEATHER_API_KEY", "test_api_key")
+	defer os.Unsetenv("OPENWEATHER_API_KEY")
+	newOpenWeatherMapTestServer(t)
+
+	geocodeTool := NewGeocodeTool()
+	content, isError := callToolText(t, geocodeTool.Handler, map[string]any{
+		"query": "Springfield",
+	})
+	if isError {
+		t.Fatalf("Expected success, got error: %s", content)
+	}
+
+	expected := []string{
+		"# Geocoding Results: Springfield",
+		"Found 5 matching locations (best match first):",
+		"1. **Springfield, Illinois, US** (39.7990, -89.6440)",
+		"2. **Springfield, Missouri, US** (37.2082, -93.2923)",
+		"Pass the coordinates of the intended place",
+	}
+	for _, want := range expected {
+		if !strings.Contains(content, want) {
+			t.Errorf("Expected to find '%s' in geocoding output:\n%s", want, content)
+		}
+	}
+}
+
+func TestGeocodeTool_OpenMeteoWithoutAPIKey(t *testing.T) {
+	os.Unsetenv("OPENWEATHER_API_KEY")
+	newOpenWeatherMapTestServer(t)
+
+	geocodeTool := NewGeocodeTool()
+	content, isError := callToolText(t, geocodeTool.Handler, map[string]any{
+		"query": "Valencia",
+	})
+	if isError {
+		t.Fatalf("Expected success, got error: %s", content)
+	}
+
+	expected := []string{
+		"1. **València, Valencia, ES** (39.4697, -0.3774), timezone Europe/Madrid, population 814208",
+		"2. **Valencia, Carabobo, VE**",
+		"3. **Valencia, California, US**",
+	}
+	for _, want := range expected {
+		if !strings.Contains(content, want) {
+			t.Errorf("Expected to find '%s' in geocoding output:\n%s", want, content)
+		}
+	}
+}
+
+func TestGeocodeTool_Reverse(t *testing.T) {
+	os.Setenv("OPENWEATHER_API_KEY", "test_api_key")
+	defer os.Unsetenv("OPENWEATHER_API_KEY")
+	newOpenWeatherMapTestServer(t)
+
+	geocodeTool := NewGeocodeTool()
+	content, isError := callToolText(t, geocodeTool.Handler, map[string]any{
+		"query": "39.4697,-0.3763",
+	})
+	if isError {
+		t.Fatalf("Expected success, got error: %s", content)
+	}
+
+	if !strings.Contains(content, "# Reverse Geocoding: 39.4697,-0.3763") {
+		t.Errorf("Missing reverse geocoding header:\n%s", content)
+	}
+	if !strings.Contains(content, "1. **Valencia, Valencian Community, ES** (39.4697, -0.3763)") {
+		t.Errorf("Missing reverse geocoding result:\n%s", content)
+	}
+}
+
+func TestGeocodeTool_Language(t *testing.T) {
+	os.Setenv("OPENWEATHER_API_KEY", "test_api_key")
+	defer os.Unsetenv("OPENWEATHER_API_KEY")
+	newOpenWeatherMapTestServer(t)
+
+	// OpenWeatherMap names places from its local names
+	geocodeTool := NewGeocodeTool()
+	content, isError := callToolText(t, geocodeTool.Handler, map[string]any{
+		"query": "39.4697,-0.3763",
+		"lang":  "fr",
+	})
+	if isError {
+		t.Fatalf("Expected success, got error: %s", content)
+	}
+	for _, want := range []string{"# Géocodage inverse: 39.4697,-0.3763", "1. **Valence, Valencian Community, ES**"} {
+		if !strings.Contains(content, want) {
+			t.Errorf("Expected to find '%s' in geocoding output:\n%s", want, content)
+		}
+	}
+
+	// Open-Meteo is asked for names in the language
+	os.Unsetenv("OPENWEATHER_API_KEY")
+	var language string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		language = r.URL.Query().Get("language")
+		w.Write(loadFixture(t, "openmeteo_geocoding_response.json"))
+	}))
+	defer server.Close()
+	openMeteoGeocodingBaseURL = server.URL
+
+	content, isError = callToolText(t, geocodeTool.Handler, map[string]any{
+		"query": "Valencia",
+		"lang":  "de",
+	})
+	if isError {
+		t.Fatalf("Expected success, got error: %s", content)
+	}
+	if language != "de" {
+		t.Errorf("Expected language=de to be sent to Open-Meteo, got %q", language)
+	}
+	if !strings.Contains(content, "Zeitzone Europe/Madrid, 814208 Einwohner") {
+		t.Errorf("Expected localized candidate details:\n%s", content)
+	}
+}
+
+func TestGeocodeLocation_RanksOnProviderNames(t *testing.T) {
+	os.Setenv("OPENWEATHER_API_KEY", "test_api_key")
+	defer os.Unsetenv("OPENWEATHER_API_KEY")
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[
+			{"name": "Munich", "local_names": {"de": "München"}, "lat": 48.1371, "lon": 11.5754, "country": "DE", "state": "Bavaria"},
+			{"name": "Munich", "lat": 48.6692, "lon": -98.8332, "country": "US", "state": "North Dakota"}
+		]`))
+	}))
+	defer server.Close()
+	originalURL := openWeatherMapBaseURL
+	openWeatherMapBaseURL = server.URL
+	defer func() { openWeatherMapBaseURL = originalURL }()
+
+	// The local name is only shown; both candidates match "Munich" equally and keep their order
+	candidates, err := GeocodeLocation(context.Background(), "Munich", 5, "de")
+	if err != nil {
+		t.Fatalf("Expected candidates, got %v", err)
+	}
+	if len(candidates) != 2 || candidates[0].DisplayName() != "München, Bavaria, DE" || candidates[1].DisplayName() != "Munich, North Dakota, US" {
+		t.Errorf("Expected München first and named in German, got %+v", candidates)
+	}
+}
+
+func TestGeocodeTool_ReverseRequiresAPIKey(t *testing.T) {
+	os.Unsetenv("OPENWEATHER_API_KEY")
+
+	geocodeTool := NewGeocodeTool()
+	content, isError := callToolText(t, geocodeTool.Handler, map[string]any{
+		"query": "39.4697,-0.3763",
+	})
+	if !isError {
+		t.Fatalf("Expected error without API key, got: %s", content)
+	}
+	if !strings.Contains(content, "OpenWeatherMap API key not configured") {
+		t.Errorf("Unexpected error message: %s", content)
+	}
+}
+
+func TestRankCandidates(t *testing.T) {
+	candidates := []GeoLocation{
+		{Name: "Springfield", State: "Illinois", Country: "US"},
+		{Name: "Springfield", State: "Missouri", Country: "US"},
+		{Name: "Springfield", State: "Illinois", Country: "US"},
+		{Name: "West Springfield", State: "Massachusetts", Country: "US"},
+		{Name: "Springfield", State: "Tasmania", Country: "AU"},
+	}
+
+	testCases := []struct {
+		query    string
+		expected []string
+	}{
+		{"Springfield", []string{"Springfield, Illinois, US", "Springfield, Missouri, US", "Springfield, Tasmania, AU", "West Springfield, Massachusetts, US"}},
+		{"Springfield,MO,US", []string{"Springfield, Missouri, US", "Springfield, Illinois, US", "West Springfield, Massachusetts, US", "Springfield, Tasmania, AU"}},
+		{"Springfield, AU", []string{"Springfield, Tasmania, AU", "Springfield, Illinois, US", "Springfield, Missouri, US", "West Springfield, Massachusetts, US"}},
+	}
+
+	for _, tc := range testCases {
+		name, qualifiers := splitLocationQuery(tc.query)
+		ranked := rankCandidates(name, qualifiers, candidates)
+
+		if len(ranked) != len(tc.expected) {
+			t.Fatalf("rankCandidates(%q) returned %d candidates, expected %d", tc.query, len(ranked), len(tc.expected))
+		}
+		for i, want := range tc.expected {
+			if got := ranked[i].DisplayName(); got != want {
+				t.Errorf("rankCandidates(%q)[%d] = %s, expected %s", tc.query, i, got, want)
+			}
+		}
+	}
+}
+
+func TestResolveLocation(t *testing.T) {
+	os.Setenv("OPENWEATHER_API_KEY", "test_api_key")
+	defer os.Unsetenv("OPENWEATHER_API_KEY")
+	newOpenWeatherMapTestServer(t)
+
+	ctx := context.Background()
+
+	t.Run("Coordinates", func(t *testing.T) {
+		resolved, err := ResolveLocation(ctx, "40.7128,-74.0060")
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if resolved.Source != locationSourceCoordinates || resolved.Lat != 40.7128 || resolved.Lon != -74.0060 {
+			t.Errorf("Unexpected resolution: %+v", resolved)
+		}
+	})
+
+	t.Run("CoordinatesOutOfRange", func(t *testing.T) {
+		if _, err := ResolveLocation(ctx, "140.0,-74.0"); err == nil {
+			t.Error("Expected error for latitude out of range")
+		}
+	})
+
+	t.Run("AmbiguousName", func(t *testing.T) {
+		resolved, err := ResolveLocation(ctx, "Springfield")
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if resolved.Name != "Springfield, Illinois, US" || resolved.Country != "US" {
+			t.Errorf("Unexpected best match: %+v", resolved)
+		}
+		if len(resolved.Alternatives) != 4 {
+			t.Errorf("Expected 4 alternatives, got %d", len(resolved.Alternatives))
+		}
+
+		note := formatAlternativesNote(resolved)
+		if !strings.Contains(note, "\"Springfield\" matches several places; showing Springfield, Illinois, US") {
+			t.Errorf("Unexpected alternatives note:\n%s", note)
+		}
+		if !strings.Contains(note, "- Springfield, Missouri, US (37.2082,-93.2923)") {
+			t.Errorf("Missing alternative in note:\n%s", note)
+		}
+	})
+
+	t.Run("QualifiedName", func(t *testing.T) {
+		resolved, err := ResolveLocation(ctx, "Springfield,MO,US")
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if resolved.State != "Missouri" {
+			t.Errorf("Expected Missouri, got %+v", resolved)
+		}
+		if len(resolved.Alternatives) != 0 {
+			t.Errorf("Expected no alternatives for a qualified name, got %d", len(resolved.Alternatives))
+		}
+	})
+
+	t.Run("NoClientIP", func(t *testing.T) {
+		_, err := ResolveLocation(ctx, "")
+		if err == nil || !strings.Contains(err.Error(), "could not determine client IP address") {
+			t.Errorf("Expected client IP error, got %v", err)
+		}
+	})
+}
+
+func TestWeatherTool_GeocodedLocation(t *testing.T) {
+	os.Setenv("OPENWEATHER_API_KEY", "test_api_key")
+	defer os.Unsetenv("OPENWEATHER_API_KEY")
+
+	var weatherQuery string
+	server := newOpenWeatherMapTestServer(t)
+	server.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/geo/1.0/direct":
+			w.Write(loadFixture(t, "owm_geocoding_direct_response.json"))
+		case "/data/2.5/weather":
+			weatherQuery = r.URL.RawQuery
+			w.Write(loadFixture(t, "valencia_weather_response.json"))
+		default:
+			http.NotFound(w, r)
+		}
+	})
+
+	weatherTool := NewWeatherTool()
+	content, isError := callToolText(t, weatherTool.Handler, map[string]any{
+		"location": "Springfield",
+	})
+	if isError {
+		t.Fatalf("Expected success, got error: %s", content)
+	}
+
+	if !strings.Contains(weatherQuery, "lat=39.7990&lon=-89.6440") || !strings.Contains(weatherQuery, "units=metric") {
+		t.Errorf("Expected weather to be fetched in metric for geocoded coordinates, got query %s", weatherQuery)
+	}
+	if !strings.Contains(content, "°F") {
+		t.Errorf("Expected a US location to be displayed in imperial units:\n%s", content)
+	}
+	if !strings.Contains(content, "*Requested location: Springfield*") {
+		t.Errorf("Missing requested location:\n%s", content)
+	}
+	if !strings.Contains(content, "matches several places") {
+		t.Errorf("Missing disambiguation note:\n%s", content)
+	}
+}
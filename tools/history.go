@@ -0,0 +1,417 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// openMeteoArchiveBaseURL serves Open-Meteo's historical weather (ERA5 reanalysis), no API key required
+var openMeteoArchiveBaseURL = "https://archive-api.open-meteo.com"
+
+// historyNow is the clock relative dates such as 'yesterday' are resolved against
+var historyNow = time.Now
+
+const (
+	maxHistoryDays       = 31
+	maxHourlyHistoryDays = 3
+	earliestHistoryDate  = "1940-01-01"
+)
+
+type WeatherHistoryTool struct {
+	Tool    mcp.Tool
+	Handler func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error)
+}
+
+// OpenMeteoArchiveData is the response of the archive API. Values are null for days the
+// reanalysis hasn't reached yet.
+type OpenMeteoArchiveData struct {
+	Latitude         float64 `json:"latitude"`
+	Longitude        float64 `json:"longitude"`
+	UTCOffsetSeconds int     `json:"utc_offset_seconds"`
+	Timezone         string  `json:"timezone"`
+	Elevation        float64 `json:"elevation"`
+	Daily            struct {
+		Time          []string   `json:"time"`
+		WeatherCode   []*int     `json:"weather_code"`
+		TempMax       []*float64 `json:"temperature_2m_max"`
+		TempMin       []*float64 `json:"temperature_2m_min"`
+		TempMean      []*float64 `json:"temperature_2m_mean"`
+		Precipitation []*float64 `json:"precipitation_sum"`
+		WindSpeedMax  []*float64 `json:"wind_speed_10m_max"`
+		WindGustMax   []*float64 `json:"wind_gusts_10m_max"`
+	} `json:"daily"`
+	Hourly struct {
+		Time          []string   `json:"time"`
+		Temperature   []*float64 `json:"temperature_2m"`
+		Humidity      []*float64 `json:"relative_humidity_2m"`
+		Precipitation []*float64 `json:"precipitation"`
+		WindSpeed     []*float64 `json:"wind_speed_10m"`
+		WeatherCode   []*int     `json:"weather_code"`
+	} `json:"hourly"`
+}
+
+// Location returns the zone the archive reported its local times in
+func (d OpenMeteoArchiveData) Location() *time.Location {
+	if loc, err := time.LoadLocation(d.Timezone); err == nil && d.Timezone != "" {
+		return loc
+	}
+	return offsetLocation(d.UTCOffsetSeconds)
+}
+
+// wmoCondition maps a WMO weather code to the condition groups OpenWeatherMap uses, so history
+// reads like the forecast and shares its translations
+func wmoCondition(code int) string {
+	switch {
+	case code <= 1:
+		return "Clear"
+	case code <= 3:
+		return "Clouds"
+	case code == 45 || code == 48:
+		return "Fog"
+	case code >= 51 && code <= 57:
+		return "Drizzle"
+	case code >= 61 && code <= 67, code >= 80 && code <= 82:
+		return "Rain"
+	case code >= 71 && code <= 77, code == 85 || code == 86:
+		return "Snow"
+	case code >= 95:
+		return "Thunderstorm"
+	default:
+		return ""
+	}
+}
+
+func NewWeatherHistoryTool() *WeatherHistoryTool {
+	return &WeatherHistoryTool{
+		Tool:    weatherHistoryTool(),
+		Handler: weatherHistoryToolHandler,
+	}
+}
+
+func weatherHistoryTool() mcp.Tool {
+	return mcp.NewTool("get_weather_history",
+		mcp.WithDescription("Get past weather for a location: daily minimum, maximum and mean temperature, precipitation totals and maximum wind, with optional hourly detail. Uses client's IP location by default, or accepts a custom location parameter (city name or 'lat,lon' coordinates). Data comes from the Open-Meteo archive, which lags a few days behind today."),
+		readOnlyTool("Weather History", true),
+		mcp.WithString("date",
+			mcp.Required(),
+			mcp.Description("Day to look up, or the first day of a range: a date (YYYY-MM-DD), 'yesterday', or a weekday name such as 'tuesday' or 'last tuesday' for the most recent one before today, in the location's local time"),
+		),
+		mcp.WithString("end_date",
+			mcp.Description("Last day of the range (optional, YYYY-MM-DD or the same forms as date). Ranges are limited to 31 days."),
+		),
+		mcp.WithString("location",
+			mcp.Description("Location to get history for (optional). Can be city name (e.g., 'London' or 'New York,US'), coordinates (e.g., '40.7128,-74.0060') or a saved location (e.g., '@home'). Uses client IP location if not provided."),
+		),
+		mcp.WithBoolean("hourly",
+			mcp.Description("Include hour-by-hour detail (optional, default false, ranges of up to 3 days)"),
+		),
+		withUnits("Defaults to the customary units of the location's country."),
+		withLanguage(),
+		withFormat(),
+		mcp.WithOutputSchema[HistoryReport](),
+	)
+}
+
+func weatherHistoryToolHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	format, err := parseFormat(request)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	date, err := request.RequireString("date")
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	resolved, err := resolveLocationInteractively(ctx, request.GetString("location", ""))
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	locationName := resolved.Query
+	if locationName == "" {
+		locationName = resolved.Name
+	}
+
+	units, err := resolveUnits(request, resolved)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	lang, err := resolveLanguage(ctx, request, resolved)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	// Relative dates count back from today at the location, when its timezone is known
+	loc := time.UTC
+	if resolved.Timezone != "" {
+		if tz, err := time.LoadLocation(resolved.Timezone); err == nil {
+			loc = tz
+		}
+	}
+
+	hourly := request.GetBool("hourly", false)
+	start, end, err := parseHistoryRange(date, request.GetString("end_date", ""), hourly, historyNow().In(loc))
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	body, err := fetchWeatherData(ctx, buildHistoryURL(resolved.Lat, resolved.Lon, start, end, hourly))
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	var data OpenMeteoArchiveData
+	if err := json.Unmarshal(body, &data); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to parse weather history response: %v", err)), nil
+	}
+
+	result := FormatHistoryAsMarkdown(data, locationName, units, lang) + formatAlternativesNote(resolved)
+	return renderResult(format, result, NewHistoryReport(data, locationName, units)), nil
+}
+
+// parseHistoryRange resolves the date and end_date arguments against today and checks the range
+// is in the past, within the archive and short enough
+func parseHistoryRange(date, endDate string, hourly bool, today time.Time) (time.Time, time.Time, error) {
+	start, err := parseHistoryDate(date, today)
+	if err != nil {
+		return start, start, err
+	}
+
+	end := start
+	if endDate != "" {
+		if end, err = parseHistoryDate(endDate, today); err != nil {
+			return start, end, err
+		}
+	}
+
+	earliest, _ := time.ParseInLocation(time.DateOnly, earliestHistoryDate, today.Location())
+	midnight := time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, today.Location())
+	days := int(math.Round(end.Sub(start).Hours()/24)) + 1
+
+	switch {
+	case end.Before(start):
+		return start, end, fmt.Errorf("end_date %s is before date %s", end.Format(time.DateOnly), start.Format(time.DateOnly))
+	case start.Before(earliest):
+		return start, end, fmt.Errorf("weather history starts on %s", earliestHistoryDate)
+	case !end.Before(midnight):
+		return start, end, fmt.Errorf("weather history only covers days before today (%s); use get_weather or get_weather_forecast for today and later", midnight.Format(time.DateOnly))
+	case days > maxHistoryDays:
+		return start, end, fmt.Errorf("date range of %d days is too long: the limit is %d days", days, maxHistoryDays)
+	case hourly && days > maxHourlyHistoryDays:
+		return start, end, fmt.Errorf("hourly detail is limited to %d days, the range has %d", maxHourlyHistoryDays, days)
+	}
+
+	return start, end, nil
+}
+
+// parseHistoryDate accepts YYYY-MM-DD, 'yesterday', or a weekday name (optionally prefixed with
+// 'last'), which means the most recent such day before today
+func parseHistoryDate(value string, today time.Time) (time.Time, error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	loc := today.Location()
+	midnight := time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, loc)
+
+	if value == "yesterday" {
+		return midnight.AddDate(0, 0, -1), nil
+	}
+
+	if weekday, ok := weekdays[strings.TrimPrefix(value, "last ")]; ok {
+		offset := (int(today.Weekday()) - int(weekday) + 7) % 7
+		if offset == 0 {
+			offset = 7
+		}
+		return midnight.AddDate(0, 0, -offset), nil
+	}
+
+	date, err := time.ParseInLocation(time.DateOnly, value, loc)
+	if err != nil {
+		return date, fmt.Errorf("invalid date %q: use a date (YYYY-MM-DD), 'yesterday' or a weekday name such as 'last tuesday'", value)
+	}
+	return date, nil
+}
+
+// buildHistoryURL requests daily aggregates, and hourly values if asked, in metric with wind in m/s
+// and times in the location's own timezone
+func buildHistoryURL(lat, lon float64, start, end time.Time, hourly bool) string {
+	params := url.Values{}
+	params.Set("latitude", fmt.Sprintf("%.4f", lat))
+	params.Set("longitude", fmt.Sprintf("%.4f", lon))
+	params.Set("start_date", start.Format(time.DateOnly))
+	params.Set("end_date", end.Format(time.DateOnly))
+	params.Set("daily", "weather_code,temperature_2m_max,temperature_2m_min,temperature_2m_mean,precipitation_sum,wind_speed_10m_max,wind_gusts_10m_max")
+	if hourly {
+		params.Set("hourly", "temperature_2m,relative_humidity_2m,precipitation,wind_speed_10m,weather_code")
+	}
+	params.Set("wind_speed_unit", "ms")
+	params.Set("timezone", "auto")
+
+	return openMeteoArchiveBaseURL + "/v1/archive?" + params.Encode()
+}
+
+// historyValue returns the i-th value of an archive series, or nil when it is missing or null
+func historyValue[T any](values []*T, i int) *T {
+	if i >= len(values) {
+		return nil
+	}
+	return values[i]
+}
+
+// historyStats summarizes a range of days, skipping days without data
+type historyStats struct {
+	Days          int
+	Warmest       string
+	MaxTemp       float64
+	Coldest       string
+	MinTemp       float64
+	Wettest       string
+	MaxPrecip     float64
+	TotalPrecip   float64
+	MeanTempTotal float64
+	MeanTempDays  int
+}
+
+func summarizeHistory(data OpenMeteoArchiveData) historyStats {
+	stats := historyStats{Days: len(data.Daily.Time)}
+	for i, date := range data.Daily.Time {
+		if tempMax := historyValue(data.Daily.TempMax, i); tempMax != nil && (stats.Warmest == "" || *tempMax > stats.MaxTemp) {
+			stats.Warmest, stats.MaxTemp = date, *tempMax
+		}
+		if tempMin := historyValue(data.Daily.TempMin, i); tempMin != nil && (stats.Coldest == "" || *tempMin < stats.MinTemp) {
+			stats.Coldest, stats.MinTemp = date, *tempMin
+		}
+		if precip := historyValue(data.Daily.Precipitation, i); precip != nil {
+			stats.TotalPrecip += *precip
+			if *precip > stats.MaxPrecip {
+				stats.Wettest, stats.MaxPrecip = date, *precip
+			}
+		}
+		if tempMean := historyValue(data.Daily.TempMean, i); tempMean != nil {
+			stats.MeanTempTotal += *tempMean
+			stats.MeanTempDays++
+		}
+	}
+	return stats
+}
+
+func FormatHistoryAsMarkdown(data OpenMeteoArchiveData, locationName string, units string, lang string) string {
+	var builder strings.Builder
+	l := newLocalizer(lang)
+	system := l.Units(units)
+	loc := data.Location()
+
+	builder.WriteString(fmt.Sprintf("# %s: %s\n\n", l.T("Weather History"), locationName))
+
+	builder.WriteString(fmt.Sprintf("## %s\n\n", l.T("Daily Summary")))
+	if len(data.Daily.Time) == 0 {
+		builder.WriteString(l.T("No data available yet for this day.") + "\n")
+	}
+	for i, date := range data.Daily.Time {
+		day, err := time.ParseInLocation(time.DateOnly, date, loc)
+		label := date
+		if err == nil {
+			label = l.Day(day)
+		}
+
+		tempMin, tempMax := historyValue(data.Daily.TempMin, i), historyValue(data.Daily.TempMax, i)
+		if tempMin == nil || tempMax == nil {
+			builder.WriteString(fmt.Sprintf("**%s**: %s\n", label, l.T("No data available yet for this day.")))
+			continue
+		}
+
+		parts := []string{fmt.Sprintf("%s - %s", system.FormatTemperature(*tempMin), system.FormatTemperature(*tempMax))}
+		if tempMean := historyValue(data.Daily.TempMean, i); tempMean != nil {
+			parts[0] += fmt.Sprintf(" (%s)", l.T("mean %s", system.FormatTemperature(*tempMean)))
+		}
+		if code := historyValue(data.Daily.WeatherCode, i); code != nil && wmoCondition(*code) != "" {
+			parts = append(parts, l.T(wmoCondition(*code)))
+		}
+		if precip := historyValue(data.Daily.Precipitation, i); precip != nil {
+			parts = append(parts, l.T("precipitation %s", system.FormatPrecipitation(*precip)))
+		}
+		if wind := historyValue(data.Daily.WindSpeedMax, i); wind != nil {
+			windStr := l.T("wind up to %s", system.FormatWindSpeed(*wind))
+			if gust := historyValue(data.Daily.WindGustMax, i); gust != nil {
+				windStr += fmt.Sprintf(" (%s)", l.T("gusts %s", system.FormatWindSpeed(*gust)))
+			}
+			parts = append(parts, windStr)
+		}
+
+		builder.WriteString(fmt.Sprintf("**%s**: %s\n", label, strings.Join(parts, ", ")))
+	}
+
+	// Range totals only add something when there is more than one day
+	if stats := summarizeHistory(data); stats.Days > 1 && stats.Warmest != "" {
+		dayLabel := func(date string) string {
+			if day, err := time.ParseInLocation(time.DateOnly, date, loc); err == nil {
+				return l.Day(day)
+			}
+			return date
+		}
+
+		builder.WriteString(fmt.Sprintf("\n## %s\n", l.T("Period Summary")))
+		builder.WriteString(fmt.Sprintf("- **%s:** %s (%s)\n", l.T("Warmest day"), dayLabel(stats.Warmest), system.FormatTemperature(stats.MaxTemp)))
+		builder.WriteString(fmt.Sprintf("- **%s:** %s (%s)\n", l.T("Coldest day"), dayLabel(stats.Coldest), system.FormatTemperature(stats.MinTemp)))
+		if stats.MeanTempDays > 0 {
+			builder.WriteString(fmt.Sprintf("- **%s:** %s\n", l.T("Mean temperature"), system.FormatTemperature(stats.MeanTempTotal/float64(stats.MeanTempDays))))
+		}
+		builder.WriteString(fmt.Sprintf("- **%s:** %s\n", l.T("Total precipitation"), system.FormatPrecipitation(stats.TotalPrecip)))
+		if stats.Wettest != "" {
+			builder.WriteString(fmt.Sprintf("- **%s:** %s (%s)\n", l.T("Wettest day"), dayLabel(stats.Wettest), system.FormatPrecipitation(stats.MaxPrecip)))
+		}
+	}
+
+	if len(data.Hourly.Time) > 0 {
+		writeHourlyHistory(&builder, data, loc, system, l)
+	}
+
+	builder.WriteString(fmt.Sprintf("\n## %s\n", l.T("Location")))
+	builder.WriteString(fmt.Sprintf("- **%s:** %.4f, %.4f\n", l.T("Coordinates"), data.Latitude, data.Longitude))
+	builder.WriteString(fmt.Sprintf("- **%s:** %.0f m\n", l.T("Elevation"), data.Elevation))
+	builder.WriteString(fmt.Sprintf("- **%s:** %s\n", l.T("Timezone"), l.T("%s (all times local)", loc.String())))
+	builder.WriteString(fmt.Sprintf("- **%s:** %s\n", l.T("Source"), l.T("Open-Meteo historical weather archive")))
+
+	return builder.String()
+}
+
+// writeHourlyHistory lists hourly values under one heading per day
+func writeHourlyHistory(builder *strings.Builder, data OpenMeteoArchiveData, loc *time.Location, system UnitSystem, l *Localizer) {
+	lastDay := ""
+	for i, value := range data.Hourly.Time {
+		timestamp, err := time.ParseInLocation("2006-01-02T15:04", value, loc)
+		if err != nil {
+			continue
+		}
+		if day := timestamp.Format(time.DateOnly); day != lastDay {
+			builder.WriteString(fmt.Sprintf("\n## %s\n\n", l.T("Hourly Detail: %s", l.Day(timestamp))))
+			lastDay = day
+		}
+
+		var parts []string
+		if temp := historyValue(data.Hourly.Temperature, i); temp != nil {
+			parts = append(parts, system.FormatTemperature(*temp))
+		}
+		if code := historyValue(data.Hourly.WeatherCode, i); code != nil && wmoCondition(*code) != "" {
+			parts = append(parts, l.T(wmoCondition(*code)))
+		}
+		if humidity := historyValue(data.Hourly.Humidity, i); humidity != nil {
+			parts = append(parts, l.T("humidity %.0f%%", *humidity))
+		}
+		if wind := historyValue(data.Hourly.WindSpeed, i); wind != nil {
+			parts = append(parts, l.T("wind %s", system.FormatWindSpeed(*wind)))
+		}
+		if precip := historyValue(data.Hourly.Precipitation, i); precip != nil && *precip > 0 {
+			parts = append(parts, l.T("precipitation %s", system.FormatPrecipitation(*precip)))
+		}
+
+		builder.WriteString(fmt.Sprintf("**%s**: %s\n", l.Clock(timestamp), strings.Join(parts, ", ")))
+	}
+}
@@ -0,0 +1,173 @@
+package tools
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newArchiveTestServer serves the Open-Meteo archive fixtures and records the last query
+func newArchiveTestServer(t *testing.T) *url.Values {
+	t.Helper()
+
+	var query url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/archive" {
+			http.NotFound(w, r)
+			return
+		}
+		query = r.URL.Query()
+
+		fixture := "valencia_history_response.json"
+		if query.Has("hourly") {
+			fixture = "valencia_history_hourly_response.json"
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(loadFixture(t, fixture))
+	}))
+
+	originalURL := openMeteoArchiveBaseURL
+	originalNow := historyNow
+	openMeteoArchiveBaseURL = server.URL
+	historyNow = func() time.Time { return time.Date(2025, 9, 3, 12, 0, 0, 0, time.UTC) } // a Wednesday
+	t.Cleanup(func() {
+		server.Close()
+		openMeteoArchiveBaseURL = originalURL
+		historyNow = originalNow
+	})
+
+	return &query
+}
+
+func TestParseHistoryRange(t *testing.T) {
+	today := time.Date(2025, 9, 3, 12, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		date     string
+		endDate  string
+		hourly   bool
+		start    string
+		end      string
+		errorMsg string
+	}{
+		{"2025-08-27", "2025-09-02", false, "2025-08-27", "2025-09-02", ""},
+		{"yesterday", "", false, "2025-09-02", "2025-09-02", ""},
+		{"Last Tuesday", "", false, "2025-09-02", "2025-09-02", ""},
+		{"wednesday", "", false, "2025-08-27", "2025-08-27", ""},
+		{"2025-09-03", "", false, "", "", "only covers days before today"},
+		{"2025-09-02", "2025-08-30", false, "", "", "is before date"},
+		{"2025-07-01", "2025-09-01", false, "", "", "too long"},
+		{"2025-08-28", "2025-09-01", true, "", "", "hourly detail is limited"},
+		{"1939-12-31", "", false, "", "", "starts on 1940-01-01"},
+		{"next week", "", false, "", "", "invalid date"},
+	}
+
+	for _, tc := range testCases {
+		start, end, err := parseHistoryRange(tc.date, tc.endDate, tc.hourly, today)
+		if tc.errorMsg != "" {
+			if err == nil || !strings.Contains(err.Error(), tc.errorMsg) {
+				t.Errorf("parseHistoryRange(%q, %q) error = %v, expected %q", tc.date, tc.endDate, err, tc.errorMsg)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("parseHistoryRange(%q, %q) unexpected error: %v", tc.date, tc.endDate, err)
+			continue
+		}
+		if got := start.Format(time.DateOnly) + ".." + end.Format(time.DateOnly); got != tc.start+".."+tc.end {
+			t.Errorf("parseHistoryRange(%q, %q) = %s, expected %s..%s", tc.date, tc.endDate, got, tc.start, tc.end)
+		}
+	}
+}
+
+func TestWeatherHistoryTool(t *testing.T) {
+	newOpenWeatherMapTestServer(t)
+	query := newArchiveTestServer(t)
+
+	historyTool := NewWeatherHistoryTool()
+
+	t.Run("SingleDay", func(t *testing.T) {
+		content, isError := callToolText(t, historyTool.Handler, map[string]any{"location": "Valencia,ES", "date": "last tuesday"})
+		if isError {
+			t.Fatalf("Expected success, got error: %s", content)
+		}
+		if got := query.Get("start_date") + ".." + query.Get("end_date"); got != "2025-09-02..2025-09-02" {
+			t.Errorf("Expected a request for 2025-09-02, got %s", got)
+		}
+
+		expected := []string{
+			"# Weather History: Valencia,ES",
+			"**Tue Sep 2**: 21.5°C - 29.6°C (mean 25.4°C), Clear, precipitation 0.0 mm, wind up to 5.6 m/s (gusts 10.2 m/s)",
+			"**Source:** Open-Meteo historical weather archive",
+		}
+		for _, text := range expected {
+			if !strings.Contains(content, text) {
+				t.Errorf("Expected %q in output:\n%s", text, content)
+			}
+		}
+	})
+
+	t.Run("Range", func(t *testing.T) {
+		content, isError := callToolText(t, historyTool.Handler, map[string]any{"location": "39.4697,-0.3763", "date": "2025-08-27", "end_date": "yesterday"})
+		if isError {
+			t.Fatalf("Expected success, got error: %s", content)
+		}
+		if query.Has("hourly") {
+			t.Errorf("Hourly data should only be requested when asked for")
+		}
+
+		expected := []string{
+			"**Sat Aug 30**: 20.4°C - 27.1°C (mean 23.6°C), Rain, precipitation 12.4 mm, wind up to 9.8 m/s (gusts 17.6 m/s)",
+			"**Warmest day:** Thu Aug 28 (31.2°C)",
+			"**Coldest day:** Sun Aug 31 (19.8°C)",
+			"**Mean temperature:** 25.0°C",
+			"**Total precipitation:** 15.7 mm",
+			"**Wettest day:** Sat Aug 30 (12.4 mm)",
+		}
+		for _, text := range expected {
+			if !strings.Contains(content, text) {
+				t.Errorf("Expected %q in output:\n%s", text, content)
+			}
+		}
+	})
+
+	t.Run("Hourly", func(t *testing.T) {
+		content, isError := callToolText(t, historyTool.Handler, map[string]any{"location": "39.4697,-0.3763", "date": "yesterday", "hourly": true, "units": "imperial"})
+		if isError {
+			t.Fatalf("Expected success, got error: %s", content)
+		}
+		if !strings.Contains(content, "## Hourly Detail: Tue Sep 2") || !strings.Contains(content, "**3:00 PM**: 85.3°F, Clear") {
+			t.Errorf("Expected hourly detail in output:\n%s", content)
+		}
+	})
+
+	t.Run("Language", func(t *testing.T) {
+		content, isError := callToolText(t, historyTool.Handler, map[string]any{"location": "39.4697,-0.3763", "date": "yesterday", "lang": "es"})
+		if isError {
+			t.Fatalf("Expected success, got error: %s", content)
+		}
+		if !strings.Contains(content, "# Historial meteorológico:") || !strings.Contains(content, "(media 25,4°C), Despejado") {
+			t.Errorf("Expected Spanish output:\n%s", content)
+		}
+	})
+
+	t.Run("FutureDate", func(t *testing.T) {
+		content, isError := callToolText(t, historyTool.Handler, map[string]any{"location": "39.4697,-0.3763", "date": "2025-09-05"})
+		if !isError || !strings.Contains(content, "only covers days before today") {
+			t.Errorf("Expected an error for a future date, got: %s", content)
+		}
+	})
+
+	t.Run("Structured", func(t *testing.T) {
+		structured := callToolStructured(t, historyTool.Tool, historyTool.Handler, map[string]any{"location": "39.4697,-0.3763", "date": "yesterday", "hourly": true})
+		if structured["provider"] != providerOpenMeteo || structured["startDate"] != "2025-09-02" {
+			t.Errorf("Unexpected report: %v", structured)
+		}
+		if hours, ok := structured["hours"].([]any); !ok || len(hours) != 24 {
+			t.Errorf("Expected 24 hours, got %v", structured["hours"])
+		}
+	})
+}
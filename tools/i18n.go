@@ -0,0 +1,890 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"golang.org/x/text/language"
+)
+
+const (
+	langEnglish = "en"
+	langSpanish = "es"
+	langGerman  = "de"
+	langFrench  = "fr"
+)
+
+// AcceptLanguageKey holds the client's Accept-Language header, used when no lang argument is given
+const AcceptLanguageKey contextKey = "accept-language"
+
+// supportedLanguages is in the order the Accept-Language matcher prefers them
+var supportedLanguages = []string{langEnglish, langSpanish, langGerman, langFrench}
+
+var languageMatcher = language.NewMatcher([]language.Tag{language.English, language.Spanish, language.German, language.French})
+
+// countryLanguages picks the output language for clients located by IP, keyed by ISO 3166 code.
+// Multilingual countries such as CH or BE are left out and get English.
+var countryLanguages = map[string]string{
+	"ES": langSpanish, "MX": langSpanish, "AR": langSpanish, "CO": langSpanish, "CL": langSpanish,
+	"PE": langSpanish, "VE": langSpanish, "EC": langSpanish, "GT": langSpanish, "CU": langSpanish,
+	"BO": langSpanish, "DO": langSpanish, "HN": langSpanish, "PY": langSpanish, "SV": langSpanish,
+	"NI": langSpanish, "CR": langSpanish, "PA": langSpanish, "UY": langSpanish,
+	"DE": langGerman, "AT": langGerman, "LI": langGerman,
+	"FR": langFrench, "MC": langFrench,
+}
+
+// locale holds the message catalog and date and number conventions of one language
+type locale struct {
+	Decimal     string
+	DayLayout   string
+	ClockLayout string
+	Weekdays    [7]string
+	Months      [12]string
+	Compass     *strings.Replacer
+	Messages    map[string]string
+}
+
+// locales are keyed by language. English messages are the catalog keys, so its catalog is empty.
+var locales = map[string]locale{
+	langEnglish: {
+		Decimal:     ".",
+		DayLayout:   "Mon Jan 2",
+		ClockLayout: "3:04 PM",
+		Weekdays:    [7]string{"Sun", "Mon", "Tue", "Wed", "Thu", "Fri", "Sat"},
+		Months:      [12]string{"Jan", "Feb", "Mar", "Apr", "May", "Jun", "Jul", "Aug", "Sep", "Oct", "Nov", "Dec"},
+		Compass:     strings.NewReplacer(),
+	},
+	langSpanish: {
+		Decimal:     ",",
+		DayLayout:   "Mon 2 Jan",
+		ClockLayout: "15:04",
+		Weekdays:    [7]string{"dom", "lun", "mar", "mié", "jue", "vie", "sáb"},
+		Months:      [12]string{"ene", "feb", "mar", "abr", "may", "jun", "jul", "ago", "sep", "oct", "nov", "dic"},
+		Compass:     strings.NewReplacer("W", "O"),
+		Messages: map[string]string{
+			"Weather Information":  "Información meteorológica",
+			"Weather Forecast":     "Pronóstico del tiempo",
+			"Requested location":   "Ubicación solicitada",
+			"Current Conditions":   "Condiciones actuales",
+			"Condition":            "Condición",
+			"Temperature":          "Temperatura",
+			"feels like %s":        "sensación térmica %s",
+			"Range":                "Rango",
+			"Humidity":             "Humedad",
+			"Pressure":             "Presión",
+			"Details":              "Detalles",
+			"Wind":                 "Viento",
+			"Visibility":           "Visibilidad",
+			"Cloudiness":           "Nubosidad",
+			"Rain (last hour)":     "Lluvia (última hora)",
+			"Snow (last hour)":     "Nieve (última hora)",
+			"Location":             "Ubicación",
+			"City":                 "Ciudad",
+			"Coordinates":          "Coordenadas",
+			"Sunrise":              "Amanecer",
+			"Sunset":               "Atardecer",
+			"Observed":             "Observado",
+			"Timezone":             "Zona horaria",
+			"Station":              "Estación",
+			"Forecast Office":      "Oficina de pronóstico",
+			"grid %d,%d":           "cuadrícula %d,%d",
+			"Source":               "Fuente",
+			"Air Quality":          "Calidad del aire",
+			"Current Air Quality":  "Calidad del aire actual",
+			"Air Quality Index":    "Índice de calidad del aire",
+			"Main Pollutant":       "Contaminante principal",
+			"Measured":             "Medido",
+			"Health Guidance":      "Recomendaciones de salud",
+			"Pollutants (µg/m³)":   "Contaminantes (µg/m³)",
+			"Air Quality Forecast": "Previsión de calidad del aire",
+			"%d of 5 (%s)":         "%d de 5 (%s)",
+			"%s up to %s µg/m³":    "%s hasta %s µg/m³",
+			"Good":                 "Buena",
+			"Fair":                 "Aceptable",
+			"Moderate":             "Moderada",
+			"Poor":                 "Mala",
+			"Very Poor":            "Muy mala",
+			"Air quality is good. Enjoy outdoor activities.":                                                                                     "La calidad del aire es buena. Disfruta de las actividades al aire libre.",
+			"Air quality is acceptable. Unusually sensitive people should consider limiting prolonged outdoor exertion.":                         "La calidad del aire es aceptable. Las personas especialmente sensibles deberían limitar el esfuerzo prolongado al aire libre.",
+			"Sensitive groups (children, older adults and people with heart or lung disease) should reduce prolonged or heavy outdoor exertion.": "Los grupos sensibles (niños, mayores y personas con enfermedades cardíacas o pulmonares) deberían reducir el esfuerzo prolongado o intenso al aire libre.",
+			"Everyone may begin to feel health effects: sensitive groups should avoid outdoor exertion and everyone else should limit it.":       "Todos pueden empezar a notar efectos en la salud: los grupos sensibles deberían evitar el esfuerzo al aire libre y el resto limitarlo.",
+			"Health alert: everyone should avoid outdoor exertion and sensitive groups should stay indoors.":                                     "Alerta sanitaria: todos deberían evitar el esfuerzo al aire libre y los grupos sensibles permanecer en interiores.",
+			"Weather History":                       "Historial meteorológico",
+			"Daily Summary":                         "Resumen diario",
+			"No data available yet for this day.":   "Aún no hay datos para este día.",
+			"mean %s":                               "media %s",
+			"precipitation %s":                      "precipitación %s",
+			"gusts %s":                              "rachas %s",
+			"Period Summary":                        "Resumen del periodo",
+			"Warmest day":                           "Día más cálido",
+			"Coldest day":                           "Día más frío",
+			"Mean temperature":                      "Temperatura media",
+			"Total precipitation":                   "Precipitación total",
+			"Wettest day":                           "Día más lluvioso",
+			"Hourly Detail: %s":                     "Detalle por horas: %s",
+			"Elevation":                             "Altitud",
+			"Open-Meteo historical weather archive": "Archivo meteorológico histórico de Open-Meteo",
+			"Comfort":                               "Confort",
+			"Dew Point":                             "Punto de rocío",
+			"Heat Index":                            "Índice de calor",
+			"Wind Chill":                            "Sensación por viento",
+			"Apparent Temperature":                  "Temperatura aparente",
+			"Humidity Comfort":                      "Confort de humedad",
+			"Beaufort Scale":                        "Escala de Beaufort",
+			"Force %d (%s)":                         "Fuerza %d (%s)",
+			"up to Force %d (%s)":                   "hasta fuerza %d (%s)",
+			"dew point %s (%s)":                     "punto de rocío %s (%s)",
+			"dew point up to %s (%s)":               "punto de rocío hasta %s (%s)",
+			"apparent %s (%s)":                      "aparente %s (%s)",
+			"%s at sea level, %s at ground level":   "%s al nivel del mar, %s en superficie",
+			"ground %s":                             "en superficie %s",
+			"rain %s":                               "lluvia %s",
+			"snow %s":                               "nieve %s",
+			"visibility %s":                         "visibilidad %s",
+			"visibility down to %s":                 "visibilidad mínima %s",
+			"Weather Comparison":                    "Comparación del tiempo",
+			"When":                                  "Cuándo",
+			"Feels Like":                            "Sensación térmica",
+			"Precipitation":                         "Precipitación",
+			"Precipitation Chance":                  "Probabilidad de precipitación",
+			"High":                                  "Máxima",
+			"Low":                                   "Mínima",
+			"Best and Worst":                        "Mejor y peor",
+			"best %s (%s), worst %s (%s)":           "mejor %s (%s), peor %s (%s)",
+			"Unavailable":                           "No disponible",
+			"Astronomy":                             "Astronomía",
+			"Date":                                  "Fecha",
+			"Sun":                                   "Sol",
+			"Solar Noon":                            "Mediodía solar",
+			"Day Length":                            "Duración del día",
+			"Civil Twilight":                        "Crepúsculo civil",
+			"Nautical Twilight":                     "Crepúsculo náutico",
+			"Astronomical Twilight":                 "Crepúsculo astronómico",
+			"dawn %s, dusk %s":                      "alba %s, ocaso %s",
+			"all night":                             "toda la noche",
+			"none":                                  "ninguno",
+			"up all day":                            "visible todo el día",
+			"down all day":                          "oculto todo el día",
+			"Moon":                                  "Luna",
+			"Phase":                                 "Fase",
+			"%s, %s days old":                       "%s, %s días",
+			"Illumination":                          "Iluminación",
+			"Moonrise":                              "Salida de la luna",
+			"Moonset":                               "Puesta de la luna",
+			"New Moon":                              "Luna nueva",
+			"Waxing Crescent":                       "Luna creciente",
+			"First Quarter":                         "Cuarto creciente",
+			"Waxing Gibbous":                        "Gibosa creciente",
+			"Full Moon":                             "Luna llena",
+			"Waning Gibbous":                        "Gibosa menguante",
+			"Last Quarter":                          "Cuarto menguante",
+			"Waning Crescent":                       "Luna menguante",
+			"estimated from longitude":              "estimada a partir de la longitud",
+			"Computed locally from astronomical formulas": "Calculado localmente con fórmulas astronómicas",
+			"Activity Suitability: %s in %s":              "Idoneidad de actividad: %s en %s",
+			"Profile":                                     "Perfil",
+			"Best Windows":                                "Mejores franjas",
+			"No time in the forecast meets the profile.":  "Ningún momento del pronóstico cumple el perfil.",
+			"The closest is %s (score %d): %s.":           "Lo más cercano es %s (puntuación %d): %s.",
+			"score %d":                                    "puntuación %d",
+			"Daily Outlook":                               "Resumen diario",
+			"%d of %d slots suitable":                     "%d de %d franjas adecuadas",
+			"best score %d":                               "mejor puntuación %d",
+			"mostly %s":                                   "sobre todo %s",
+			"Scores run from 0 to 100; windows only include slots within every limit.": "Las puntuaciones van de 0 a 100; las franjas solo incluyen periodos dentro de todos los límites.",
+			"precipitation chance up to %.0f%%":                                        "probabilidad de precipitación hasta %.0f%%",
+			"daylight only":                                                            "solo con luz de día",
+			"at least %s":                                                              "al menos %s",
+			"too cold":                                                                 "demasiado frío",
+			"too hot":                                                                  "demasiado calor",
+			"too windy":                                                                "demasiado viento",
+			"likely precipitation":                                                     "precipitación probable",
+			"dark":                                                                     "de noche",
+			"Cycling":                                                                  "Ciclismo",
+			"Running":                                                                  "Correr",
+			"Hiking":                                                                   "Senderismo",
+			"Picnic":                                                                   "Pícnic",
+			"Outdoor Event":                                                            "Evento al aire libre",
+			"Beach":                                                                    "Playa",
+			"Custom":                                                                   "Personalizado",
+			"steady":                                                                   "estable",
+			"rising":                                                                   "subiendo",
+			"rising quickly":                                                           "subiendo rápido",
+			"falling":                                                                  "bajando",
+			"falling quickly":                                                          "bajando rápido",
+			"Dangerously Cold":                                                         "Frío peligroso",
+			"Very Cold":                                                                "Muy frío",
+			"Cold":                                                                     "Frío",
+			"Cool":                                                                     "Fresco",
+			"Mild":                                                                     "Templado",
+			"Warm":                                                                     "Cálido",
+			"Hot":                                                                      "Caluroso",
+			"Very Hot":                                                                 "Muy caluroso",
+			"Dangerously Hot":                                                          "Calor peligroso",
+			"Dry":                                                                      "Seco",
+			"Comfortable":                                                              "Confortable",
+			"Slightly Humid":                                                           "Algo húmedo",
+			"Humid":                                                                    "Húmedo",
+			"Muggy":                                                                    "Bochornoso",
+			"Oppressive":                                                               "Agobiante",
+			"Calm":                                                                     "Calma",
+			"Light Air":                                                                "Ventolina",
+			"Light Breeze":                                                             "Flojito",
+			"Gentle Breeze":                                                            "Flojo",
+			"Moderate Breeze":                                                          "Bonancible",
+			"Fresh Breeze":                                                             "Fresquito",
+			"Strong Breeze":                                                            "Fresco",
+			"Near Gale":                                                                "Frescachón",
+			"Gale":                                                                     "Temporal",
+			"Strong Gale":                                                              "Temporal fuerte",
+			"Storm":                                                                    "Temporal duro",
+			"Violent Storm":                                                            "Temporal muy duro",
+			"Hurricane Force":                                                          "Temporal huracanado",
+			"%s (all times local)":                                                     "%s (todas las horas son locales)",
+			"Next %d Hours":                                                            "Próximas %d horas",
+			"%d Hours from %s":                                                         "%d horas desde el %s",
+			"%d-Day Forecast":                                                          "Pronóstico de %d días",
+			"Forecast by Part of Day":                                                  "Pronóstico por franja del día",
+			"Forecast Periods":                                                         "Periodos del pronóstico",
+			"%.0f%% chance rain":                                                       "%.0f%% prob. de lluvia",
+			"%.0f%% chance precipitation":                                              "%.0f%% prob. de precipitación",
+			"wind %s":                                                                  "viento %s",
+			"wind up to %s":                                                            "viento hasta %s",
+			"humidity %.0f%%":                                                          "humedad %.0f%%",
+			"pressure %s":                                                              "presión %s",
+			"clouds %.0f%%":                                                            "nubes %.0f%%",
+			"Geocoding Results":                                                        "Resultados de geocodificación",
+			"Reverse Geocoding":                                                        "Geocodificación inversa",
+			"No matching locations found.":                                             "No se encontraron ubicaciones coincidentes.",
+			"Found %d matching locations (best match first):": "Se encontraron %d ubicaciones coincidentes (la más probable primero):",
+			"timezone %s":   "zona horaria %s",
+			"population %d": "población %d",
+			"Pass the coordinates of the intended place as `location` to the weather tools to avoid ambiguity.": "Pasa las coordenadas del lugar deseado como `location` a las herramientas meteorológicas para evitar ambigüedades.",
+			"No forecast data available for the requested days.":                                                "No hay datos de pronóstico para los días solicitados.",
+			"No forecast data available from %s; the forecast runs until %s.":                                   "No hay datos de pronóstico desde el %s; el pronóstico llega hasta el %s.",
+			"No forecast periods available for the requested range.":                                            "No hay periodos de pronóstico para el intervalo solicitado.",
+			"Night":        "Madrugada",
+			"Morning":      "Mañana",
+			"Afternoon":    "Tarde",
+			"Evening":      "Noche",
+			"Clear":        "Despejado",
+			"Clouds":       "Nubes",
+			"Rain":         "Lluvia",
+			"Drizzle":      "Llovizna",
+			"Thunderstorm": "Tormenta",
+			"Snow":         "Nieve",
+			"Mist":         "Neblina",
+			"Fog":          "Niebla",
+			"Haze":         "Calima",
+			"Smoke":        "Humo",
+			"Dust":         "Polvo",
+			"Sand":         "Arena",
+			"Ash":          "Ceniza",
+			"Squall":       "Turbonada",
+			"Tornado":      "Tornado",
+		},
+	},
+	langGerman: {
+		Decimal:     ",",
+		DayLayout:   "Mon, 2. Jan",
+		ClockLayout: "15:04",
+		Weekdays:    [7]string{"So", "Mo", "Di", "Mi", "Do", "Fr", "Sa"},
+		Months:      [12]string{"Jan", "Feb", "Mär", "Apr", "Mai", "Jun", "Jul", "Aug", "Sep", "Okt", "Nov", "Dez"},
+		Compass:     strings.NewReplacer("E", "O"),
+		Messages: map[string]string{
+			"Weather Information":  "Wetterinformationen",
+			"Weather Forecast":     "Wettervorhersage",
+			"Requested location":   "Angefragter Ort",
+			"Current Conditions":   "Aktuelle Bedingungen",
+			"Condition":            "Wetterlage",
+			"Temperature":          "Temperatur",
+			"feels like %s":        "gefühlt %s",
+			"Range":                "Spanne",
+			"Humidity":             "Luftfeuchtigkeit",
+			"Pressure":             "Luftdruck",
+			"Details":              "Details",
+			"Wind":                 "Wind",
+			"Visibility":           "Sichtweite",
+			"Cloudiness":           "Bewölkung",
+			"Rain (last hour)":     "Regen (letzte Stunde)",
+			"Snow (last hour)":     "Schnee (letzte Stunde)",
+			"Location":             "Ort",
+			"City":                 "Stadt",
+			"Coordinates":          "Koordinaten",
+			"Sunrise":              "Sonnenaufgang",
+			"Sunset":               "Sonnenuntergang",
+			"Observed":             "Beobachtet",
+			"Timezone":             "Zeitzone",
+			"Station":              "Station",
+			"Forecast Office":      "Vorhersagebüro",
+			"grid %d,%d":           "Raster %d,%d",
+			"Source":               "Quelle",
+			"Air Quality":          "Luftqualität",
+			"Current Air Quality":  "Aktuelle Luftqualität",
+			"Air Quality Index":    "Luftqualitätsindex",
+			"Main Pollutant":       "Hauptschadstoff",
+			"Measured":             "Gemessen",
+			"Health Guidance":      "Gesundheitshinweise",
+			"Pollutants (µg/m³)":   "Schadstoffe (µg/m³)",
+			"Air Quality Forecast": "Luftqualitätsvorhersage",
+			"%d of 5 (%s)":         "%d von 5 (%s)",
+			"%s up to %s µg/m³":    "%s bis %s µg/m³",
+			"Good":                 "Gut",
+			"Fair":                 "Befriedigend",
+			"Moderate":             "Mäßig",
+			"Poor":                 "Schlecht",
+			"Very Poor":            "Sehr schlecht",
+			"Air quality is good. Enjoy outdoor activities.":                                                                                     "Die Luftqualität ist gut. Genießen Sie Aktivitäten im Freien.",
+			"Air quality is acceptable. Unusually sensitive people should consider limiting prolonged outdoor exertion.":                         "Die Luftqualität ist akzeptabel. Besonders empfindliche Personen sollten längere Anstrengungen im Freien einschränken.",
+			"Sensitive groups (children, older adults and people with heart or lung disease) should reduce prolonged or heavy outdoor exertion.": "Empfindliche Gruppen (Kinder, ältere Menschen und Personen mit Herz- oder Lungenerkrankungen) sollten längere oder schwere Anstrengungen im Freien reduzieren.",
+			"Everyone may begin to feel health effects: sensitive groups should avoid outdoor exertion and everyone else should limit it.":       "Jeder kann gesundheitliche Auswirkungen spüren: Empfindliche Gruppen sollten Anstrengungen im Freien vermeiden, alle anderen sie einschränken.",
+			"Health alert: everyone should avoid outdoor exertion and sensitive groups should stay indoors.":                                     "Gesundheitswarnung: Alle sollten Anstrengungen im Freien vermeiden, empfindliche Gruppen sollten in Innenräumen bleiben.",
+			"Weather History":                       "Wetterverlauf",
+			"Daily Summary":                         "Tagesübersicht",
+			"No data available yet for this day.":   "Für diesen Tag liegen noch keine Daten vor.",
+			"mean %s":                               "Mittel %s",
+			"precipitation %s":                      "Niederschlag %s",
+			"gusts %s":                              "Böen %s",
+			"Period Summary":                        "Zeitraumübersicht",
+			"Warmest day":                           "Wärmster Tag",
+			"Coldest day":                           "Kältester Tag",
+			"Mean temperature":                      "Mitteltemperatur",
+			"Total precipitation":                   "Niederschlag gesamt",
+			"Wettest day":                           "Nassester Tag",
+			"Hourly Detail: %s":                     "Stündlich: %s",
+			"Elevation":                             "Höhe",
+			"Open-Meteo historical weather archive": "Historisches Wetterarchiv von Open-Meteo",
+			"Comfort":                               "Behaglichkeit",
+			"Dew Point":                             "Taupunkt",
+			"Heat Index":                            "Hitzeindex",
+			"Wind Chill":                            "Windchill",
+			"Apparent Temperature":                  "Gefühlte Temperatur",
+			"Humidity Comfort":                      "Feuchteempfinden",
+			"Beaufort Scale":                        "Beaufort-Skala",
+			"Force %d (%s)":                         "Stärke %d (%s)",
+			"up to Force %d (%s)":                   "bis Stärke %d (%s)",
+			"dew point %s (%s)":                     "Taupunkt %s (%s)",
+			"dew point up to %s (%s)":               "Taupunkt bis %s (%s)",
+			"apparent %s (%s)":                      "gefühlt %s (%s)",
+			"%s at sea level, %s at ground level":   "%s auf Meereshöhe, %s am Boden",
+			"ground %s":                             "am Boden %s",
+			"rain %s":                               "Regen %s",
+			"snow %s":                               "Schnee %s",
+			"visibility %s":                         "Sicht %s",
+			"visibility down to %s":                 "Sicht bis %s",
+			"Weather Comparison":                    "Wettervergleich",
+			"When":                                  "Wann",
+			"Feels Like":                            "Gefühlt",
+			"Precipitation":                         "Niederschlag",
+			"Precipitation Chance":                  "Niederschlagswahrscheinlichkeit",
+			"High":                                  "Höchstwert",
+			"Low":                                   "Tiefstwert",
+			"Best and Worst":                        "Am besten und am schlechtesten",
+			"best %s (%s), worst %s (%s)":           "am besten %s (%s), am schlechtesten %s (%s)",
+			"Unavailable":                           "Nicht verfügbar",
+			"Astronomy":                             "Astronomie",
+			"Date":                                  "Datum",
+			"Sun":                                   "Sonne",
+			"Solar Noon":                            "Sonnenhöchststand",
+			"Day Length":                            "Tageslänge",
+			"Civil Twilight":                        "Bürgerliche Dämmerung",
+			"Nautical Twilight":                     "Nautische Dämmerung",
+			"Astronomical Twilight":                 "Astronomische Dämmerung",
+			"dawn %s, dusk %s":                      "Beginn %s, Ende %s",
+			"all night":                             "die ganze Nacht",
+			"none":                                  "keine",
+			"up all day":                            "den ganzen Tag sichtbar",
+			"down all day":                          "den ganzen Tag unter dem Horizont",
+			"Moon":                                  "Mond",
+			"Phase":                                 "Phase",
+			"%s, %s days old":                       "%s, %s Tage alt",
+			"Illumination":                          "Beleuchtung",
+			"Moonrise":                              "Mondaufgang",
+			"Moonset":                               "Monduntergang",
+			"New Moon":                              "Neumond",
+			"Waxing Crescent":                       "Zunehmende Sichel",
+			"First Quarter":                         "Erstes Viertel",
+			"Waxing Gibbous":                        "Zunehmender Mond",
+			"Full Moon":                             "Vollmond",
+			"Waning Gibbous":                        "Abnehmender Mond",
+			"Last Quarter":                          "Letztes Viertel",
+			"Waning Crescent":                       "Abnehmende Sichel",
+			"estimated from longitude":              "aus dem Längengrad geschätzt",
+			"Computed locally from astronomical formulas": "Lokal mit astronomischen Formeln berechnet",
+			"Activity Suitability: %s in %s":              "Aktivitätseignung: %s in %s",
+			"Profile":                                     "Profil",
+			"Best Windows":                                "Beste Zeitfenster",
+			"No time in the forecast meets the profile.":  "Kein Zeitraum der Vorhersage erfüllt das Profil.",
+			"The closest is %s (score %d): %s.":           "Am nächsten kommt %s (Punktzahl %d): %s.",
+			"score %d":                                    "Punktzahl %d",
+			"Daily Outlook":                               "Tagesübersicht",
+			"%d of %d slots suitable":                     "%d von %d Zeitfenstern geeignet",
+			"best score %d":                               "beste Punktzahl %d",
+			"mostly %s":                                   "meist %s",
+			"Scores run from 0 to 100; windows only include slots within every limit.": "Punktzahlen reichen von 0 bis 100; Zeitfenster enthalten nur Abschnitte innerhalb aller Grenzen.",
+			"precipitation chance up to %.0f%%":                                        "Niederschlagswahrscheinlichkeit bis %.0f%%",
+			"daylight only":                                                            "nur bei Tageslicht",
+			"at least %s":                                                              "mindestens %s",
+			"too cold":                                                                 "zu kalt",
+			"too hot":                                                                  "zu heiß",
+			"too windy":                                                                "zu windig",
+			"likely precipitation":                                                     "Niederschlag wahrscheinlich",
+			"dark":                                                                     "dunkel",
+			"Cycling":                                                                  "Radfahren",
+			"Running":                                                                  "Laufen",
+			"Hiking":                                                                   "Wandern",
+			"Picnic":                                                                   "Picknick",
+			"Outdoor Event":                                                            "Veranstaltung im Freien",
+			"Beach":                                                                    "Strand",
+			"Custom":                                                                   "Benutzerdefiniert",
+			"steady":                                                                   "gleichbleibend",
+			"rising":                                                                   "steigend",
+			"rising quickly":                                                           "schnell steigend",
+			"falling":                                                                  "fallend",
+			"falling quickly":                                                          "schnell fallend",
+			"Dangerously Cold":                                                         "Gefährlich kalt",
+			"Very Cold":                                                                "Sehr kalt",
+			"Cold":                                                                     "Kalt",
+			"Cool":                                                                     "Kühl",
+			"Mild":                                                                     "Mild",
+			"Warm":                                                                     "Warm",
+			"Hot":                                                                      "Heiß",
+			"Very Hot":                                                                 "Sehr heiß",
+			"Dangerously Hot":                                                          "Gefährlich heiß",
+			"Dry":                                                                      "Trocken",
+			"Comfortable":                                                              "Angenehm",
+			"Slightly Humid":                                                           "Leicht feucht",
+			"Humid":                                                                    "Feucht",
+			"Muggy":                                                                    "Schwül",
+			"Oppressive":                                                               "Drückend",
+			"Calm":                                                                     "Windstille",
+			"Light Air":                                                                "Leiser Zug",
+			"Light Breeze":                                                             "Leichte Brise",
+			"Gentle Breeze":                                                            "Schwache Brise",
+			"Moderate Breeze":                                                          "Mäßige Brise",
+			"Fresh Breeze":                                                             "Frische Brise",
+			"Strong Breeze":                                                            "Starker Wind",
+			"Near Gale":                                                                "Steifer Wind",
+			"Gale":                                                                     "Stürmischer Wind",
+			"Strong Gale":                                                              "Sturm",
+			"Storm":                                                                    "Schwerer Sturm",
+			"Violent Storm":                                                            "Orkanartiger Sturm",
+			"Hurricane Force":                                                          "Orkan",
+			"%s (all times local)":                                                     "%s (alle Zeiten in Ortszeit)",
+			"Next %d Hours":                                                            "Nächste %d Stunden",
+			"%d Hours from %s":                                                         "%d Stunden ab %s",
+			"%d-Day Forecast":                                                          "%d-Tage-Vorhersage",
+			"Forecast by Part of Day":                                                  "Vorhersage nach Tageszeit",
+			"Forecast Periods":                                                         "Vorhersagezeiträume",
+			"%.0f%% chance rain":                                                       "%.0f%% Regenwahrscheinlichkeit",
+			"%.0f%% chance precipitation":                                              "%.0f%% Niederschlagswahrscheinlichkeit",
+			"wind %s":                                                                  "Wind %s",
+			"wind up to %s":                                                            "Wind bis %s",
+			"humidity %.0f%%":                                                          "Luftfeuchtigkeit %.0f%%",
+			"pressure %s":                                                              "Luftdruck %s",
+			"clouds %.0f%%":                                                            "Bewölkung %.0f%%",
+			"Geocoding Results":                                                        "Geokodierungsergebnisse",
+			"Reverse Geocoding":                                                        "Umgekehrte Geokodierung",
+			"No matching locations found.":                                             "Keine passenden Orte gefunden.",
+			"Found %d matching locations (best match first):": "%d passende Orte gefunden (bester Treffer zuerst):",
+			"timezone %s":   "Zeitzone %s",
+			"population %d": "%d Einwohner",
+			"Pass the coordinates of the intended place as `location` to the weather tools to avoid ambiguity.": "Übergib die Koordinaten des gemeinten Ortes als `location` an die Wetter-Tools, um Mehrdeutigkeiten zu vermeiden.",
+			"No forecast data available for the requested days.":                                                "Keine Vorhersagedaten für die angefragten Tage verfügbar.",
+			"No forecast data available from %s; the forecast runs until %s.":                                   "Keine Vorhersagedaten ab %s verfügbar; die Vorhersage reicht bis %s.",
+			"No forecast periods available for the requested range.":                                            "Keine Vorhersagezeiträume für den angefragten Zeitraum verfügbar.",
+			"Night":        "Nacht",
+			"Morning":      "Morgen",
+			"Afternoon":    "Nachmittag",
+			"Evening":      "Abend",
+			"Clear":        "Klar",
+			"Clouds":       "Bewölkt",
+			"Rain":         "Regen",
+			"Drizzle":      "Nieselregen",
+			"Thunderstorm": "Gewitter",
+			"Snow":         "Schnee",
+			"Mist":         "Dunst",
+			"Fog":          "Nebel",
+			"Haze":         "Trübung",
+			"Smoke":        "Rauch",
+			"Dust":         "Staub",
+			"Sand":         "Sand",
+			"Ash":          "Asche",
+			"Squall":       "Sturmböen",
+			"Tornado":      "Tornado",
+		},
+	},
+	langFrench: {
+		Decimal:     ",",
+		DayLayout:   "Mon 2 Jan",
+		ClockLayout: "15:04",
+		Weekdays:    [7]string{"dim.", "lun.", "mar.", "mer.", "jeu.", "ven.", "sam."},
+		Months:      [12]string{"janv.", "févr.", "mars", "avr.", "mai", "juin", "juil.", "août", "sept.", "oct.", "nov.", "déc."},
+		Compass:     strings.NewReplacer("W", "O"),
+		Messages: map[string]string{
+			"Weather Information":  "Informations météo",
+			"Weather Forecast":     "Prévisions météo",
+			"Requested location":   "Lieu demandé",
+			"Current Conditions":   "Conditions actuelles",
+			"Condition":            "Temps",
+			"Temperature":          "Température",
+			"feels like %s":        "ressenti %s",
+			"Range":                "Plage",
+			"Humidity":             "Humidité",
+			"Pressure":             "Pression",
+			"Details":              "Détails",
+			"Wind":                 "Vent",
+			"Visibility":           "Visibilité",
+			"Cloudiness":           "Nébulosité",
+			"Rain (last hour)":     "Pluie (dernière heure)",
+			"Snow (last hour)":     "Neige (dernière heure)",
+			"Location":             "Lieu",
+			"City":                 "Ville",
+			"Coordinates":          "Coordonnées",
+			"Sunrise":              "Lever du soleil",
+			"Sunset":               "Coucher du soleil",
+			"Observed":             "Observé",
+			"Timezone":             "Fuseau horaire",
+			"Station":              "Station",
+			"Forecast Office":      "Bureau de prévision",
+			"grid %d,%d":           "grille %d,%d",
+			"Source":               "Source",
+			"Air Quality":          "Qualité de l'air",
+			"Current Air Quality":  "Qualité de l'air actuelle",
+			"Air Quality Index":    "Indice de qualité de l'air",
+			"Main Pollutant":       "Polluant principal",
+			"Measured":             "Mesuré",
+			"Health Guidance":      "Conseils santé",
+			"Pollutants (µg/m³)":   "Polluants (µg/m³)",
+			"Air Quality Forecast": "Prévisions de qualité de l'air",
+			"%d of 5 (%s)":         "%d sur 5 (%s)",
+			"%s up to %s µg/m³":    "%s jusqu'à %s µg/m³",
+			"Good":                 "Bonne",
+			"Fair":                 "Correcte",
+			"Moderate":             "Moyenne",
+			"Poor":                 "Mauvaise",
+			"Very Poor":            "Très mauvaise",
+			"Air quality is good. Enjoy outdoor activities.":                                                                                     "La qualité de l'air est bonne. Profitez des activités en plein air.",
+			"Air quality is acceptable. Unusually sensitive people should consider limiting prolonged outdoor exertion.":                         "La qualité de l'air est acceptable. Les personnes particulièrement sensibles devraient limiter les efforts prolongés en plein air.",
+			"Sensitive groups (children, older adults and people with heart or lung disease) should reduce prolonged or heavy outdoor exertion.": "Les personnes sensibles (enfants, personnes âgées et personnes atteintes de maladies cardiaques ou pulmonaires) devraient réduire les efforts prolongés ou intenses en plein air.",
+			"Everyone may begin to feel health effects: sensitive groups should avoid outdoor exertion and everyone else should limit it.":       "Tout le monde peut ressentir des effets sur la santé : les personnes sensibles devraient éviter les efforts en plein air et les autres les limiter.",
+			"Health alert: everyone should avoid outdoor exertion and sensitive groups should stay indoors.":                                     "Alerte sanitaire : tout le monde devrait éviter les efforts en plein air et les personnes sensibles devraient rester à l'intérieur.",
+			"Weather History":                       "Historique météo",
+			"Daily Summary":                         "Résumé quotidien",
+			"No data available yet for this day.":   "Pas encore de données pour ce jour.",
+			"mean %s":                               "moyenne %s",
+			"precipitation %s":                      "précipitations %s",
+			"gusts %s":                              "rafales %s",
+			"Period Summary":                        "Résumé de la période",
+			"Warmest day":                           "Jour le plus chaud",
+			"Coldest day":                           "Jour le plus froid",
+			"Mean temperature":                      "Température moyenne",
+			"Total precipitation":                   "Précipitations totales",
+			"Wettest day":                           "Jour le plus pluvieux",
+			"Hourly Detail: %s":                     "Détail horaire : %s",
+			"Elevation":                             "Altitude",
+			"Open-Meteo historical weather archive": "Archives météo historiques d'Open-Meteo",
+			"Comfort":                               "Confort",
+			"Dew Point":                             "Point de rosée",
+			"Heat Index":                            "Indice de chaleur",
+			"Wind Chill":                            "Refroidissement éolien",
+			"Apparent Temperature":                  "Température apparente",
+			"Humidity Comfort":                      "Confort hygrométrique",
+			"Beaufort Scale":                        "Échelle de Beaufort",
+			"Force %d (%s)":                         "Force %d (%s)",
+			"up to Force %d (%s)":                   "jusqu'à force %d (%s)",
+			"dew point %s (%s)":                     "point de rosée %s (%s)",
+			"dew point up to %s (%s)":               "point de rosée jusqu'à %s (%s)",
+			"apparent %s (%s)":                      "ressenti %s (%s)",
+			"%s at sea level, %s at ground level":   "%s au niveau de la mer, %s au sol",
+			"ground %s":                             "au sol %s",
+			"rain %s":                               "pluie %s",
+			"snow %s":                               "neige %s",
+			"visibility %s":                         "visibilité %s",
+			"visibility down to %s":                 "visibilité jusqu'à %s",
+			"Weather Comparison":                    "Comparaison météo",
+			"When":                                  "Quand",
+			"Feels Like":                            "Ressenti",
+			"Precipitation":                         "Précipitations",
+			"Precipitation Chance":                  "Probabilité de précipitations",
+			"High":                                  "Maximale",
+			"Low":                                   "Minimale",
+			"Best and Worst":                        "Meilleur et pire",
+			"best %s (%s), worst %s (%s)":           "meilleur %s (%s), pire %s (%s)",
+			"Unavailable":                           "Indisponible",
+			"Astronomy":                             "Astronomie",
+			"Date":                                  "Date",
+			"Sun":                                   "Soleil",
+			"Solar Noon":                            "Midi solaire",
+			"Day Length":                            "Durée du jour",
+			"Civil Twilight":                        "Crépuscule civil",
+			"Nautical Twilight":                     "Crépuscule nautique",
+			"Astronomical Twilight":                 "Crépuscule astronomique",
+			"dawn %s, dusk %s":                      "aube %s, crépuscule %s",
+			"all night":                             "toute la nuit",
+			"none":                                  "aucun",
+			"up all day":                            "visible toute la journée",
+			"down all day":                          "couché toute la journée",
+			"Moon":                                  "Lune",
+			"Phase":                                 "Phase",
+			"%s, %s days old":                       "%s, %s jours",
+			"Illumination":                          "Illumination",
+			"Moonrise":                              "Lever de lune",
+			"Moonset":                               "Coucher de lune",
+			"New Moon":                              "Nouvelle lune",
+			"Waxing Crescent":                       "Premier croissant",
+			"First Quarter":                         "Premier quartier",
+			"Waxing Gibbous":                        "Gibbeuse croissante",
+			"Full Moon":                             "Pleine lune",
+			"Waning Gibbous":                        "Gibbeuse décroissante",
+			"Last Quarter":                          "Dernier quartier",
+			"Waning Crescent":                       "Dernier croissant",
+			"estimated from longitude":              "estimé d'après la longitude",
+			"Computed locally from astronomical formulas": "Calculé localement avec des formules astronomiques",
+			"Activity Suitability: %s in %s":              "Conditions pour l'activité : %s à %s",
+			"Profile":                                     "Profil",
+			"Best Windows":                                "Meilleurs créneaux",
+			"No time in the forecast meets the profile.":  "Aucun moment des prévisions ne correspond au profil.",
+			"The closest is %s (score %d): %s.":           "Le plus proche est %s (score %d) : %s.",
+			"score %d":                                    "score %d",
+			"Daily Outlook":                               "Aperçu quotidien",
+			"%d of %d slots suitable":                     "%d créneaux adaptés sur %d",
+			"best score %d":                               "meilleur score %d",
+			"mostly %s":                                   "surtout %s",
+			"Scores run from 0 to 100; windows only include slots within every limit.": "Les scores vont de 0 à 100 ; les créneaux ne comprennent que des périodes dans toutes les limites.",
+			"precipitation chance up to %.0f%%":                                        "risque de précipitations jusqu'à %.0f%%",
+			"daylight only":                                                            "de jour uniquement",
+			"at least %s":                                                              "au moins %s",
+			"too cold":                                                                 "trop froid",
+			"too hot":                                                                  "trop chaud",
+			"too windy":                                                                "trop venteux",
+			"likely precipitation":                                                     "précipitations probables",
+			"dark":                                                                     "nuit",
+			"Cycling":                                                                  "Vélo",
+			"Running":                                                                  "Course à pied",
+			"Hiking":                                                                   "Randonnée",
+			"Picnic":                                                                   "Pique-nique",
+			"Outdoor Event":                                                            "Événement en plein air",
+			"Beach":                                                                    "Plage",
+			"Custom":                                                                   "Personnalisé",
+			"steady":                                                                   "stable",
+			"rising":                                                                   "en hausse",
+			"rising quickly":                                                           "en hausse rapide",
+			"falling":                                                                  "en baisse",
+			"falling quickly":                                                          "en baisse rapide",
+			"Dangerously Cold":                                                         "Froid dangereux",
+			"Very Cold":                                                                "Très froid",
+			"Cold":                                                                     "Froid",
+			"Cool":                                                                     "Frais",
+			"Mild":                                                                     "Doux",
+			"Warm":                                                                     "Tiède",
+			"Hot":                                                                      "Chaud",
+			"Very Hot":                                                                 "Très chaud",
+			"Dangerously Hot":                                                          "Chaleur dangereuse",
+			"Dry":                                                                      "Sec",
+			"Comfortable":                                                              "Confortable",
+			"Slightly Humid":                                                           "Légèrement humide",
+			"Humid":                                                                    "Humide",
+			"Muggy":                                                                    "Lourd",
+			"Oppressive":                                                               "Étouffant",
+			"Calm":                                                                     "Calme",
+			"Light Air":                                                                "Très légère brise",
+			"Light Breeze":                                                             "Légère brise",
+			"Gentle Breeze":                                                            "Petite brise",
+			"Moderate Breeze":                                                          "Jolie brise",
+			"Fresh Breeze":                                                             "Bonne brise",
+			"Strong Breeze":                                                            "Vent frais",
+			"Near Gale":                                                                "Grand frais",
+			"Gale":                                                                     "Coup de vent",
+			"Strong Gale":                                                              "Fort coup de vent",
+			"Storm":                                                                    "Tempête",
+			"Violent Storm":                                                            "Violente tempête",
+			"Hurricane Force":                                                          "Ouragan",
+			"%s (all times local)":                                                     "%s (toutes les heures sont locales)",
+			"Next %d Hours":                                                            "%d prochaines heures",
+			"%d Hours from %s":                                                         "%d heures à partir du %s",
+			"%d-Day Forecast":                                                          "Prévisions sur %d jours",
+			"Forecast by Part of Day":                                                  "Prévisions par moment de la journée",
+			"Forecast Periods":                                                         "Périodes de prévision",
+			"%.0f%% chance rain":                                                       "%.0f%% de risque de pluie",
+			"%.0f%% chance precipitation":                                              "%.0f%% de risque de précipitations",
+			"wind %s":                                                                  "vent %s",
+			"wind up to %s":                                                            "vent jusqu'à %s",
+			"humidity %.0f%%":                                                          "humidité %.0f%%",
+			"pressure %s":                                                              "pression %s",
+			"clouds %.0f%%":                                                            "nuages %.0f%%",
+			"Geocoding Results":                                                        "Résultats du géocodage",
+			"Reverse Geocoding":                                                        "Géocodage inverse",
+			"No matching locations found.":                                             "Aucun lieu correspondant trouvé.",
+			"Found %d matching locations (best match first):": "%d lieux correspondants trouvés (meilleure correspondance en premier) :",
+			"timezone %s":   "fuseau horaire %s",
+			"population %d": "%d habitants",
+			"Pass the coordinates of the intended place as `location` to the weather tools to avoid ambiguity.": "Passez les coordonnées du lieu voulu comme `location` aux outils météo pour éviter toute ambiguïté.",
+			"No forecast data available for the requested days.":                                                "Aucune donnée de prévision pour les jours demandés.",
+			"No forecast data available from %s; the forecast runs until %s.":                                   "Aucune donnée de prévision à partir du %s ; les prévisions s'arrêtent au %s.",
+			"No forecast periods available for the requested range.":                                            "Aucune période de prévision pour l'intervalle demandé.",
+			"Night":        "Nuit",
+			"Morning":      "Matin",
+			"Afternoon":    "Après-midi",
+			"Evening":      "Soir",
+			"Clear":        "Dégagé",
+			"Clouds":       "Nuageux",
+			"Rain":         "Pluie",
+			"Drizzle":      "Bruine",
+			"Thunderstorm": "Orage",
+			"Snow":         "Neige",
+			"Mist":         "Brume",
+			"Fog":          "Brouillard",
+			"Haze":         "Brume sèche",
+			"Smoke":        "Fumée",
+			"Dust":         "Poussière",
+			"Sand":         "Sable",
+			"Ash":          "Cendres",
+			"Squall":       "Grain",
+			"Tornado":      "Tornade",
+		},
+	},
+}
+
+// Localizer renders output strings, numbers and dates in one of the supported languages
+type Localizer struct {
+	Lang   string
+	locale locale
+}
+
+// newLocalizer returns the localizer for lang, falling back to English for unsupported languages
+func newLocalizer(lang string) *Localizer {
+	l, ok := locales[lang]
+	if !ok {
+		lang, l = langEnglish, locales[langEnglish]
+	}
+	return &Localizer{Lang: lang, locale: l}
+}
+
+// T translates an English message from the catalog and fills in its arguments. Messages missing
+// from the catalog are used as-is.
+func (l *Localizer) T(message string, args ...any) string {
+	if translated, ok := l.locale.Messages[message]; ok {
+		message = translated
+	}
+	if len(args) == 0 {
+		return message
+	}
+	return fmt.Sprintf(message, args...)
+}
+
+// Number formats a value with the given number of decimals and the language's decimal separator
+func (l *Localizer) Number(value float64, decimals int) string {
+	return formatDecimal(value, decimals, l.locale.Decimal)
+}
+
+// formatDecimal formats a value without grouping, swapping the decimal point for separator if set
+func formatDecimal(value float64, decimals int, separator string) string {
+	formatted := strconv.FormatFloat(value, 'f', decimals, 64)
+	if separator == "" || separator == "." {
+		return formatted
+	}
+	return strings.Replace(formatted, ".", separator, 1)
+}
+
+// Units returns the named unit system formatting values with the language's decimal separator
+func (l *Localizer) Units(units string) UnitSystem {
+	system := getUnitSystem(units)
+	system.decimal = l.locale.Decimal
+	return system
+}
+
+// Day formats a date like "Tue Sep 2" in the language's word order and names
+func (l *Localizer) Day(t time.Time) string {
+	return l.names(t, t.Format(l.locale.DayLayout))
+}
+
+// Clock formats a time of day, using a 24-hour clock outside English
+func (l *Localizer) Clock(t time.Time) string {
+	return t.Format(l.locale.ClockLayout)
+}
+
+// DateTime formats a date and time of day like "Tue Sep 2 3:00 PM"
+func (l *Localizer) DateTime(t time.Time) string {
+	return l.Day(t) + " " + l.Clock(t)
+}
+
+// WeekdayClock formats a weekday and time of day like "Tue 3:00 PM"
+func (l *Localizer) WeekdayClock(t time.Time) string {
+	return l.locale.Weekdays[t.Weekday()] + " " + l.Clock(t)
+}
+
+// names swaps the English weekday and month abbreviations Go's layouts produce for the language's own
+func (l *Localizer) names(t time.Time, formatted string) string {
+	return strings.NewReplacer(
+		t.Weekday().String()[:3], l.locale.Weekdays[t.Weekday()],
+		t.Month().String()[:3], l.locale.Months[t.Month()-1],
+	).Replace(formatted)
+}
+
+// Compass translates a compass point such as "WSW" into the language's abbreviations
+func (l *Localizer) Compass(direction string) string {
+	return l.locale.Compass.Replace(direction)
+}
+
+// Title capitalizes an upstream description: every word in English, only the first elsewhere
+func (l *Localizer) Title(s string) string {
+	if l.Lang == langEnglish || s == "" {
+		return toTitle(s)
+	}
+	runes := []rune(s)
+	runes[0] = unicode.ToUpper(runes[0])
+	return string(runes)
+}
+
+// withLanguage adds the shared lang argument to a tool definition
+func withLanguage() mcp.ToolOption {
+	return mcp.WithString("lang",
+		mcp.Description("Output language (optional). 'en', 'es', 'de' or 'fr'. Defaults to the client's Accept-Language header, then the language of the client's IP country, then English."),
+		mcp.Enum(supportedLanguages...),
+	)
+}
+
+// resolveLanguage returns the explicit lang argument if given, otherwise the best match for the
+// client's Accept-Language header, otherwise the language of a location found from the client IP
+func resolveLanguage(ctx context.Context, request mcp.CallToolRequest, resolved *ResolvedLocation) (string, error) {
+	if lang := request.GetString("lang", ""); lang != "" {
+		lang = strings.ToLower(lang)
+		if _, ok := locales[lang]; !ok {
+			return "", fmt.Errorf("invalid lang %q: must be one of %s", lang, strings.Join(supportedLanguages, ", "))
+		}
+		return lang, nil
+	}
+
+	if header, ok := ctx.Value(AcceptLanguageKey).(string); ok && header != "" {
+		if lang, ok := matchAcceptLanguage(header); ok {
+			return lang, nil
+		}
+	}
+
+	if resolved != nil && resolved.Source == locationSourceIP {
+		if lang, ok := countryLanguages[strings.ToUpper(resolved.Country)]; ok {
+			return lang, nil
+		}
+	}
+
+	return langEnglish, nil
+}
+
+// matchAcceptLanguage picks the supported language that best fits an Accept-Language header
+func matchAcceptLanguage(header string) (string, bool) {
+	tags, _, err := language.ParseAcceptLanguage(header)
+	if err != nil || len(tags) == 0 {
+		return "", false
+	}
+	_, index, confidence := languageMatcher.Match(tags...)
+	if confidence == language.No {
+		return "", false
+	}
+	return supportedLanguages[index], true
+}
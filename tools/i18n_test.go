@@ -0,0 +1,145 @@
+package tools
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestResolveLanguage(t *testing.T) {
+	ipLocation := &ResolvedLocation{Country: "ES", Source: locationSourceIP}
+	geocodedLocation := &ResolvedLocation{Country: "ES", Source: locationSourceGeocoding}
+
+	testCases := []struct {
+		name           string
+		lang           string
+		acceptLanguage string
+		resolved       *ResolvedLocation
+		expected       string
+	}{
+		{"explicit argument", "FR", "de-DE", ipLocation, langFrench},
+		{"accept-language", "", "de-AT,de;q=0.9,en;q=0.5", ipLocation, langGerman},
+		{"accept-language region", "", "es-MX", nil, langSpanish},
+		{"unsupported accept-language", "", "ja-JP", ipLocation, langSpanish},
+		{"ip country", "", "", ipLocation, langSpanish},
+		{"geocoded country", "", "", geocodedLocation, langEnglish},
+		{"no hints", "", "", nil, langEnglish},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.WithValue(context.Background(), AcceptLanguageKey, tc.acceptLanguage)
+			args := map[string]any{}
+			if tc.lang != "" {
+				args["lang"] = tc.lang
+			}
+
+			got, err := resolveLanguage(ctx, mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: args}}, tc.resolved)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if got != tc.expected {
+				t.Errorf("resolveLanguage() = %q, expected %q", got, tc.expected)
+			}
+		})
+	}
+
+	request := mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: map[string]any{"lang": "klingon"}}}
+	if _, err := resolveLanguage(context.Background(), request, nil); err == nil || !strings.Contains(err.Error(), "invalid lang") {
+		t.Errorf("Expected invalid lang error, got %v", err)
+	}
+}
+
+func TestLocalizer(t *testing.T) {
+	moment := time.Date(2025, 9, 2, 15, 0, 0, 0, offsetLocation(7200)) // a Tuesday
+
+	testCases := []struct {
+		lang     string
+		dateTime string
+		number   string
+		compass  string
+	}{
+		{langEnglish, "Tue Sep 2 3:00 PM", "28.4", "WSW"},
+		{langSpanish, "mar 2 sep 15:00", "28,4", "OSO"},
+		{langGerman, "Di, 2. Sep 15:00", "28,4", "WSW"},
+		{langFrench, "mar. 2 sept. 15:00", "28,4", "OSO"},
+	}
+
+	for _, tc := range testCases {
+		l := newLocalizer(tc.lang)
+		if got := l.DateTime(moment); got != tc.dateTime {
+			t.Errorf("%s DateTime() = %q, expected %q", tc.lang, got, tc.dateTime)
+		}
+		if got := l.Number(28.4, 1); got != tc.number {
+			t.Errorf("%s Number() = %q, expected %q", tc.lang, got, tc.number)
+		}
+		if got := l.Compass("WSW"); got != tc.compass {
+			t.Errorf("%s Compass() = %q, expected %q", tc.lang, got, tc.compass)
+		}
+	}
+
+	if got := newLocalizer(langGerman).Compass("ESE"); got != "OSO" {
+		t.Errorf("German Compass(ESE) = %q, expected OSO", got)
+	}
+	if got := newLocalizer(langGerman).Units("imperial").FormatPressure(1015); got != "29,97 inHg" {
+		t.Errorf("German FormatPressure() = %q, expected 29,97 inHg", got)
+	}
+	if got := newLocalizer("pt").Lang; got != langEnglish {
+		t.Errorf("Unsupported language should fall back to English, got %q", got)
+	}
+}
+
+func TestWeatherTool_Language(t *testing.T) {
+	os.Setenv("OPENWEATHER_API_KEY", "test_api_key")
+	defer os.Unsetenv("OPENWEATHER_API_KEY")
+
+	var requestedLang string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestedLang = r.URL.Query().Get("lang")
+		fixture := "valencia_weather_response.json"
+		if r.URL.Path == "/data/2.5/forecast" {
+			fixture = "valencia_forecast_response.json"
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(loadFixture(t, fixture))
+	}))
+	defer server.Close()
+
+	originalURL := openWeatherMapBaseURL
+	openWeatherMapBaseURL = server.URL
+	defer func() { openWeatherMapBaseURL = originalURL }()
+
+	weatherTool := NewWeatherTool()
+	content, isError := callToolText(t, weatherTool.Handler, map[string]any{"location": "39.4697,-0.3763", "units": "metric", "lang": "es"})
+	if isError {
+		t.Fatalf("Expected success, got error: %s", content)
+	}
+	if requestedLang != "es" {
+		t.Errorf("Expected lang=es to be passed to OpenWeatherMap, got %q", requestedLang)
+	}
+
+	expected := []string{"# Información meteorológica:", "## Condiciones actuales", "**Temperatura:** 28,4°C", "**Amanecer:** 07:27", "**Observado:** mar 2 sep 15:00"}
+	for _, text := range expected {
+		if !strings.Contains(content, text) {
+			t.Errorf("Expected %q in Spanish output:\n%s", text, content)
+		}
+	}
+
+	forecastTool := NewWeatherForecastTool()
+	content, isError = callToolText(t, forecastTool.Handler, map[string]any{"location": "39.4676,-0.3771", "granularity": "daypart", "start": "wednesday", "days": 1, "lang": "de"})
+	if isError {
+		t.Fatalf("Expected success, got error: %s", content)
+	}
+	if requestedLang != "de" {
+		t.Errorf("Expected lang=de to be passed to OpenWeatherMap, got %q", requestedLang)
+	}
+	if !strings.Contains(content, "## Vorhersage nach Tageszeit") || !strings.Contains(content, "**Mi, 3. Sep Nacht**:") {
+		t.Errorf("Expected German part-of-day summaries:\n%s", content)
+	}
+}
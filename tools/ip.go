@@ -15,6 +15,11 @@ type contextKey string
 
 const ClientIPKey contextKey = "client-ip"
 
+// IPResult is the structured form of the get_ip tool's answer
+type IPResult struct {
+	IP string `json:"ip"`
+}
+
 type IPTool struct {
 	Tool    mcp.Tool
 	Handler func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error)
@@ -30,17 +35,25 @@ func NewIPTool() *IPTool {
 func ipTool() mcp.Tool {
 	return mcp.NewTool("get_ip",
 		mcp.WithDescription("Get the IP address of the client making the request"),
+		readOnlyTool("Client IP Address", false),
+		withFormat(),
+		mcp.WithOutputSchema[IPResult](),
 	)
 }
 
 func ipToolHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	format, err := parseFormat(request)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
 	// Extract client IP from context
 	clientIP, ok := ctx.Value(ClientIPKey).(string)
 	if !ok || clientIP == "" {
 		return mcp.NewToolResultError("Could not determine client IP address"), nil
 	}
 
-	return mcp.NewToolResultText(clientIP), nil
+	return renderResult(format, clientIP, IPResult{IP: clientIP}), nil
 }
 
 type IPDataTool struct {
@@ -65,6 +78,21 @@ type IPData struct {
 	AS          string  `json:"as"`
 }
 
+// CompactSummary renders the lookup as one line, e.g. "8.8.8.8: Ashburn, Virginia, United States (Google LLC)"
+func (d IPData) CompactSummary() string {
+	var place []string
+	for _, part := range []string{d.City, d.RegionName, d.Country} {
+		if part != "" {
+			place = append(place, part)
+		}
+	}
+	summary := fmt.Sprintf("%s: %s", d.Query, strings.Join(place, ", "))
+	if d.ISP != "" {
+		summary += fmt.Sprintf(" (%s)", d.ISP)
+	}
+	return summary
+}
+
 func NewIPDataTool() *IPDataTool {
 	return &IPDataTool{
 		Tool:    ipDataTool(),
@@ -75,9 +103,12 @@ func NewIPDataTool() *IPDataTool {
 func ipDataTool() mcp.Tool {
 	return mcp.NewTool("get_ip_data",
 		mcp.WithDescription("Get detailed information about the client's IP address including geolocation data"),
+		readOnlyTool("IP Address Lookup", true),
 		mcp.WithString("ip",
 			mcp.Description("IP address to lookup (optional, uses client IP if not provided)"),
 		),
+		withFormat(),
+		mcp.WithOutputSchema[IPData](),
 	)
 }
 
@@ -93,7 +124,12 @@ func FetchIPData(ctx context.Context, targetIP string) (*IPData, error) {
 	}
 
 	url := fmt.Sprintf("http://ip-api.com/json/%s", targetIP)
-	resp, err := http.Get(url)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create IP data request: %v", err)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch IP data: %v", err)
 	}
@@ -117,6 +153,11 @@ func FetchIPData(ctx context.Context, targetIP string) (*IPData, error) {
 }
 
 func ipDataToolHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	format, err := parseFormat(request)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
 	var targetIP string
 
 	// Check if IP parameter is provided
@@ -135,7 +176,7 @@ func ipDataToolHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.C
 	}
 
 	result := FormatIPDataAsMarkdown(*ipData)
-	return mcp.NewToolResultText(result), nil
+	return renderResult(format, result, *ipData), nil
 }
 
 func FormatIPDataAsMarkdown(data IPData) string {
@@ -0,0 +1,76 @@
+package tools
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// rpcRequest is the part of a JSON-RPC message that the HTTP middlewares need to answer methods
+// mcp-go does not route itself. Result and Error are only set on responses to requests the server
+// sent to the client.
+type rpcRequest struct {
+	ID     mcp.RequestId   `json:"id"`
+	Method mcp.MCPMethod   `json:"method"`
+	Params json.RawMessage `json:"params"`
+	Result json.RawMessage `json:"result"`
+	Error  json.RawMessage `json:"error"`
+}
+
+// maxRPCBodyBytes caps the size of the POSTed JSON-RPC messages RPCMiddleware reads
+const maxRPCBodyBytes = 1 << 20
+
+// rpcRequestKey holds the JSON-RPC request RPCMiddleware decoded
+const rpcRequestKey contextKey = "rpc-request"
+
+// RPCMiddleware reads a POSTed JSON-RPC message once, up to maxRPCBodyBytes, and keeps the decoded
+// request in the request context for the middlewares it wraps. The body is restored for mcp-go.
+func RPCMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxRPCBodyBytes))
+		if err != nil {
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) {
+				http.Error(w, "Request body too large", http.StatusRequestEntityTooLarge)
+				return
+			}
+			http.Error(w, "Failed to read request body", http.StatusBadRequest)
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		var request rpcRequest
+		if json.Unmarshal(body, &request) == nil {
+			r = r.WithContext(context.WithValue(r.Context(), rpcRequestKey, &request))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// peekRPCRequest returns the JSON-RPC request RPCMiddleware decoded. It returns nil for anything
+// else, such as GET streams, batches or malformed JSON, which mcp-go reports itself.
+func peekRPCRequest(r *http.Request) *rpcRequest {
+	request, _ := r.Context().Value(rpcRequestKey).(*rpcRequest)
+	return request
+}
+
+// writeRPCResponse writes a JSON-RPC response, echoing the session ID like the Streamable HTTP
+// transport does
+func writeRPCResponse(w http.ResponseWriter, r *http.Request, response any) {
+	w.Header().Set("Content-Type", "application/json")
+	if sessionID := r.Header.Get(server.HeaderKeySessionID); sessionID != "" {
+		w.Header().Set(server.HeaderKeySessionID, sessionID)
+	}
+	json.NewEncoder(w).Encode(response)
+}
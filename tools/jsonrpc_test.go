@@ -0,0 +1,47 @@
+package tools
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRPCMiddleware(t *testing.T) {
+	var seen *rpcRequest
+	var body string
+	handler := RPCMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen = peekRPCRequest(r)
+		data, _ := io.ReadAll(r.Body)
+		body = string(data)
+	}))
+
+	message := `{"jsonrpc":"2.0","id":7,"method":"tools/list"}`
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/mcp", strings.NewReader(message)))
+	if seen == nil || seen.Method != "tools/list" || seen.ID.String() != "int64:7" {
+		t.Errorf("Expected the decoded request in the context, got %+v", seen)
+	}
+	if body != message {
+		t.Errorf("Expected the body to be restored, got %q", body)
+	}
+
+	// Batches and other methods reach mcp-go without a decoded request
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/mcp", strings.NewReader(`[`+message+`]`)))
+	if seen != nil {
+		t.Errorf("Expected no decoded request for a batch, got %+v", seen)
+	}
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/mcp", nil))
+	if seen != nil {
+		t.Errorf("Expected no decoded request for a GET, got %+v", seen)
+	}
+
+	// Oversized bodies are refused before they are buffered
+	seen = nil
+	recorder := httptest.NewRecorder()
+	large := `{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"` + strings.Repeat("x", maxRPCBodyBytes) + `"}}`
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/mcp", strings.NewReader(large)))
+	if recorder.Code != http.StatusRequestEntityTooLarge || seen != nil {
+		t.Errorf("Expected status 413 without reaching the handler, got %d", recorder.Code)
+	}
+}
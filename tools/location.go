@@ -0,0 +1,218 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+const (
+	locationSourceCoordinates = "coordinates"
+	locationSourceGeocoding   = "geocoding"
+	locationSourceIP          = "ip"
+	locationSourceSaved       = "saved"
+)
+
+// ResolvedLocation is the outcome of turning a tool's location argument into coordinates
+type ResolvedLocation struct {
+	Query        string
+	Name         string
+	State        string
+	Country      string
+	Lat          float64
+	Lon          float64
+	Timezone     string
+	Source       string
+	Alternatives []GeoLocation
+	// Units is the unit system the user chose when asked for the location, if any
+	Units string
+}
+
+// usStateNames maps USPS state and territory codes to the names geocoders report
+var usStateNames = map[string]string{
+	"AL": "Alabama", "AK": "Alaska", "AZ": "Arizona", "AR": "Arkansas", "CA": "California",
+	"CO": "Colorado", "CT": "Connecticut", "DE": "Delaware", "DC": "District of Columbia", "FL": "Florida",
+	"GA": "Georgia", "HI": "Hawaii", "ID": "Idaho", "IL": "Illinois", "IN": "Indiana",
+	"IA": "Iowa", "KS": "Kansas", "KY": "Kentucky", "LA": "Louisiana", "ME": "Maine",
+	"MD": "Maryland", "MA": "Massachusetts", "MI": "Michigan", "MN": "Minnesota", "MS": "Mississippi",
+	"MO": "Missouri", "MT": "Montana", "NE": "Nebraska", "NV": "Nevada", "NH": "New Hampshire",
+	"NJ": "New Jersey", "NM": "New Mexico", "NY": "New York", "NC": "North Carolina", "ND": "North Dakota",
+	"OH": "Ohio", "OK": "Oklahoma", "OR": "Oregon", "PA": "Pennsylvania", "RI": "Rhode Island",
+	"SC": "South Carolina", "SD": "South Dakota", "TN": "Tennessee", "TX": "Texas", "UT": "Utah",
+	"VT": "Vermont", "VA": "Virginia", "WA": "Washington", "WV": "West Virginia", "WI": "Wisconsin",
+	"WY": "Wyoming", "AS": "American Samoa", "GU": "Guam", "MP": "Northern Mariana Islands",
+	"PR": "Puerto Rico", "VI": "U.S. Virgin Islands", "UM": "U.S. Minor Outlying Islands",
+}
+
+func isUSStateCode(code string) bool {
+	_, ok := usStateNames[strings.ToUpper(code)]
+	return ok
+}
+
+// ResolveLocation turns a location argument into coordinates. Empty locations fall back to the
+// client IP, '@name' saved locations and 'lat,lon' strings are used as-is, and anything else is
+// geocoded.
+func ResolveLocation(ctx context.Context, location string) (*ResolvedLocation, error) {
+	location = strings.TrimSpace(location)
+
+	if strings.HasPrefix(location, savedLocationPrefix) {
+		saved, ok := getSavedLocation(ctx, location)
+		if !ok {
+			return nil, fmt.Errorf("no saved location named %q: list_saved_locations shows the saved names", location)
+		}
+		return saved.resolved(), nil
+	}
+
+	if location == "" {
+		ipData, err := FetchIPData(ctx, "")
+		if err != nil {
+			return nil, fmt.Errorf("Failed to get location from IP: %v", err)
+		}
+		return &ResolvedLocation{
+			Name:     fmt.Sprintf("%s, %s", ipData.City, ipData.Country),
+			State:    ipData.RegionName,
+			Country:  ipData.CountryCode,
+			Lat:      ipData.Lat,
+			Lon:      ipData.Lon,
+			Timezone: ipData.Timezone,
+			Source:   locationSourceIP,
+		}, nil
+	}
+
+	if lat, lon, ok := parseCoordinates(location); ok {
+		if lat < -90 || lat > 90 || lon < -180 || lon > 180 {
+			return nil, fmt.Errorf("coordinates out of range: %s", location)
+		}
+		return &ResolvedLocation{
+			Query:  location,
+			Name:   location,
+			Lat:    lat,
+			Lon:    lon,
+			Source: locationSourceCoordinates,
+		}, nil
+	}
+
+	// Names stay in the provider's default language so that they match the query when ranked
+	candidates, err := GeocodeLocation(ctx, location, defaultGeocodeLimit, "")
+	if err != nil {
+		return nil, err
+	}
+	if len(candidates) == 0 {
+		return nil, fmt.Errorf("no locations found matching %q", location)
+	}
+
+	best := candidates[0]
+	resolved := resolvedFromCandidate(location, best)
+
+	// Other candidates that score as well as the best one make the name ambiguous
+	name, qualifiers := splitLocationQuery(location)
+	bestScore := candidateScore(name, qualifiers, best)
+	for _, candidate := range candidates[1:] {
+		if candidateScore(name, qualifiers, candidate) == bestScore {
+			resolved.Alternatives = append(resolved.Alternatives, candidate)
+		}
+	}
+
+	return resolved, nil
+}
+
+// resolvedFromCandidate resolves a query to one of its geocoding candidates
+func resolvedFromCandidate(query string, candidate GeoLocation) *ResolvedLocation {
+	return &ResolvedLocation{
+		Query:    query,
+		Name:     candidate.DisplayName(),
+		State:    candidate.State,
+		Country:  candidate.Country,
+		Lat:      candidate.Lat,
+		Lon:      candidate.Lon,
+		Timezone: candidate.Timezone,
+		Source:   locationSourceGeocoding,
+	}
+}
+
+// splitLocationQuery separates "City,State,Country" into the place name and its qualifiers
+func splitLocationQuery(query string) (string, []string) {
+	parts := strings.Split(query, ",")
+	name := strings.TrimSpace(parts[0])
+
+	var qualifiers []string
+	for _, part := range parts[1:] {
+		if part = strings.TrimSpace(part); part != "" {
+			qualifiers = append(qualifiers, part)
+		}
+	}
+	return name, qualifiers
+}
+
+// candidateScore rewards exact name matches and candidates whose state or country match the qualifiers
+func candidateScore(name string, qualifiers []string, candidate GeoLocation) int {
+	score := 0
+	if foldAccents(candidate.Name) == foldAccents(name) {
+		score += 2
+	}
+
+	for _, qualifier := range qualifiers {
+		switch {
+		case strings.EqualFold(qualifier, candidate.Country),
+			strings.EqualFold(qualifier, candidate.CountryName),
+			candidate.Country == "GB" && strings.EqualFold(qualifier, "UK"),
+			candidate.Country == "US" && (strings.EqualFold(qualifier, "USA") || strings.EqualFold(qualifier, "United States")):
+			score += 2
+		case strings.EqualFold(qualifier, candidate.State),
+			candidate.Country == "US" && strings.EqualFold(usStateNames[strings.ToUpper(qualifier)], candidate.State):
+			score += 2
+		}
+	}
+	return score
+}
+
+// rankCandidates removes duplicates and orders candidates by score, keeping the provider's
+// relevance order for ties
+func rankCandidates(name string, qualifiers []string, candidates []GeoLocation) []GeoLocation {
+	seen := make(map[string]bool)
+	unique := make([]GeoLocation, 0, len(candidates))
+	for _, candidate := range candidates {
+		key := candidate.DisplayName()
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		unique = append(unique, candidate)
+	}
+
+	sort.SliceStable(unique, func(i, j int) bool {
+		return candidateScore(name, qualifiers, unique[i]) > candidateScore(name, qualifiers, unique[j])
+	})
+
+	return unique
+}
+
+var accentReplacer = strings.NewReplacer(
+	"à", "a", "á", "a", "â", "a", "ã", "a", "ä", "a", "å", "a",
+	"ç", "c", "è", "e", "é", "e", "ê", "e", "ë", "e",
+	"ì", "i", "í", "i", "î", "i", "ï", "i", "ñ", "n",
+	"ò", "o", "ó", "o", "ô", "o", "õ", "o", "ö", "o", "ø", "o",
+	"ù", "u", "ú", "u", "û", "u", "ü", "u", "ý", "y", "ÿ", "y",
+)
+
+// foldAccents lowercases s and strips common Latin diacritics so "València" matches "Valencia"
+func foldAccents(s string) string {
+	return accentReplacer.Replace(strings.ToLower(s))
+}
+
+// formatAlternativesNote tells the caller which other places an ambiguous name could have meant
+func formatAlternativesNote(resolved *ResolvedLocation) string {
+	if resolved == nil || len(resolved.Alternatives) == 0 {
+		return ""
+	}
+
+	var builder strings.Builder
+	builder.WriteString(fmt.Sprintf("\n*Note: \"%s\" matches several places; showing %s. Other matches:*\n", resolved.Query, resolved.Name))
+	for _, alternative := range resolved.Alternatives {
+		builder.WriteString(fmt.Sprintf("- %s (%.4f,%.4f)\n", alternative.DisplayName(), alternative.Lat, alternative.Lon))
+	}
+	builder.WriteString("\n*Add a state or country (e.g., 'Springfield,MO,US') or pass coordinates to pick another.*\n")
+
+	return builder.String()
+}
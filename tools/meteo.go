@@ -0,0 +1,221 @@
+package tools
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// Ranges the heat index and wind chill are defined for (National Weather Service and Environment
+// Canada), and the Magnus coefficients for dew point
+const (
+	heatIndexMinTempF   = 80.0
+	windChillMaxTemp    = 10.0 // °C
+	windChillMinWindKmh = 4.8
+	dewPointMinHumidity = 1.0
+	magnusA, magnusB    = 17.625, 243.04
+)
+
+// Pressure changes (hPa) below which pressure counts as steady, and per 3 hours above which it
+// changes quickly, as in the Met Office's pressure tendency terms
+const (
+	pressureSteadyLimit = 1.0
+	pressureQuickRate   = 3.5
+)
+
+// apparentCategories rates an apparent temperature in °C: each entry applies below its limit
+var apparentCategories = []struct {
+	Below float64
+	Name  string
+}{
+	{-27, "Dangerously Cold"},
+	{-10, "Very Cold"},
+	{0, "Cold"},
+	{10, "Cool"},
+	{20, "Mild"},
+	{27, "Warm"}, // where the heat index "Caution" band starts
+	{32, "Hot"},
+	{39, "Very Hot"},
+	{math.Inf(1), "Dangerously Hot"},
+}
+
+// comfortLevels rates how humid the air feels from its dew point in °C
+var comfortLevels = []struct {
+	Below float64
+	Name  string
+}{
+	{10, "Dry"},
+	{16, "Comfortable"},
+	{18, "Slightly Humid"},
+	{21, "Humid"},
+	{24, "Muggy"},
+	{math.Inf(1), "Oppressive"},
+}
+
+// beaufortLimits are the upper wind speeds (m/s) of Beaufort forces 0 to 11; anything faster is 12
+var beaufortLimits = [...]float64{0.5, 1.6, 3.4, 5.5, 8.0, 10.8, 13.9, 17.2, 20.8, 24.5, 28.5, 32.7}
+
+var beaufortNames = [...]string{
+	"Calm", "Light Air", "Light Breeze", "Gentle Breeze", "Moderate Breeze", "Fresh Breeze", "Strong Breeze",
+	"Near Gale", "Gale", "Strong Gale", "Storm", "Violent Storm", "Hurricane Force",
+}
+
+// derivedMetrics are computed from temperature, humidity and wind. Temperatures are in °C like the
+// provider data, so they format through UnitSystem like any other value.
+type derivedMetrics struct {
+	DewPoint  float64
+	HeatIndex *float64
+	WindChill *float64
+	Apparent  float64
+	Category  string
+	Beaufort  int
+	Comfort   string
+}
+
+// BeaufortName describes the Beaufort force, e.g. "Gentle Breeze"
+func (m derivedMetrics) BeaufortName() string {
+	return beaufortNames[m.Beaufort]
+}
+
+// deriveMetrics computes dew point, heat index, wind chill, apparent temperature and its category,
+// Beaufort force and comfort from a temperature (°C), relative humidity (%) and wind speed (m/s).
+// Heat index and wind chill are nil outside the conditions they are defined for.
+func deriveMetrics(tempC, humidity, windMs float64) derivedMetrics {
+	metrics := derivedMetrics{
+		DewPoint:  dewPoint(tempC, humidity),
+		HeatIndex: heatIndex(tempC, humidity),
+		WindChill: windChill(tempC, windMs),
+		Apparent:  tempC,
+		Beaufort:  beaufortForce(windMs),
+	}
+
+	switch {
+	case metrics.HeatIndex != nil:
+		metrics.Apparent = *metrics.HeatIndex
+	case metrics.WindChill != nil:
+		metrics.Apparent = *metrics.WindChill
+	}
+	metrics.Category = apparentCategory(metrics.Apparent)
+	metrics.Comfort = comfortLevel(metrics.DewPoint)
+
+	return metrics
+}
+
+// dewPoint uses the Magnus approximation, accurate to about 0.1°C over normal weather ranges
+func dewPoint(tempC, humidity float64) float64 {
+	gamma := math.Log(max(humidity, dewPointMinHumidity)/100) + magnusA*tempC/(magnusB+tempC)
+	return magnusB * gamma / (magnusA - gamma)
+}
+
+// heatIndex uses the National Weather Service regression, which is defined in °F: Steadman's simple
+// formula, replaced by Rothfusz's regression with its low and high humidity adjustments when warm
+func heatIndex(tempC, humidity float64) *float64 {
+	t := celsiusToFahrenheit(tempC)
+	if t < heatIndexMinTempF {
+		return nil
+	}
+
+	rh := humidity
+	hi := 0.5 * (t + 61.0 + (t-68.0)*1.2 + rh*0.094)
+
+	if (hi+t)/2 >= 80 {
+		hi = -42.379 + 2.04901523*t + 10.14333127*rh - 0.22475541*t*rh - 0.00683783*t*t -
+			0.05481717*rh*rh + 0.00122874*t*t*rh + 0.00085282*t*rh*rh - 0.00000199*t*t*rh*rh
+
+		switch {
+		case rh < 13 && t >= 80 && t <= 112:
+			hi -= (13 - rh) / 4 * math.Sqrt((17-math.Abs(t-95))/17)
+		case rh > 85 && t >= 80 && t <= 87:
+			hi += (rh - 85) / 10 * (87 - t) / 5
+		}
+	}
+
+	result := fahrenheitToCelsius(hi)
+	return &result
+}
+
+// windChill uses the formula shared by the National Weather Service and Environment Canada, which
+// takes °C and wind in km/h
+func windChill(tempC, windMs float64) *float64 {
+	kmh := windMs * 3.6
+	if tempC > windChillMaxTemp || kmh <= windChillMinWindKmh {
+		return nil
+	}
+
+	v := math.Pow(kmh, 0.16)
+	result := 13.12 + 0.6215*tempC - 11.37*v + 0.3965*tempC*v
+	return &result
+}
+
+// Metrics derives comfort metrics from the slot's temperature, humidity and wind
+func (item ForecastItem) Metrics() derivedMetrics {
+	return deriveMetrics(item.Main.Temp, float64(item.Main.Humidity), item.Wind.Speed)
+}
+
+// pressureTrend describes a pressure change of deltaHPa over the given hours
+func pressureTrend(deltaHPa, hours float64) string {
+	quick := hours > 0 && math.Abs(deltaHPa)/hours*3 >= pressureQuickRate
+	switch {
+	case math.Abs(deltaHPa) < pressureSteadyLimit:
+		return "steady"
+	case deltaHPa > 0 && quick:
+		return "rising quickly"
+	case deltaHPa > 0:
+		return "rising"
+	case quick:
+		return "falling quickly"
+	default:
+		return "falling"
+	}
+}
+
+// apparentRange names the categories of an apparent temperature range, e.g. "Mild - Hot"
+func apparentRange(minC, maxC float64, l *Localizer) string {
+	low, high := l.T(apparentCategory(minC)), l.T(apparentCategory(maxC))
+	if low == high {
+		return low
+	}
+	return low + " - " + high
+}
+
+func apparentCategory(apparentC float64) string {
+	for _, category := range apparentCategories {
+		if apparentC < category.Below {
+			return category.Name
+		}
+	}
+	return apparentCategories[len(apparentCategories)-1].Name
+}
+
+func comfortLevel(dewPointC float64) string {
+	for _, level := range comfortLevels {
+		if dewPointC < level.Below {
+			return level.Name
+		}
+	}
+	return comfortLevels[len(comfortLevels)-1].Name
+}
+
+func beaufortForce(windMs float64) int {
+	for force, limit := range beaufortLimits {
+		if windMs < limit {
+			return force
+		}
+	}
+	return len(beaufortLimits)
+}
+
+// writeDerivedMetrics appends the comfort section computed from temperature, humidity and wind
+func writeDerivedMetrics(builder *strings.Builder, metrics derivedMetrics, system UnitSystem, l *Localizer) {
+	builder.WriteString(fmt.Sprintf("\n## %s\n", l.T("Comfort")))
+	builder.WriteString(fmt.Sprintf("- **%s:** %s\n", l.T("Dew Point"), system.FormatTemperature(metrics.DewPoint)))
+	if metrics.HeatIndex != nil {
+		builder.WriteString(fmt.Sprintf("- **%s:** %s\n", l.T("Heat Index"), system.FormatTemperature(*metrics.HeatIndex)))
+	}
+	if metrics.WindChill != nil {
+		builder.WriteString(fmt.Sprintf("- **%s:** %s\n", l.T("Wind Chill"), system.FormatTemperature(*metrics.WindChill)))
+	}
+	builder.WriteString(fmt.Sprintf("- **%s:** %s (%s)\n", l.T("Apparent Temperature"), system.FormatTemperature(metrics.Apparent), l.T(metrics.Category)))
+	builder.WriteString(fmt.Sprintf("- **%s:** %s\n", l.T("Humidity Comfort"), l.T(metrics.Comfort)))
+	builder.WriteString(fmt.Sprintf("- **%s:** %s\n", l.T("Beaufort Scale"), l.T("Force %d (%s)", metrics.Beaufort, l.T(metrics.BeaufortName()))))
+}
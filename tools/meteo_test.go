@@ -0,0 +1,255 @@
+package tools
+
+import (
+	"encoding/json"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestDewPoint(t *testing.T) {
+	testCases := []struct {
+		temp     float64
+		humidity float64
+		expected float64
+	}{
+		{25, 60, 16.7},
+		{30, 50, 18.4},
+		{0, 100, 0},
+		{-10, 70, -14.4},
+		{20, 0, -38.0}, // humidity is floored at 1% so the logarithm stays finite
+	}
+
+	for _, tc := range testCases {
+		if got := dewPoint(tc.temp, tc.humidity); math.Abs(got-tc.expected) > 0.1 {
+			t.Errorf("dewPoint(%.1f°C, %.0f%%) = %.2f, expected %.1f", tc.temp, tc.humidity, got, tc.expected)
+		}
+	}
+}
+
+func TestHeatIndex(t *testing.T) {
+	// Expected values in °F from the National Weather Service heat index chart
+	testCases := []struct {
+		tempF    float64
+		humidity float64
+		expected float64
+		applies  bool
+	}{
+		{90, 70, 106, true},
+		{96, 50, 108, true},
+		{86, 90, 105, true},
+		{110, 10, 104, true},
+		{80, 40, 80, true},
+		{79, 90, 0, false},
+		{50, 50, 0, false},
+	}
+
+	for _, tc := range testCases {
+		got := heatIndex(fahrenheitToCelsius(tc.tempF), tc.humidity)
+		if !tc.applies {
+			if got != nil {
+				t.Errorf("heatIndex(%.0f°F, %.0f%%) = %.1f, expected none", tc.tempF, tc.humidity, *got)
+			}
+			continue
+		}
+		if got == nil {
+			t.Errorf("heatIndex(%.0f°F, %.0f%%) = none, expected %.0f°F", tc.tempF, tc.humidity, tc.expected)
+			continue
+		}
+		if gotF := celsiusToFahrenheit(*got); math.Abs(gotF-tc.expected) > 1 {
+			t.Errorf("heatIndex(%.0f°F, %.0f%%) = %.1f°F, expected %.0f°F", tc.tempF, tc.humidity, gotF, tc.expected)
+		}
+	}
+}
+
+func TestWindChill(t *testing.T) {
+	// Expected values in °C from the Environment Canada wind chill chart, wind in km/h
+	testCases := []struct {
+		temp     float64
+		windKmh  float64
+		expected float64
+		applies  bool
+	}{
+		{-10, 20, -18, true},
+		{0, 10, -3, true},
+		{-30, 40, -47, true},
+		{5, 60, -2, true},
+		{5, 4, 0, false},
+		{15, 30, 0, false},
+	}
+
+	for _, tc := range testCases {
+		got := windChill(tc.temp, tc.windKmh/3.6)
+		if !tc.applies {
+			if got != nil {
+				t.Errorf("windChill(%.0f°C, %.0f km/h) = %.1f, expected none", tc.temp, tc.windKmh, *got)
+			}
+			continue
+		}
+		if got == nil || math.Abs(*got-tc.expected) > 0.6 {
+			t.Errorf("windChill(%.0f°C, %.0f km/h) = %v, expected %.0f", tc.temp, tc.windKmh, got, tc.expected)
+		}
+	}
+}
+
+func TestBeaufortForce(t *testing.T) {
+	testCases := []struct {
+		wind     float64
+		expected int
+		name     string
+	}{
+		{0, 0, "Calm"},
+		{0.5, 1, "Light Air"},
+		{4.1, 3, "Gentle Breeze"},
+		{10.8, 6, "Strong Breeze"},
+		{20, 8, "Gale"},
+		{32.6, 11, "Violent Storm"},
+		{45, 12, "Hurricane Force"},
+	}
+
+	for _, tc := range testCases {
+		force := beaufortForce(tc.wind)
+		if force != tc.expected || beaufortNames[force] != tc.name {
+			t.Errorf("beaufortForce(%.1f) = %d %s, expected %d %s", tc.wind, force, beaufortNames[force], tc.expected, tc.name)
+		}
+	}
+}
+
+func TestPressureTrend(t *testing.T) {
+	testCases := []struct {
+		change   float64
+		hours    float64
+		expected string
+	}{
+		{0, 3, "steady"},
+		{-0.9, 3, "steady"},
+		{1, 3, "rising"},
+		{-2, 3, "falling"},
+		{4, 3, "rising quickly"},
+		{-3.5, 3, "falling quickly"},
+		{-6, 21, "falling"}, // quick changes are judged per 3 hours
+		{2, 0, "rising"},
+	}
+
+	for _, tc := range testCases {
+		if got := pressureTrend(tc.change, tc.hours); got != tc.expected {
+			t.Errorf("pressureTrend(%.1f hPa, %.0fh) = %s, expected %s", tc.change, tc.hours, got, tc.expected)
+		}
+	}
+}
+
+func TestDeriveMetrics(t *testing.T) {
+	testCases := []struct {
+		name     string
+		temp     float64
+		humidity float64
+		wind     float64
+		category string
+		comfort  string
+		apparent string
+	}{
+		{"hot and humid", 33, 70, 2, "Dangerously Hot", "Oppressive", "heat index"},
+		{"warm", 28.4, 54, 4.1, "Hot", "Humid", "heat index"},
+		{"mild", 18, 60, 3, "Mild", "Comfortable", "temperature"},
+		{"cool", 8, 80, 1, "Cool", "Dry", "temperature"},
+		{"windy cold", -5, 80, 10, "Very Cold", "Dry", "wind chill"},
+		{"bitter", -25, 70, 12, "Dangerously Cold", "Dry", "wind chill"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			metrics := deriveMetrics(tc.temp, tc.humidity, tc.wind)
+			if metrics.Category != tc.category {
+				t.Errorf("Category = %s (apparent %.1f°C), expected %s", metrics.Category, metrics.Apparent, tc.category)
+			}
+			if metrics.Comfort != tc.comfort {
+				t.Errorf("Comfort = %s (dew point %.1f°C), expected %s", metrics.Comfort, metrics.DewPoint, tc.comfort)
+			}
+
+			switch tc.apparent {
+			case "heat index":
+				if metrics.HeatIndex == nil || metrics.Apparent != *metrics.HeatIndex || metrics.WindChill != nil {
+					t.Errorf("Expected the heat index as apparent temperature, got %+v", metrics)
+				}
+			case "wind chill":
+				if metrics.WindChill == nil || metrics.Apparent != *metrics.WindChill || metrics.HeatIndex != nil {
+					t.Errorf("Expected the wind chill as apparent temperature, got %+v", metrics)
+				}
+			default:
+				if metrics.HeatIndex != nil || metrics.WindChill != nil || metrics.Apparent != tc.temp {
+					t.Errorf("Expected the air temperature as apparent temperature, got %+v", metrics)
+				}
+			}
+		})
+	}
+}
+
+func TestFormatWeatherAsMarkdown_Comfort(t *testing.T) {
+	var weatherData WeatherData
+	if err := json.Unmarshal(loadFixture(t, "valencia_weather_response.json"), &weatherData); err != nil {
+		t.Fatalf("Failed to parse fixture: %v", err)
+	}
+
+	testCases := []struct {
+		units    string
+		lang     string
+		expected []string
+	}{
+		{unitsMetric, langEnglish, []string{
+			"## Comfort",
+			"**Dew Point:** 18.2°C",
+			"**Heat Index:** 29.3°C",
+			"**Apparent Temperature:** 29.3°C (Hot)",
+			"**Humidity Comfort:** Humid",
+			"**Beaufort Scale:** Force 3 (Gentle Breeze)",
+		}},
+		{unitsImperial, langEnglish, []string{"**Dew Point:** 64.8°F", "**Heat Index:** 84.8°F"}},
+		{unitsMetric, langGerman, []string{"**Taupunkt:** 18,2°C", "**Gefühlte Temperatur:** 29,3°C (Heiß)", "**Beaufort-Skala:** Stärke 3 (Schwache Brise)"}},
+	}
+
+	for _, tc := range testCases {
+		result := FormatWeatherAsMarkdown(weatherData, "", tc.units, tc.lang)
+		for _, text := range tc.expected {
+			if !strings.Contains(result, text) {
+				t.Errorf("Expected %q in %s/%s output:\n%s", text, tc.units, tc.lang, result)
+			}
+		}
+		if strings.Contains(result, "Wind Chill") {
+			t.Errorf("Wind chill should not apply at 28°C:\n%s", result)
+		}
+	}
+}
+
+func TestFormatForecast_DerivedFields(t *testing.T) {
+	var data ForecastData
+	data.City.Name = "Oslo"
+	for i, temp := range []float64{-4, -6} {
+		item := ForecastItem{Dt: 1767225600 + int64(i)*10800} // 2026-01-01 00:00 UTC
+		item.Main.Temp, item.Main.TempMin, item.Main.TempMax = temp, temp, temp
+		item.Main.Humidity = 80
+		item.Wind.Speed = 6 + float64(i)*3
+		data.List = append(data.List, item)
+	}
+
+	opts := defaultForecastOptions()
+	opts.Fields = fieldSet([]string{fieldTemperature, fieldDewPoint, fieldApparent, fieldBeaufort})
+	result := FormatForecastWithOptions(data, "", unitsMetric, langEnglish, opts)
+
+	expected := []string{
+		"**Thu 12:00 AM**: -4.0°C, dew point -6.9°C (Dry), apparent -10.5°C (Very Cold), Force 4 (Moderate Breeze)",
+		"**Thu Jan 1**: -6.0°C - -4.0°C, dew point up to -6.9°C (Dry), apparent -14.6°C - -10.5°C (Very Cold), up to Force 5 (Fresh Breeze)",
+	}
+	for _, text := range expected {
+		if !strings.Contains(result, text) {
+			t.Errorf("Expected %q in output:\n%s", text, result)
+		}
+	}
+
+	report := NewForecastReport(data, "", unitsMetric, opts)
+	if slot := report.Slots[0]; slot.Comfort.WindChill == nil || slot.Comfort.ApparentCategory != "Very Cold" || slot.Comfort.Beaufort != 4 {
+		t.Errorf("Unexpected slot comfort report: %+v", slot.Comfort)
+	}
+	if summary := report.Summaries[0]; summary.BeaufortMax != 5 || summary.ApparentMin != -14.6 {
+		t.Errorf("Unexpected summary: %+v", summary)
+	}
+}
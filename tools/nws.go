@@ -0,0 +1,602 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const (
+	providerOpenWeatherMap = "openweathermap"
+	providerNWS            = "nws"
+	providerOpenMeteo      = "open-meteo"
+)
+
+// nwsBaseURL is the National Weather Service API endpoint (overridden in tests)
+var nwsBaseURL = "https://api.weather.gov"
+
+// nwsUserAgent identifies the server to api.weather.gov, which rejects requests without one
+const nwsUserAgent = "LazyMCP/1.0 (https://github.com/Riddlerrr/lazymcp)"
+
+type WeatherAlertsTool struct {
+	Tool    mcp.Tool
+	Handler func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error)
+}
+
+// NWSQuantity is a measured value with its WMO unit code; Value is nil when the station did not report it
+type NWSQuantity struct {
+	UnitCode string   `json:"unitCode"`
+	Value    *float64 `json:"value"`
+}
+
+type NWSPointData struct {
+	Geometry struct {
+		Coordinates []float64 `json:"coordinates"`
+	} `json:"geometry"`
+	Properties struct {
+		GridID              string `json:"gridId"`
+		GridX               int    `json:"gridX"`
+		GridY               int    `json:"gridY"`
+		Forecast            string `json:"forecast"`
+		ForecastHourly      string `json:"forecastHourly"`
+		ObservationStations string `json:"observationStations"`
+		RelativeLocation    struct {
+			Properties struct {
+				City  string `json:"city"`
+				State string `json:"state"`
+			} `json:"properties"`
+		} `json:"relativeLocation"`
+		TimeZone string `json:"timeZone"`
+	} `json:"properties"`
+}
+
+type NWSForecastData struct {
+	Properties struct {
+		Units       string              `json:"units"`
+		GeneratedAt string              `json:"generatedAt"`
+		UpdateTime  string              `json:"updateTime"`
+		Periods     []NWSForecastPeriod `json:"periods"`
+	} `json:"properties"`
+}
+
+type NWSForecastPeriod struct {
+	Number                     int         `json:"number"`
+	Name                       string      `json:"name"`
+	StartTime                  string      `json:"startTime"`
+	EndTime                    string      `json:"endTime"`
+	IsDaytime                  bool        `json:"isDaytime"`
+	Temperature                float64     `json:"temperature"`
+	TemperatureUnit            string      `json:"temperatureUnit"`
+	ProbabilityOfPrecipitation NWSQuantity `json:"probabilityOfPrecipitation"`
+	WindSpeed                  string      `json:"windSpeed"`
+	WindDirection              string      `json:"windDirection"`
+	ShortForecast              string      `json:"shortForecast"`
+	DetailedForecast           string      `json:"detailedForecast"`
+}
+
+type NWSStationsData struct {
+	Features []struct {
+		Properties struct {
+			StationIdentifier string `json:"stationIdentifier"`
+			Name              string `json:"name"`
+		} `json:"properties"`
+	} `json:"features"`
+}
+
+type NWSObservationData struct {
+	Properties NWSObservationProperties `json:"properties"`
+}
+
+type NWSObservationProperties struct {
+	StationName        string      `json:"stationName"`
+	Timestamp          string      `json:"timestamp"`
+	TextDescription    string      `json:"textDescription"`
+	Temperature        NWSQuantity `json:"temperature"`
+	Dewpoint           NWSQuantity `json:"dewpoint"`
+	WindDirection      NWSQuantity `json:"windDirection"`
+	WindSpeed          NWSQuantity `json:"windSpeed"`
+	WindGust           NWSQuantity `json:"windGust"`
+	BarometricPressure NWSQuantity `json:"barometricPressure"`
+	SeaLevelPressure   NWSQuantity `json:"seaLevelPressure"`
+	Visibility         NWSQuantity `json:"visibility"`
+	RelativeHumidity   NWSQuantity `json:"relativeHumidity"`
+	WindChill          NWSQuantity `json:"windChill"`
+	HeatIndex          NWSQuantity `json:"heatIndex"`
+}
+
+type NWSAlertsData struct {
+	Title    string `json:"title"`
+	Updated  string `json:"updated"`
+	Features []struct {
+		Properties NWSAlert `json:"properties"`
+	} `json:"features"`
+}
+
+// AlertsReport is the structured form of the active alerts for a location
+type AlertsReport struct {
+	Location string     `json:"location"`
+	Lat      float64    `json:"lat"`
+	Lon      float64    `json:"lon"`
+	Count    int        `json:"count"`
+	Alerts   []NWSAlert `json:"alerts"`
+}
+
+// CompactSummary lists the active alert events on one line
+func (r AlertsReport) CompactSummary() string {
+	if len(r.Alerts) == 0 {
+		return fmt.Sprintf("%s: no active alerts", r.Location)
+	}
+	events := make([]string, 0, len(r.Alerts))
+	for _, alert := range r.Alerts {
+		events = append(events, fmt.Sprintf("%s (%s)", alert.Event, alert.Severity))
+	}
+	return fmt.Sprintf("%s: %s", r.Location, strings.Join(events, "; "))
+}
+
+type NWSAlert struct {
+	ID          string `json:"id"`
+	AreaDesc    string `json:"areaDesc"`
+	Sent        string `json:"sent"`
+	Effective   string `json:"effective"`
+	Onset       string `json:"onset"`
+	Expires     string `json:"expires"`
+	Ends        string `json:"ends"`
+	Status      string `json:"status"`
+	MessageType string `json:"messageType"`
+	Severity    string `json:"severity"`
+	Certainty   string `json:"certainty"`
+	Urgency     string `json:"urgency"`
+	Event       string `json:"event"`
+	SenderName  string `json:"senderName"`
+	Headline    string `json:"headline"`
+	Description string `json:"description"`
+	Instruction string `json:"instruction"`
+}
+
+func NewWeatherAlertsTool() *WeatherAlertsTool {
+	return &WeatherAlertsTool{
+		Tool:    weatherAlertsTool(),
+		Handler: weatherAlertsToolHandler,
+	}
+}
+
+func weatherAlertsTool() mcp.Tool {
+	return mcp.NewTool("get_weather_alerts",
+		mcp.WithDescription("Get active weather watches, warnings and advisories from the US National Weather Service for a location. Uses client's IP location by default, or accepts a custom location parameter (city name or 'lat,lon' coordinates)"),
+		readOnlyTool("Weather Alerts", true),
+		mcp.WithString("location",
+			mcp.Description("US location to get alerts for (optional). Can be city name (e.g., 'Miami,FL,US'), coordinates (e.g., '40.7128,-74.0060') or a saved location (e.g., '@home'). Uses client IP location if not provided."),
+		),
+		withFormat(),
+		mcp.WithOutputSchema[AlertsReport](),
+	)
+}
+
+func weatherAlertsToolHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	format, err := parseFormat(request)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	resolved, locationName, err := resolveNWSLocation(ctx, request)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	var alerts NWSAlertsData
+	alertsURL := fmt.Sprintf("%s/alerts/active?point=%.4f,%.4f", nwsBaseURL, resolved.Lat, resolved.Lon)
+	if err := fetchNWSData(ctx, alertsURL, &alerts); err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	result := FormatAlertsAsMarkdown(alerts, locationName)
+	return renderResult(format, result, NewAlertsReport(alerts, locationName, resolved)), nil
+}
+
+// handleNWSWeatherRequest reports the latest observation from the station nearest to the location
+func handleNWSWeatherRequest(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	format, err := parseFormat(request)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	resolved, locationName, err := resolveNWSLocation(ctx, request)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	units, err := resolveNWSUnits(request, resolved)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	lang, err := resolveLanguage(ctx, request, resolved)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	point, err := fetchNWSPoint(ctx, resolved.Lat, resolved.Lon)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	var stations NWSStationsData
+	if err := fetchNWSData(ctx, nwsGridpointURL(point, "stations"), &stations); err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	if len(stations.Features) == 0 {
+		return mcp.NewToolResultError("no NWS observation stations found near this location"), nil
+	}
+
+	station := stations.Features[0].Properties
+	var observation NWSObservationData
+	observationURL := fmt.Sprintf("%s/stations/%s/observations/latest", nwsBaseURL, station.StationIdentifier)
+	if err := fetchNWSData(ctx, observationURL, &observation); err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	if observation.Properties.StationName == "" {
+		observation.Properties.StationName = fmt.Sprintf("%s (%s)", station.Name, station.StationIdentifier)
+	}
+
+	result := FormatNWSObservationAsMarkdown(point, observation, locationName, units, lang)
+	return renderResult(format, result, NewNWSWeatherReport(point, observation, locationName, units)), nil
+}
+
+// handleNWSForecastRequest resolves the location to an NWS gridpoint and returns its forecast periods
+func handleNWSForecastRequest(ctx context.Context, request mcp.CallToolRequest, opts ForecastOptions) (*mcp.CallToolResult, error) {
+	format, err := parseFormat(request)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	resolved, locationName, err := resolveNWSLocation(ctx, request)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	units, err := resolveNWSUnits(request, resolved)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	lang, err := resolveLanguage(ctx, request, resolved)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	point, err := fetchNWSPoint(ctx, resolved.Lat, resolved.Lon)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	// NWS forecast text uses mph for "us" units and km/h for "si"
+	forecastUnits := "si"
+	if getUnitSystem(units).WindSpeed == "mph" {
+		forecastUnits = "us"
+	}
+
+	var forecast NWSForecastData
+	if err := fetchNWSData(ctx, nwsGridpointURL(point, "forecast")+"?units="+forecastUnits, &forecast); err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	// NWS periods are already day/night parts, so only the start and days options apply
+	forecast.Properties.Periods = filterNWSPeriods(forecast.Properties.Periods, opts)
+
+	result := FormatNWSForecastAsMarkdown(point, forecast, locationName, units, lang)
+	return renderResult(format, result, NewNWSForecastReport(point, forecast, locationName, units)), nil
+}
+
+// resolveNWSLocation resolves the location argument (or client IP) and rejects places outside the US
+func resolveNWSLocation(ctx context.Context, request mcp.CallToolRequest) (*ResolvedLocation, string, error) {
+	resolved, err := resolveLocationInteractively(ctx, request.GetString("location", ""))
+	if err != nil {
+		return nil, "", err
+	}
+	if resolved.Country != "" && resolved.Country != "US" {
+		return nil, "", fmt.Errorf("the NWS provider only covers the United States, %s is in %s", resolved.Name, resolved.Country)
+	}
+
+	locationName := resolved.Query
+	if locationName == "" {
+		locationName = resolved.Name
+	}
+	return resolved, locationName, nil
+}
+
+// resolveNWSUnits behaves like resolveUnits but defaults to imperial, since NWS only covers the US
+func resolveNWSUnits(request mcp.CallToolRequest, resolved *ResolvedLocation) (string, error) {
+	if request.GetString("units", "") == "" && resolved.Country == "" {
+		return unitsImperial, nil
+	}
+	return resolveUnits(request, resolved)
+}
+
+// fetchNWSPoint resolves coordinates to the forecast office gridpoint that covers them
+func fetchNWSPoint(ctx context.Context, lat, lon float64) (NWSPointData, error) {
+	var point NWSPointData
+	pointURL := fmt.Sprintf("%s/points/%.4f,%.4f", nwsBaseURL, lat, lon)
+	if err := fetchNWSData(ctx, pointURL, &point); err != nil {
+		return point, err
+	}
+	if point.Properties.GridID == "" {
+		return point, fmt.Errorf("location %.4f,%.4f is not covered by the National Weather Service", lat, lon)
+	}
+	return point, nil
+}
+
+func nwsGridpointURL(point NWSPointData, endpoint string) string {
+	return fmt.Sprintf("%s/gridpoints/%s/%d,%d/%s", nwsBaseURL, point.Properties.GridID, point.Properties.GridX, point.Properties.GridY, endpoint)
+}
+
+// fetchNWSData performs a GET against api.weather.gov and decodes the GeoJSON response into target
+func fetchNWSData(ctx context.Context, url string, target any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create NWS request: %v", err)
+	}
+	req.Header.Set("User-Agent", nwsUserAgent)
+	req.Header.Set("Accept", "application/geo+json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to fetch NWS data: %v", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read NWS response: %v", err)
+	}
+
+	if resp.StatusCode == http.StatusNotFound {
+		return fmt.Errorf("location is not covered by the National Weather Service (US only)")
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("NWS API error (status %d): %s", resp.StatusCode, string(body))
+	}
+
+	if err := json.Unmarshal(body, target); err != nil {
+		return fmt.Errorf("failed to parse NWS response: %v", err)
+	}
+	return nil
+}
+
+func nwsPointName(point NWSPointData) string {
+	relative := point.Properties.RelativeLocation.Properties
+	if relative.City == "" {
+		return fmt.Sprintf("%s %d,%d", point.Properties.GridID, point.Properties.GridX, point.Properties.GridY)
+	}
+	return fmt.Sprintf("%s, %s", relative.City, relative.State)
+}
+
+func writeNWSLocation(builder *strings.Builder, point NWSPointData, l *Localizer) {
+	builder.WriteString(fmt.Sprintf("\n## %s\n", l.T("Location")))
+	builder.WriteString(fmt.Sprintf("- **%s:** %s\n", l.T("City"), nwsPointName(point)))
+	if coords := point.Geometry.Coordinates; len(coords) == 2 {
+		builder.WriteString(fmt.Sprintf("- **%s:** %.4f, %.4f\n", l.T("Coordinates"), coords[1], coords[0]))
+	}
+	builder.WriteString(fmt.Sprintf("- **%s:** %s (%s)\n", l.T("Forecast Office"), point.Properties.GridID, l.T("grid %d,%d", point.Properties.GridX, point.Properties.GridY)))
+	builder.WriteString(fmt.Sprintf("- **%s:** National Weather Service\n", l.T("Source")))
+}
+
+// filterNWSPeriods keeps the periods that start within opts.Days local days of opts.Start
+func filterNWSPeriods(periods []NWSForecastPeriod, opts ForecastOptions) []NWSForecastPeriod {
+	if len(periods) == 0 {
+		return periods
+	}
+	first, err := time.Parse(time.RFC3339, periods[0].StartTime)
+	if err != nil {
+		return periods
+	}
+	start, _ := forecastStart(opts.Start, first)
+
+	var filtered []NWSForecastPeriod
+	dayCount := 0
+	lastDay := ""
+	for _, period := range periods {
+		startTime, err := time.Parse(time.RFC3339, period.StartTime)
+		if err != nil || startTime.Before(start) {
+			continue
+		}
+		if day := startTime.Format(time.DateOnly); day != lastDay {
+			if dayCount == opts.Days {
+				break
+			}
+			dayCount++
+			lastDay = day
+		}
+		filtered = append(filtered, period)
+	}
+	return filtered
+}
+
+// FormatNWSForecastAsMarkdown renders NWS forecast periods. Period names and forecast text come
+// from the NWS in English whatever the language.
+func FormatNWSForecastAsMarkdown(point NWSPointData, forecast NWSForecastData, originalLocation string, units string, lang string) string {
+	var builder strings.Builder
+	l := newLocalizer(lang)
+	system := l.Units(units)
+	name := nwsPointName(point)
+
+	builder.WriteString(fmt.Sprintf("# %s: %s\n\n", l.T("Weather Forecast"), name))
+
+	if originalLocation != "" && originalLocation != name {
+		builder.WriteString(fmt.Sprintf("*%s: %s*\n\n", l.T("Requested location"), originalLocation))
+	}
+
+	builder.WriteString(fmt.Sprintf("## %s\n\n", l.T("Forecast Periods")))
+	if len(forecast.Properties.Periods) == 0 {
+		builder.WriteString(l.T("No forecast periods available for the requested range.") + "\n")
+	}
+	for _, period := range forecast.Properties.Periods {
+		windStr := ""
+		if period.WindSpeed != "" {
+			windStr = ", " + l.T("wind %s", l.Compass(period.WindDirection)+" "+period.WindSpeed)
+		}
+
+		popStr := ""
+		if pop := period.ProbabilityOfPrecipitation.Value; pop != nil && *pop > 0 {
+			popStr = fmt.Sprintf(" (%s)", l.T("%.0f%% chance precipitation", *pop))
+		}
+
+		// Periods report whole degrees in °F or °C depending on the units the forecast was requested in
+		celsius := period.Temperature
+		if period.TemperatureUnit == "F" {
+			celsius = fahrenheitToCelsius(period.Temperature)
+		}
+
+		builder.WriteString(fmt.Sprintf("**%s**: %.0f%s, %s%s%s\n", period.Name, system.ConvertTemperature(celsius), system.Temperature, period.ShortForecast, windStr, popStr))
+		if period.DetailedForecast != "" {
+			builder.WriteString(fmt.Sprintf("  %s\n", period.DetailedForecast))
+		}
+	}
+
+	writeNWSLocation(&builder, point, l)
+
+	return builder.String()
+}
+
+func FormatNWSObservationAsMarkdown(point NWSPointData, observation NWSObservationData, originalLocation string, units string, lang string) string {
+	var builder strings.Builder
+	l := newLocalizer(lang)
+	system := l.Units(units)
+	props := observation.Properties
+	name := nwsPointName(point)
+
+	builder.WriteString(fmt.Sprintf("# %s: %s\n\n", l.T("Weather Information"), name))
+
+	if originalLocation != "" && originalLocation != name {
+		builder.WriteString(fmt.Sprintf("*%s: %s*\n\n", l.T("Requested location"), originalLocation))
+	}
+
+	builder.WriteString(fmt.Sprintf("## %s\n", l.T("Current Conditions")))
+	if props.TextDescription != "" {
+		builder.WriteString(fmt.Sprintf("- **%s:** %s\n", l.T("Condition"), props.TextDescription))
+	}
+	if temp := props.Temperature.Value; temp != nil {
+		builder.WriteString(fmt.Sprintf("- **%s:** %s\n", l.T("Temperature"), system.FormatTemperature(*temp)))
+	}
+	if humidity := props.RelativeHumidity.Value; humidity != nil {
+		builder.WriteString(fmt.Sprintf("- **%s:** %.0f%%\n", l.T("Humidity"), *humidity))
+	}
+	if pressure := props.BarometricPressure.Value; pressure != nil {
+		builder.WriteString(fmt.Sprintf("- **%s:** %s\n", l.T("Pressure"), system.FormatPressure(*pressure/100)))
+	}
+
+	builder.WriteString(fmt.Sprintf("\n## %s\n", l.T("Details")))
+	if speed := props.WindSpeed.Value; speed != nil && *speed > 0 {
+		degrees := 0
+		if dir := props.WindDirection.Value; dir != nil {
+			degrees = int(*dir)
+		}
+		wind := fmt.Sprintf("%s %s (%d°)", system.FormatWindSpeed(kmhToMetersPerSecond(*speed)), l.Compass(getWindDirection(degrees)), degrees)
+		if gust := props.WindGust.Value; gust != nil && *gust > *speed {
+			wind += ", " + l.T("gusts %s", system.FormatWindSpeed(kmhToMetersPerSecond(*gust)))
+		}
+		builder.WriteString(fmt.Sprintf("- **%s:** %s\n", l.T("Wind"), wind))
+	}
+	if visibility := props.Visibility.Value; visibility != nil && *visibility > 0 {
+		builder.WriteString(fmt.Sprintf("- **%s:** %s\n", l.T("Visibility"), system.FormatDistance(*visibility)))
+	}
+	if props.StationName != "" {
+		builder.WriteString(fmt.Sprintf("- **%s:** %s\n", l.T("Station"), props.StationName))
+	}
+	if props.Timestamp != "" {
+		builder.WriteString(fmt.Sprintf("- **%s:** %s\n", l.T("Observed"), formatNWSTime(props.Timestamp, point.Properties.TimeZone, l)))
+	}
+
+	if metrics, ok := nwsDerivedMetrics(props); ok {
+		writeDerivedMetrics(&builder, metrics, system, l)
+	}
+
+	writeNWSLocation(&builder, point, l)
+
+	return builder.String()
+}
+
+// nwsDerivedMetrics computes comfort metrics from an observation, which needs at least temperature
+// and humidity. A missing wind speed counts as calm.
+func nwsDerivedMetrics(props NWSObservationProperties) (derivedMetrics, bool) {
+	temp, humidity := props.Temperature.Value, props.RelativeHumidity.Value
+	if temp == nil || humidity == nil {
+		return derivedMetrics{}, false
+	}
+
+	wind := 0.0
+	if speed := props.WindSpeed.Value; speed != nil {
+		wind = kmhToMetersPerSecond(*speed)
+	}
+	return deriveMetrics(*temp, *humidity, wind), true
+}
+
+func NewAlertsReport(alerts NWSAlertsData, locationName string, resolved *ResolvedLocation) AlertsReport {
+	report := AlertsReport{
+		Location: locationName,
+		Lat:      resolved.Lat,
+		Lon:      resolved.Lon,
+		Alerts:   make([]NWSAlert, 0, len(alerts.Features)),
+	}
+	for _, feature := range alerts.Features {
+		report.Alerts = append(report.Alerts, feature.Properties)
+	}
+	report.Count = len(report.Alerts)
+	return report
+}
+
+func FormatAlertsAsMarkdown(alerts NWSAlertsData, locationName string) string {
+	var builder strings.Builder
+
+	builder.WriteString(fmt.Sprintf("# Active Weather Alerts: %s\n\n", locationName))
+
+	if len(alerts.Features) == 0 {
+		builder.WriteString("No active watches, warnings or advisories.\n")
+		return builder.String()
+	}
+
+	builder.WriteString(fmt.Sprintf("*%d active alert(s) from the National Weather Service*\n", len(alerts.Features)))
+
+	// Alert text comes from the NWS in English, so its dates are formatted in English too
+	l := newLocalizer(langEnglish)
+
+	for _, feature := range alerts.Features {
+		alert := feature.Properties
+
+		builder.WriteString(fmt.Sprintf("\n## %s\n", alert.Event))
+		if alert.Headline != "" {
+			builder.WriteString(fmt.Sprintf("*%s*\n\n", alert.Headline))
+		}
+		builder.WriteString(fmt.Sprintf("- **Severity:** %s\n", alert.Severity))
+		builder.WriteString(fmt.Sprintf("- **Urgency:** %s\n", alert.Urgency))
+		builder.WriteString(fmt.Sprintf("- **Certainty:** %s\n", alert.Certainty))
+		builder.WriteString(fmt.Sprintf("- **Area:** %s\n", alert.AreaDesc))
+		builder.WriteString(fmt.Sprintf("- **Effective:** %s\n", formatNWSTime(alert.Effective, "", l)))
+		builder.WriteString(fmt.Sprintf("- **Expires:** %s\n", formatNWSTime(alert.Expires, "", l)))
+		if alert.Instruction != "" {
+			builder.WriteString(fmt.Sprintf("- **Instructions:** %s\n", alert.Instruction))
+		}
+	}
+
+	return builder.String()
+}
+
+// formatNWSTime renders an RFC 3339 timestamp in the given IANA zone, or in its own offset when the
+// zone is empty or unknown. The input is returned unchanged if it can't be parsed.
+func formatNWSTime(value string, timeZone string, l *Localizer) string {
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return value
+	}
+	if timeZone != "" {
+		if loc, err := time.LoadLocation(timeZone); err == nil {
+			t = t.In(loc)
+		}
+	}
+	return l.DateTime(t) + t.Format(" -07:00")
+}
@@ -0,0 +1,240 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// newNWSTestServer serves recorded api.weather.gov responses and points nwsBaseURL at it
+func newNWSTestServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	routes := map[string]string{
+		"/points/40.7128,-74.0060":           "nws_points_response.json",
+		"/gridpoints/OKX/33,35/forecast":     "nws_forecast_response.json",
+		"/gridpoints/OKX/33,35/stations":     "nws_stations_response.json",
+		"/stations/KNYC/observations/latest": "nws_observation_response.json",
+		"/alerts/active":                     "nws_alerts_response.json",
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("User-Agent") == "" {
+			t.Errorf("Expected User-Agent header on NWS request to %s", r.URL.Path)
+		}
+
+		fixture, ok := routes[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/geo+json")
+		w.Write(loadFixture(t, fixture))
+	}))
+
+	originalBaseURL := nwsBaseURL
+	nwsBaseURL = server.URL
+	t.Cleanup(func() {
+		nwsBaseURL = originalBaseURL
+		server.Close()
+	})
+
+	return server
+}
+
+func callToolText(t *testing.T, handler func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error), args map[string]any) (string, bool) {
+	t.Helper()
+
+	ctx := context.WithValue(context.Background(), ClientIPKey, "8.8.8.8")
+	request := mcp.CallToolRequest{
+		Params: mcp.CallToolParams{
+			Arguments: args,
+		},
+	}
+
+	result, err := handler(ctx, request)
+	if err != nil {
+		t.Fatalf("Handler returned error: %v", err)
+	}
+
+	textContent, ok := mcp.AsTextContent(result.Content[0])
+	if !ok {
+		t.Fatalf("Expected text content in result")
+	}
+
+	return textContent.Text, result.IsError
+}
+
+func TestNWSForecast_Fixtures(t *testing.T) {
+	newNWSTestServer(t)
+
+	forecastTool := NewWeatherForecastTool()
+	content, isError := callToolText(t, forecastTool.Handler, map[string]any{
+		"location": "40.7128,-74.0060",
+		"provider": "nws",
+	})
+	if isError {
+		t.Fatalf("Expected success, got error: %s", content)
+	}
+
+	expected := []string{
+		"# Weather Forecast: New York, NY",
+		"*Requested location: 40.7128,-74.0060*",
+		"## Forecast Periods",
+		"**Today**: 78°F, Sunny, wind NW 8 to 12 mph",
+		"**Wednesday Night**: 67°F, Chance Showers And Thunderstorms, wind S 6 mph (40% chance precipitation)",
+		"A slight chance of showers and thunderstorms after 2pm.",
+		"**Forecast Office:** OKX (grid 33,35)",
+		"**Coordinates:** 40.7128, -74.0060",
+		"**Source:** National Weather Service",
+	}
+	for _, want := range expected {
+		if !strings.Contains(content, want) {
+			t.Errorf("Expected to find '%s' in NWS forecast output:\n%s", want, content)
+		}
+	}
+
+	// Periods without a precipitation value must not report a chance
+	if strings.Contains(content, "**Today**: 78°F, Sunny, wind NW 8 to 12 mph (") {
+		t.Error("Null precipitation probability should be omitted")
+	}
+}
+
+func TestNWSWeather_Observation(t *testing.T) {
+	newNWSTestServer(t)
+
+	weatherTool := NewWeatherTool()
+	content, isError := callToolText(t, weatherTool.Handler, map[string]any{
+		"location": "40.7128,-74.0060",
+		"provider": "nws",
+	})
+	if isError {
+		t.Fatalf("Expected success, got error: %s", content)
+	}
+
+	expected := []string{
+		"# Weather Information: New York, NY",
+		"**Condition:** Partly Cloudy",
+		"**Temperature:** 72.0°F",
+		"**Humidity:** 55%",
+		"**Pressure:** 30.10 inHg",
+		"**Wind:** 9.2 mph NW (320°)",
+		"**Visibility:** 10.0 miles",
+		"**Station:** New York City, Central Park",
+		"**Observed:** Tue Sep 2 9:51 AM -04:00",
+	}
+	for _, want := range expected {
+		if !strings.Contains(content, want) {
+			t.Errorf("Expected to find '%s' in NWS observation output:\n%s", want, content)
+		}
+	}
+}
+
+func TestNWS_RejectsNonUSLocation(t *testing.T) {
+	os.Unsetenv("OPENWEATHER_API_KEY")
+	newNWSTestServer(t)
+	newOpenWeatherMapTestServer(t)
+
+	forecastTool := NewWeatherForecastTool()
+	content, isError := callToolText(t, forecastTool.Handler, map[string]any{
+		"location": "Valencia",
+		"provider": "nws",
+	})
+	if !isError {
+		t.Fatalf("Expected error for geocoded location outside the US, got: %s", content)
+	}
+	if !strings.Contains(content, "only covers the United States") {
+		t.Errorf("Unexpected error message: %s", content)
+	}
+}
+
+func TestNWS_OutsideCoverage(t *testing.T) {
+	newNWSTestServer(t)
+
+	forecastTool := NewWeatherForecastTool()
+	content, isError := callToolText(t, forecastTool.Handler, map[string]any{
+		"location": "39.4676,-0.3771",
+		"provider": "nws",
+	})
+	if !isError {
+		t.Fatalf("Expected error for location outside the US, got: %s", content)
+	}
+	if !strings.Contains(content, "not covered by the National Weather Service") {
+		t.Errorf("Unexpected error message: %s", content)
+	}
+}
+
+func TestWeatherAlertsTool_Fixtures(t *testing.T) {
+	newNWSTestServer(t)
+
+	alertsTool := NewWeatherAlertsTool()
+	content, isError := callToolText(t, alertsTool.Handler, map[string]any{
+		"location": "40.7128,-74.0060",
+	})
+	if isError {
+		t.Fatalf("Expected success, got error: %s", content)
+	}
+
+	expected := []string{
+		"# Active Weather Alerts: 40.7128,-74.0060",
+		"*2 active alert(s) from the National Weather Service*",
+		"## Heat Advisory",
+		"- **Severity:** Moderate",
+		"- **Urgency:** Expected",
+		"- **Certainty:** Likely",
+		"- **Area:** New York (Manhattan); Bronx; Kings (Brooklyn)",
+		"- **Effective:** Tue Sep 2 9:45 AM -04:00",
+		"- **Expires:** Tue Sep 2 8:00 PM -04:00",
+		"- **Instructions:** Drink plenty of fluids",
+		"## Small Craft Advisory",
+	}
+	for _, want := range expected {
+		if !strings.Contains(content, want) {
+			t.Errorf("Expected to find '%s' in alerts output:\n%s", want, content)
+		}
+	}
+}
+
+func TestFormatAlertsAsMarkdown_NoAlerts(t *testing.T) {
+	var alerts NWSAlertsData
+	if err := json.Unmarshal([]byte(`{"type":"FeatureCollection","features":[]}`), &alerts); err != nil {
+		t.Fatalf("Failed to unmarshal alerts: %v", err)
+	}
+
+	result := FormatAlertsAsMarkdown(alerts, "Denver, United States")
+	if !strings.Contains(result, "# Active Weather Alerts: Denver, United States") {
+		t.Error("Missing alerts header")
+	}
+	if !strings.Contains(result, "No active watches, warnings or advisories.") {
+		t.Error("Missing empty alerts message")
+	}
+}
+
+func TestParseCoordinates(t *testing.T) {
+	testCases := []struct {
+		location string
+		lat      float64
+		lon      float64
+		ok       bool
+	}{
+		{"40.7128,-74.0060", 40.7128, -74.0060, true},
+		{" 39.4676 , -0.3771 ", 39.4676, -0.3771, true},
+		{"London,UK", 0, 0, false},
+		{"Springfield", 0, 0, false},
+		{"1,2,3", 0, 0, false},
+	}
+
+	for _, tc := range testCases {
+		lat, lon, ok := parseCoordinates(tc.location)
+		if ok != tc.ok || lat != tc.lat || lon != tc.lon {
+			t.Errorf("parseCoordinates(%q) = %v, %v, %v, expected %v, %v, %v", tc.location, lat, lon, ok, tc.lat, tc.lon, tc.ok)
+		}
+	}
+}
@@ -0,0 +1,362 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Riddlerrr/lazymcp/storage"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// PrincipalKey holds the authenticated user a request belongs to, as reported by a trusted proxy.
+// Preferences of a principal outlive their sessions.
+const PrincipalKey contextKey = "principal"
+
+const (
+	setPreferencesToolName = "set_preferences"
+
+	// sessionPreferencesTTL forgets the preferences of sessions idle this long, as clients do not
+	// always terminate their sessions
+	sessionPreferencesTTL     = 24 * time.Hour
+	sessionPreferencesRefresh = time.Hour
+)
+
+// Preferences are the defaults applied to the arguments of every tool call in a session
+type Preferences struct {
+	Location string `json:"location,omitempty"`
+	Units    string `json:"units,omitempty"`
+	Lang     string `json:"lang,omitempty"`
+	Format   string `json:"format,omitempty"`
+}
+
+// preferenceNames are the tool arguments preferences fill in
+var preferenceNames = []string{"location", "units", "lang", "format"}
+
+// arguments returns the preferences by the tool argument they fill in
+func (p Preferences) arguments() map[string]string {
+	return map[string]string{
+		"location": p.Location,
+		"units":    p.Units,
+		"lang":     p.Lang,
+		"format":   p.Format,
+	}
+}
+
+// PreferencesResult is the structured content of set_preferences
+type PreferencesResult struct {
+	Preferences
+	Persistent bool `json:"persistent"`
+}
+
+// CompactSummary lists the preferences that are set on one line
+func (r PreferencesResult) CompactSummary() string {
+	var parts []string
+	for _, name := range preferenceNames {
+		if value := r.arguments()[name]; value != "" {
+			parts = append(parts, fmt.Sprintf("%s %s", name, value))
+		}
+	}
+	if len(parts) == 0 {
+		return "No preferences set"
+	}
+	return "Preferences: " + strings.Join(parts, ", ")
+}
+
+// sessionPreferences are the preferences of one session. They expire in the store after
+// sessionPreferencesTTL without use.
+type sessionPreferences struct {
+	Preferences Preferences `json:"preferences"`
+	LastUsed    time.Time   `json:"lastUsed"`
+}
+
+// getPreferences returns the preferences of a session, falling back to those saved for the
+// principal
+func getPreferences(sessionID string, principal string) (Preferences, error) {
+	var prefs Preferences
+	var session sessionPreferences
+	found := false
+	err := state.View(func(tx storage.Tx) error {
+		if sessionID != "" {
+			err := tx.Get(sessionPreferencesBucket, sessionID, &session)
+			if err == nil {
+				found = true
+				return nil
+			}
+			if err != storage.ErrNotFound {
+				return err
+			}
+		}
+		if principal != "" {
+			if err := tx.Get(principalPreferencesBucket, principal, &prefs); err != nil && err != storage.ErrNotFound {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil || !found {
+		return prefs, err
+	}
+
+	// Extend the session's lifetime, at most once per sessionPreferencesRefresh so that reading
+	// preferences does not write on every tool call
+	if time.Since(session.LastUsed) > sessionPreferencesRefresh {
+		session.LastUsed = time.Now()
+		err = state.Update(func(tx storage.Tx) error {
+			var current sessionPreferences
+			if err := tx.Get(sessionPreferencesBucket, sessionID, &current); err != nil {
+				return nil // removed in the meantime
+			}
+			current.LastUsed = session.LastUsed
+			return tx.Put(sessionPreferencesBucket, sessionID, current, sessionPreferencesTTL)
+		})
+	}
+	return session.Preferences, err
+}
+
+// setPreferences stores a session's preferences and, when the principal is known, saves them for
+// the principal. It reports whether they were saved for the principal.
+func setPreferences(sessionID string, principal string, prefs Preferences) (bool, error) {
+	err := state.Update(func(tx storage.Tx) error {
+		if sessionID != "" {
+			session := sessionPreferences{Preferences: prefs, LastUsed: time.Now()}
+			if err := tx.Put(sessionPreferencesBucket, sessionID, session, sessionPreferencesTTL); err != nil {
+				return err
+			}
+		}
+		if principal == "" {
+			return nil
+		}
+		if prefs == (Preferences{}) {
+			return tx.Delete(principalPreferencesBucket, principal)
+		}
+		return tx.Put(principalPreferencesBucket, principal, prefs, 0)
+	})
+	return principal != "" && err == nil, err
+}
+
+// removeSessionPreferences forgets the preferences of a terminated session
+func removeSessionPreferences(sessionID string) {
+	err := state.Update(func(tx storage.Tx) error {
+		return tx.Delete(sessionPreferencesBucket, sessionID)
+	})
+	if err != nil {
+		log.Printf("Failed to remove the preferences of session %s: %v", sessionID, err)
+	}
+}
+
+// requestIdentity returns the session and principal a tool call belongs to
+func requestIdentity(ctx context.Context) (string, string) {
+	var sessionID string
+	if session := server.ClientSessionFromContext(ctx); session != nil {
+		sessionID = session.SessionID()
+	}
+	principal, _ := ctx.Value(PrincipalKey).(string)
+	return sessionID, principal
+}
+
+// ApplyPreferences fills the arguments a call to a read-only tool leaves out with the session's
+// preferences, for the arguments the tool declares. It is registered as a before-call-tool hook,
+// so those tools see the preferences as if the client had passed them. Tools that change state,
+// such as save_location, only get the arguments the client passed, so a default is never saved.
+func (t *ToolSet) ApplyPreferences(ctx context.Context, id any, request *mcp.CallToolRequest) {
+	tool, ok := t.tool(request.Params.Name)
+	if !ok || tool.Annotations.ReadOnlyHint == nil || !*tool.Annotations.ReadOnlyHint {
+		return
+	}
+	prefs, err := getPreferences(requestIdentity(ctx))
+	if err != nil {
+		log.Printf("Failed to read preferences: %v", err)
+	}
+	if prefs == (Preferences{}) {
+		return
+	}
+
+	arguments, _ := request.Params.Arguments.(map[string]any)
+	if arguments == nil {
+		arguments = make(map[string]any)
+	}
+	for name, value := range prefs.arguments() {
+		if _, declared := tool.InputSchema.Properties[name]; !declared || value == "" {
+			continue
+		}
+		if given, ok := arguments[name]; ok && given != "" {
+			continue
+		}
+		arguments[name] = value
+	}
+	request.Params.Arguments = arguments
+}
+
+// tool looks up a registered tool by name
+func (t *ToolSet) tool(name string) (mcp.Tool, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for _, tool := range t.tools {
+		if tool.Tool.Name == name {
+			return tool.Tool, true
+		}
+	}
+	return mcp.Tool{}, false
+}
+
+type SetPreferencesTool struct {
+	Tool    mcp.Tool
+	Handler func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error)
+}
+
+func NewSetPreferencesTool() *SetPreferencesTool {
+	return &SetPreferencesTool{
+		Tool:    setPreferencesTool(),
+		Handler: setPreferencesToolHandler,
+	}
+}
+
+func setPreferencesTool() mcp.Tool {
+	return mcp.NewTool(setPreferencesToolName,
+		mcp.WithDescription("Set default location, units, language and output format for this session. Every read-only tool uses them when the argument is left out. Arguments that are not passed keep their current value, and calling the tool without arguments shows the current preferences."),
+		writeTool("Session Preferences", false, true, false),
+		mcp.WithString("location",
+			mcp.Description("Default location: city name (e.g., 'London' or 'New York,US') or coordinates (e.g., '40.7128,-74.0060')"),
+		),
+		withUnits("Becomes the default of every tool that takes units."),
+		mcp.WithString("lang",
+			mcp.Description("Default output language (optional). 'en', 'es', 'de' or 'fr'."),
+			mcp.Enum(supportedLanguages...),
+		),
+		mcp.WithString("format",
+			mcp.Description("Default output format (optional). 'markdown', 'plain', 'compact' or 'json'."),
+			mcp.Enum(formatMarkdown, formatPlain, formatCompact, formatJSON),
+		),
+		mcp.WithArray("clear",
+			mcp.Description("Preferences to clear (optional), applied before the other arguments"),
+			mcp.WithStringEnumItems(preferenceNames),
+		),
+		mcp.WithBoolean("reset",
+			mcp.Description("Clear every preference before applying the other arguments (optional)"),
+		),
+		mcp.WithOutputSchema[PreferencesResult](),
+	)
+}
+
+func setPreferencesToolHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	sessionID, principal := requestIdentity(ctx)
+	if sessionID == "" && principal == "" {
+		return mcp.NewToolResultError(fmt.Sprintf("Preferences need a session: send the %s header returned by initialize", server.HeaderKeySessionID)), nil
+	}
+
+	prefs, err := getPreferences(sessionID, principal)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to read preferences: %v", err)), nil
+	}
+	if request.GetBool("reset", false) {
+		prefs = Preferences{}
+	}
+
+	fields := []struct {
+		name  string
+		value *string
+	}{
+		{"location", &prefs.Location},
+		{"units", &prefs.Units},
+		{"lang", &prefs.Lang},
+		{"format", &prefs.Format},
+	}
+	for _, name := range request.GetStringSlice("clear", nil) {
+		if !slices.Contains(preferenceNames, name) {
+			return mcp.NewToolResultError(fmt.Sprintf("invalid preference %q to clear: must be one of %s", name, strings.Join(preferenceNames, ", "))), nil
+		}
+		for _, field := range fields {
+			if field.name == name {
+				*field.value = ""
+			}
+		}
+	}
+
+	arguments := request.GetArguments()
+	for _, field := range fields {
+		argument, ok := arguments[field.name]
+		if !ok {
+			continue
+		}
+		value, _ := argument.(string)
+		value = strings.TrimSpace(value)
+		if field.name != "location" {
+			value = strings.ToLower(value)
+		}
+		if err := validatePreference(field.name, value); err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		*field.value = value
+	}
+
+	persistent, err := setPreferences(sessionID, principal, prefs)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to save preferences: %v", err)), nil
+	}
+
+	format := prefs.Format
+	if format == "" {
+		format = formatMarkdown
+	}
+	return renderResult(format, FormatPreferencesAsMarkdown(prefs, persistent), PreferencesResult{Preferences: prefs, Persistent: persistent}), nil
+}
+
+// validatePreference checks a preference value; an empty value clears the preference
+func validatePreference(name string, value string) error {
+	if value == "" {
+		return nil
+	}
+	switch name {
+	case "units":
+		if !isValidUnits(value) {
+			return fmt.Errorf("invalid units %q: must be one of metric, imperial, standard, uk", value)
+		}
+	case "lang":
+		if _, ok := locales[value]; !ok {
+			return fmt.Errorf("invalid lang %q: must be one of %s", value, strings.Join(supportedLanguages, ", "))
+		}
+	case "format":
+		switch value {
+		case formatMarkdown, formatPlain, formatCompact, formatJSON:
+		default:
+			return fmt.Errorf("invalid format %q: must be one of markdown, plain, compact, json", value)
+		}
+	}
+	return nil
+}
+
+// FormatPreferencesAsMarkdown lists the preferences in effect
+func FormatPreferencesAsMarkdown(prefs Preferences, persistent bool) string {
+	var builder strings.Builder
+	builder.WriteString("# Preferences\n\n")
+
+	rows := []struct {
+		label string
+		value string
+	}{
+		{"Location", prefs.Location},
+		{"Units", prefs.Units},
+		{"Language", prefs.Lang},
+		{"Format", prefs.Format},
+	}
+	for _, row := range rows {
+		value := row.value
+		if value == "" {
+			value = "not set"
+		}
+		builder.WriteString(fmt.Sprintf("- **%s:** %s\n", row.label, value))
+	}
+
+	if persistent {
+		builder.WriteString("\n*Saved for your account and applied to your future sessions.*\n")
+	} else {
+		builder.WriteString("\n*Applied to this session only.*\n")
+	}
+	return builder.String()
+}
@@ -0,0 +1,205 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Riddlerrr/lazymcp/storage"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// newPreferencesTestServer registers set_preferences, get_weather and a write tool echoing its
+// location with preferences applied, the way main.go does
+func newPreferencesTestServer(t *testing.T) *server.MCPServer {
+	t.Helper()
+	useMemoryState(t)
+	t.Cleanup(func() { apiKeyState = &apiKeyStatus{} })
+
+	hooks := &server.Hooks{}
+	mcpServer := server.NewMCPServer("test", "1.0.0", server.WithToolCapabilities(false), server.WithHooks(hooks))
+	toolSet := NewToolSet(mcpServer)
+	hooks.AddBeforeCallTool(toolSet.ApplyPreferences)
+
+	setPreferencesTool := NewSetPreferencesTool()
+	toolSet.Add(setPreferencesTool.Tool, setPreferencesTool.Handler)
+	weatherTool := NewWeatherTool()
+	toolSet.Add(weatherTool.Tool, weatherTool.Handler)
+	echoTool := mcp.NewTool("echo_location", writeTool("Echo", false, true, false), mcp.WithString("location"))
+	toolSet.Add(echoTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText(request.GetString("location", "none")), nil
+	})
+	toolSet.Refresh()
+	return mcpServer
+}
+
+// callSessionTool calls a tool through the server as the given session and principal
+func callSessionTool(t *testing.T, mcpServer *server.MCPServer, sessionID string, principal string, name string, arguments string) *mcp.CallToolResult {
+	t.Helper()
+
+	ctx := mcpServer.WithContext(context.Background(), testSession{id: sessionID})
+	if principal != "" {
+		ctx = context.WithValue(ctx, PrincipalKey, principal)
+	}
+	message := fmt.Sprintf(`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":%q,"arguments":%s}}`, name, arguments)
+	response, ok := mcpServer.HandleMessage(ctx, json.RawMessage(message)).(mcp.JSONRPCResponse)
+	if !ok {
+		t.Fatalf("Expected a successful response, got %+v", response)
+	}
+	result, ok := response.Result.(mcp.CallToolResult)
+	if !ok {
+		t.Fatalf("Unexpected result type %T", response.Result)
+	}
+	return &result
+}
+
+func TestSetPreferences_AppliedToTools(t *testing.T) {
+	os.Setenv("OPENWEATHER_API_KEY", "test_api_key")
+	defer os.Unsetenv("OPENWEATHER_API_KEY")
+	newOpenWeatherMapTestServer(t)
+	mcpServer := newPreferencesTestServer(t)
+
+	result := callSessionTool(t, mcpServer, "session-a", "", "set_preferences", `{"location":"39.4697,-0.3763","units":"IMPERIAL","format":"compact"}`)
+	if result.IsError {
+		t.Fatalf("Unexpected error: %s", resultText(result))
+	}
+	if text := resultText(result); text != "Preferences: location 39.4697,-0.3763, units imperial, format compact" {
+		t.Errorf("Unexpected summary: %s", text)
+	}
+
+	result = callSessionTool(t, mcpServer, "session-a", "", "get_weather", `{}`)
+	if result.IsError || !strings.HasPrefix(resultText(result), "Valencia") || !strings.Contains(resultText(result), "°F") {
+		t.Errorf("Expected a compact imperial summary for the preferred location, got: %s", resultText(result))
+	}
+
+	// Arguments win over preferences
+	result = callSessionTool(t, mcpServer, "session-a", "", "get_weather", `{"units":"metric","format":"markdown"}`)
+	if !strings.Contains(resultText(result), "# Weather Information: Valencia") || !strings.Contains(resultText(result), "°C") {
+		t.Errorf("Expected metric Markdown, got: %s", resultText(result))
+	}
+
+	// Tools that change state only get the arguments the client passed
+	result = callSessionTool(t, mcpServer, "session-a", "", "echo_location", `{}`)
+	if text := resultText(result); text != "none" {
+		t.Errorf("Expected no default location for a write tool, got: %s", text)
+	}
+
+	// Other sessions are not affected
+	result = callSessionTool(t, mcpServer, "session-b", "", "get_weather", `{"location":"39.4697,-0.3763"}`)
+	if !strings.Contains(resultText(result), "# Weather Information: Valencia") {
+		t.Errorf("Expected the default format in another session, got: %s", resultText(result))
+	}
+
+	// clear removes single preferences, reset clears them all
+	result = callSessionTool(t, mcpServer, "session-a", "", "set_preferences", `{"clear":["format"]}`)
+	if text := resultText(result); !strings.Contains(text, "- **Format:** not set") || !strings.Contains(text, "- **Units:** imperial") {
+		t.Errorf("Expected only the format to be cleared, got: %s", text)
+	}
+	result = callSessionTool(t, mcpServer, "session-a", "", "set_preferences", `{"reset":true,"lang":"es"}`)
+	structured, _ := json.Marshal(result.StructuredContent)
+	if string(structured) != `{"lang":"es","persistent":false}` {
+		t.Errorf("Expected only the language after a reset, got %s", structured)
+	}
+}
+
+func TestSetPreferences_Validation(t *testing.T) {
+	mcpServer := newPreferencesTestServer(t)
+
+	testCases := []struct {
+		arguments string
+		error     string
+	}{
+		{`{"units":"kelvin"}`, `invalid units "kelvin"`},
+		{`{"lang":"xx"}`, `invalid lang "xx"`},
+		{`{"format":"html"}`, `invalid format "html"`},
+		{`{"clear":["color"]}`, `invalid preference "color" to clear`},
+	}
+	for _, tc := range testCases {
+		result := callSessionTool(t, mcpServer, "session-a", "", "set_preferences", tc.arguments)
+		if !result.IsError || !strings.Contains(resultText(result), tc.error) {
+			t.Errorf("Expected %q for %s, got: %s", tc.error, tc.arguments, resultText(result))
+		}
+	}
+
+	// The schema lists the valid values too
+	tool := NewSetPreferencesTool().Tool
+	for _, name := range []string{"units", "lang", "format"} {
+		if property, _ := tool.InputSchema.Properties[name].(map[string]any); property["enum"] == nil {
+			t.Errorf("Expected the %s argument to list its values", name)
+		}
+	}
+
+	result := callSessionTool(t, mcpServer, "", "", "set_preferences", `{"units":"metric"}`)
+	if !result.IsError || !strings.Contains(resultText(result), "Preferences need a session") {
+		t.Errorf("Expected a session error, got: %s", resultText(result))
+	}
+}
+
+func TestSetPreferences_Persistent(t *testing.T) {
+	mcpServer := newPreferencesTestServer(t)
+	path := filepath.Join(t.TempDir(), "state.db")
+	useBoltState(t, path)
+
+	result := callSessionTool(t, mcpServer, "session-a", "alice", "set_preferences", `{"units":"uk"}`)
+	if !strings.Contains(resultText(result), "Saved for your account") {
+		t.Errorf("Expected the preferences to be saved, got: %s", resultText(result))
+	}
+
+	// A later session of the same principal starts with the saved preferences, also after a restart
+	state.Close()
+	useBoltState(t, path)
+	if prefs, err := getPreferences("session-b", "alice"); err != nil || prefs.Units != "uk" {
+		t.Errorf("Expected the saved units in a new session, got %+v, %v", prefs, err)
+	}
+	if prefs, _ := getPreferences("session-c", "bob"); prefs != (Preferences{}) {
+		t.Errorf("Expected no preferences for another principal, got %+v", prefs)
+	}
+
+	// Without a principal, preferences stay with the session
+	result = callSessionTool(t, mcpServer, "session-d", "", "set_preferences", `{"units":"metric"}`)
+	if !strings.Contains(resultText(result), "Applied to this session only") {
+		t.Errorf("Expected session-only preferences, got: %s", resultText(result))
+	}
+}
+
+func TestSessionPreferences_Expiry(t *testing.T) {
+	useMemoryState(t)
+	setPreferences("session-a", "", Preferences{Units: "metric"})
+	setPreferences("session-b", "", Preferences{Units: "imperial"})
+
+	// Reading preferences extends the lifetime of a session that has not been used for a while
+	stale := sessionPreferences{Preferences: Preferences{Units: "metric"}, LastUsed: time.Now().Add(-2 * sessionPreferencesRefresh)}
+	state.Update(func(tx storage.Tx) error {
+		return tx.Put(sessionPreferencesBucket, "session-a", stale, time.Minute)
+	})
+	if prefs, _ := getPreferences("session-a", ""); prefs.Units != "metric" {
+		t.Errorf("Expected the session preferences, got %+v", prefs)
+	}
+	var session sessionPreferences
+	state.View(func(tx storage.Tx) error {
+		return tx.Get(sessionPreferencesBucket, "session-a", &session)
+	})
+	if time.Since(session.LastUsed) > time.Minute {
+		t.Errorf("Expected the session to be refreshed, last used %v", session.LastUsed)
+	}
+
+	// Idle sessions expire with their time to live
+	state.Update(func(tx storage.Tx) error {
+		return tx.Put(sessionPreferencesBucket, "session-a", stale, time.Nanosecond)
+	})
+	time.Sleep(time.Millisecond)
+	if prefs, _ := getPreferences("session-a", ""); prefs != (Preferences{}) {
+		t.Errorf("Expected idle session preferences to expire, got %+v", prefs)
+	}
+
+	removeSessionPreferences("session-b")
+	if prefs, _ := getPreferences("session-b", ""); prefs != (Preferences{}) {
+		t.Errorf("Expected the preferences of a terminated session to be gone, got %+v", prefs)
+	}
+}
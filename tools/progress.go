@@ -0,0 +1,48 @@
+package tools
+
+import (
+	"context"
+	"log"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const methodNotificationProgress = "notifications/progress"
+
+// progressReporter sends notifications/progress for a tool call whose client asked for them by
+// passing a progress token. Without a token, or outside an MCP server, reports are dropped.
+type progressReporter struct {
+	ctx   context.Context
+	token mcp.ProgressToken
+	total int
+}
+
+func newProgressReporter(ctx context.Context, request mcp.CallToolRequest, total int) *progressReporter {
+	var token mcp.ProgressToken
+	if request.Params.Meta != nil {
+		token = request.Params.Meta.ProgressToken
+	}
+	return &progressReporter{ctx: ctx, token: token, total: total}
+}
+
+// report announces that step of total steps are done and what happens next
+func (p *progressReporter) report(step int, message string) {
+	if p.token == nil {
+		return
+	}
+	mcpServer := server.ServerFromContext(p.ctx)
+	if mcpServer == nil {
+		return
+	}
+
+	err := mcpServer.SendNotificationToClient(p.ctx, methodNotificationProgress, map[string]any{
+		"progressToken": p.token,
+		"progress":      step,
+		"total":         p.total,
+		"message":       message,
+	})
+	if err != nil {
+		log.Printf("Failed to send progress notification: %v", err)
+	}
+}
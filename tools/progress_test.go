@@ -0,0 +1,72 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// notificationSession is a client session that keeps the notifications sent to it
+type notificationSession struct {
+	notifications chan mcp.JSONRPCNotification
+}
+
+func (s *notificationSession) Initialize()       {}
+func (s *notificationSession) Initialized() bool { return true }
+func (s *notificationSession) SessionID() string { return "progress-session" }
+func (s *notificationSession) NotificationChannel() chan<- mcp.JSONRPCNotification {
+	return s.notifications
+}
+
+func TestHandleWeatherRequest_Progress(t *testing.T) {
+	os.Setenv("OPENWEATHER_API_KEY", "test_api_key")
+	defer os.Unsetenv("OPENWEATHER_API_KEY")
+	newOpenWeatherMapTestServer(t)
+
+	mcpServer := server.NewMCPServer("test", "1.0.0", server.WithToolCapabilities(false))
+	weatherTool := NewWeatherTool()
+	mcpServer.AddTool(weatherTool.Tool, weatherTool.Handler)
+
+	session := &notificationSession{notifications: make(chan mcp.JSONRPCNotification, 10)}
+	if err := mcpServer.RegisterSession(context.Background(), session); err != nil {
+		t.Fatalf("Failed to register session: %v", err)
+	}
+	ctx := mcpServer.WithContext(context.Background(), session)
+
+	message := `{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"get_weather","arguments":{"location":"39.4697,-0.3763"},"_meta":{"progressToken":"weather-1"}}}`
+	if response, ok := mcpServer.HandleMessage(ctx, json.RawMessage(message)).(mcp.JSONRPCResponse); !ok {
+		t.Fatalf("Expected a successful response, got %+v", response)
+	}
+	close(session.notifications)
+
+	var steps []string
+	for notification := range session.notifications {
+		params := notification.Params.AdditionalFields
+		if notification.Method != methodNotificationProgress || params["progressToken"] != "weather-1" || params["total"] != weatherRequestSteps {
+			t.Errorf("Unexpected notification: %+v", notification)
+		}
+		steps = append(steps, fmt.Sprintf("%v %v", params["progress"], params["message"]))
+	}
+
+	expected := []string{"0 Resolving location", "1 Fetching weather data for 39.4697,-0.3763", "2 Formatting the result", "3 Done"}
+	if strings.Join(steps, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("Expected progress %v, got %v", expected, steps)
+	}
+}
+
+func TestProgressReporter_NoToken(t *testing.T) {
+	session := &notificationSession{notifications: make(chan mcp.JSONRPCNotification, 1)}
+	mcpServer := server.NewMCPServer("test", "1.0.0")
+	ctx := mcpServer.WithContext(context.Background(), session)
+
+	newProgressReporter(ctx, mcp.CallToolRequest{}, 3).report(1, "Working")
+	if len(session.notifications) != 0 {
+		t.Error("Expected no notifications without a progress token")
+	}
+}
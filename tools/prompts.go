@@ -0,0 +1,336 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"slices"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+type ActivityPrompt struct {
+	Prompt  mcp.Prompt
+	Handler func(ctx context.Context, request mcp.GetPromptRequest) (*mcp.GetPromptResult, error)
+}
+
+func NewActivityPrompt() *ActivityPrompt {
+	return &ActivityPrompt{
+		Prompt:  activityPrompt(),
+		Handler: activityPromptHandler,
+	}
+}
+
+func activityPrompt() mcp.Prompt {
+	return mcp.NewPrompt("plan_outdoor_activity",
+		mcp.WithPromptDescription("Ask whether and when to do an outdoor activity, such as a bike ride, a run or an event, based on the forecast for the next 5 days"),
+		mcp.WithArgument("activity",
+			mcp.ArgumentDescription("What you want to do, e.g. 'cycling', 'a 10k run' or 'a garden party'"),
+			mcp.RequiredArgument(),
+		),
+		mcp.WithArgument("location",
+			mcp.ArgumentDescription("Where (optional). Uses your IP location if not provided."),
+		),
+		mcp.WithArgument("when",
+			mcp.ArgumentDescription("When you'd like to go (optional), e.g. 'this weekend' or 'tomorrow morning'"),
+		),
+	)
+}
+
+func activityPromptHandler(ctx context.Context, request mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+	activity := strings.TrimSpace(request.Params.Arguments["activity"])
+	if activity == "" {
+		return nil, fmt.Errorf("activity is required")
+	}
+	location := strings.TrimSpace(request.Params.Arguments["location"])
+	when := strings.TrimSpace(request.Params.Arguments["when"])
+
+	var builder strings.Builder
+	builder.WriteString(fmt.Sprintf("I'm thinking about %s", activity))
+	if location != "" {
+		builder.WriteString(fmt.Sprintf(" in %s", location))
+	}
+	if when != "" {
+		builder.WriteString(fmt.Sprintf(", ideally %s", when))
+	}
+	builder.WriteString(". Is the weather suitable, and when is the best time?\n\n")
+
+	builder.WriteString("Call the weather_suitability tool")
+	if location != "" {
+		builder.WriteString(fmt.Sprintf(" with location %q", location))
+	}
+	if profile, ok := activityProfiles[strings.ReplaceAll(strings.ToLower(activity), " ", "_")]; ok {
+		builder.WriteString(fmt.Sprintf(" and the %q activity profile", profile.Name))
+	} else {
+		builder.WriteString(fmt.Sprintf(" with the activity profile closest to this (%s), or 'custom' with limits that fit it", strings.Join(activityNames, ", ")))
+	}
+	builder.WriteString(". Then:\n")
+	builder.WriteString("- Recommend the best window, preferring my timing if I gave one, and say what makes it good.\n")
+	builder.WriteString("- If no window fits, say which limit rules things out (cold, heat, wind, rain or darkness) and suggest the least bad option or an indoor alternative.\n")
+	builder.WriteString("- Mention a backup window if the weather looks marginal, and any weather alerts for US locations from get_weather_alerts.\n")
+	builder.WriteString("Keep the answer short and practical.")
+
+	return mcp.NewGetPromptResult(
+		fmt.Sprintf("Plan %s around the weather", activity),
+		[]mcp.PromptMessage{
+			mcp.NewPromptMessage(mcp.RoleUser, mcp.NewTextContent(builder.String())),
+		},
+	), nil
+}
+
+type DailyBriefingPrompt struct {
+	Prompt  mcp.Prompt
+	Handler func(ctx context.Context, request mcp.GetPromptRequest) (*mcp.GetPromptResult, error)
+}
+
+type DiagnoseIPPrompt struct {
+	Prompt  mcp.Prompt
+	Handler func(ctx context.Context, request mcp.GetPromptRequest) (*mcp.GetPromptResult, error)
+}
+
+type TripWeatherPrompt struct {
+	Prompt  mcp.Prompt
+	Handler func(ctx context.Context, request mcp.GetPromptRequest) (*mcp.GetPromptResult, error)
+}
+
+type MathProblemPrompt struct {
+	Prompt  mcp.Prompt
+	Handler func(ctx context.Context, request mcp.GetPromptRequest) (*mcp.GetPromptResult, error)
+}
+
+// mathLevels are the explanation depths the math problem prompt accepts
+var mathLevels = []string{"beginner", "intermediate", "advanced"}
+
+func NewDailyBriefingPrompt() *DailyBriefingPrompt {
+	return &DailyBriefingPrompt{
+		Prompt:  dailyBriefingPrompt(),
+		Handler: dailyBriefingPromptHandler,
+	}
+}
+
+func NewDiagnoseIPPrompt() *DiagnoseIPPrompt {
+	return &DiagnoseIPPrompt{
+		Prompt:  diagnoseIPPrompt(),
+		Handler: diagnoseIPPromptHandler,
+	}
+}
+
+func NewTripWeatherPrompt() *TripWeatherPrompt {
+	return &TripWeatherPrompt{
+		Prompt:  tripWeatherPrompt(),
+		Handler: tripWeatherPromptHandler,
+	}
+}
+
+func NewMathProblemPrompt() *MathProblemPrompt {
+	return &MathProblemPrompt{
+		Prompt:  mathProblemPrompt(),
+		Handler: mathProblemPromptHandler,
+	}
+}
+
+func dailyBriefingPrompt() mcp.Prompt {
+	return mcp.NewPrompt("daily_briefing",
+		mcp.WithPromptDescription("A morning briefing for a location: current weather, today's forecast, air quality, daylight and any alerts"),
+		mcp.WithArgument("location",
+			mcp.ArgumentDescription("City name or coordinates (optional). Uses your IP location if not provided."),
+		),
+		mcp.WithArgument("units",
+			mcp.ArgumentDescription("metric, imperial, standard or uk (optional). Defaults to the location's customary units."),
+		),
+	)
+}
+
+func diagnoseIPPrompt() mcp.Prompt {
+	return mcp.NewPrompt("diagnose_ip",
+		mcp.WithPromptDescription("Investigate an IP address: where it is, who operates it and whether it looks like a home connection, a data center or a proxy"),
+		mcp.WithArgument("ip",
+			mcp.ArgumentDescription("The IPv4 or IPv6 address to diagnose"),
+			mcp.RequiredArgument(),
+		),
+	)
+}
+
+func tripWeatherPrompt() mcp.Prompt {
+	return mcp.NewPrompt("plan_trip_weather",
+		mcp.WithPromptDescription("Plan a trip around the weather in one or more cities on given dates, with what to pack"),
+		mcp.WithArgument("cities",
+			mcp.ArgumentDescription("The cities to visit, separated by semicolons, e.g. 'Lisbon,PT; Porto,PT'"),
+			mcp.RequiredArgument(),
+		),
+		mcp.WithArgument("dates",
+			mcp.ArgumentDescription("When, e.g. '2025-09-05 to 2025-09-07', 'this weekend' or 'Lisbon Friday, Porto Saturday'"),
+			mcp.RequiredArgument(),
+		),
+		mcp.WithArgument("units",
+			mcp.ArgumentDescription("metric, imperial, standard or uk (optional). Defaults to the first city's customary units."),
+		),
+	)
+}
+
+func mathProblemPrompt() mcp.Prompt {
+	return mcp.NewPrompt("solve_math_problem",
+		mcp.WithPromptDescription("Solve a math problem step by step, checking every calculation with the calculator"),
+		mcp.WithArgument("problem",
+			mcp.ArgumentDescription("The problem, as an expression or in words"),
+			mcp.RequiredArgument(),
+		),
+		mcp.WithArgument("level",
+			mcp.ArgumentDescription("How much to explain (optional): beginner, intermediate (default) or advanced"),
+		),
+	)
+}
+
+func dailyBriefingPromptHandler(ctx context.Context, request mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+	location := strings.TrimSpace(request.Params.Arguments["location"])
+	units := strings.ToLower(strings.TrimSpace(request.Params.Arguments["units"]))
+	if units != "" && !isValidUnits(units) {
+		return nil, fmt.Errorf("invalid units %q: must be one of metric, imperial, standard, uk", units)
+	}
+
+	place := "my location"
+	if location != "" {
+		place = location
+	}
+
+	var builder strings.Builder
+	builder.WriteString(fmt.Sprintf("Give me a short daily briefing for %s.\n\n", place))
+	builder.WriteString(fmt.Sprintf("Call these tools with %s:\n", toolArguments(location, units)))
+	builder.WriteString("- get_weather for the current conditions.\n")
+	builder.WriteString("- get_weather_forecast with start 'today', days 1 and granularity 'daypart' for the rest of the day.\n")
+	builder.WriteString("- get_air_quality for today's air quality.\n")
+	builder.WriteString("- get_astronomy for sunrise, sunset and day length.\n")
+	builder.WriteString("- get_weather_alerts if the location is in the US.\n")
+	builder.WriteString("Then open with one sentence on what the day will feel like, follow with the morning, afternoon and evening, ")
+	builder.WriteString("and end with practical advice: what to wear, whether to take an umbrella, and anything the air quality or alerts call for. ")
+	builder.WriteString("Leave out sections a tool could not answer.")
+
+	return mcp.NewGetPromptResult(
+		fmt.Sprintf("Daily briefing for %s", place),
+		[]mcp.PromptMessage{
+			mcp.NewPromptMessage(mcp.RoleUser, mcp.NewTextContent(builder.String())),
+		},
+	), nil
+}
+
+func diagnoseIPPromptHandler(ctx context.Context, request mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+	ip := strings.TrimSpace(request.Params.Arguments["ip"])
+	if ip == "" {
+		return nil, fmt.Errorf("ip is required")
+	}
+	if net.ParseIP(ip) == nil {
+		return nil, fmt.Errorf("invalid IP address %q", ip)
+	}
+
+	var builder strings.Builder
+	builder.WriteString(fmt.Sprintf("Diagnose the IP address %s.\n\n", ip))
+	builder.WriteString(fmt.Sprintf("Call get_ip_data with ip %q, and get_ip to compare it with my own address. Then report:\n", ip))
+	builder.WriteString("- Whether it is a private, loopback or otherwise reserved address, in which case the lookup has nothing to find.\n")
+	builder.WriteString("- Where it is located, and that IP geolocation is usually only accurate to the city or region.\n")
+	builder.WriteString("- Who operates it (ISP, organization and autonomous system), and whether that suggests a home or mobile connection, a data center or cloud host, or a VPN or proxy.\n")
+	builder.WriteString("- Whether it matches my own address or network.\n")
+	builder.WriteString("Finish with a one-line verdict.")
+
+	return mcp.NewGetPromptResult(
+		fmt.Sprintf("Diagnose %s", ip),
+		[]mcp.PromptMessage{
+			mcp.NewPromptMessage(mcp.RoleUser, mcp.NewTextContent(builder.String())),
+		},
+	), nil
+}
+
+func tripWeatherPromptHandler(ctx context.Context, request mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+	cities := splitCities(request.Params.Arguments["cities"])
+	if len(cities) == 0 {
+		return nil, fmt.Errorf("cities is required")
+	}
+	dates := strings.TrimSpace(request.Params.Arguments["dates"])
+	if dates == "" {
+		return nil, fmt.Errorf("dates is required")
+	}
+	units := strings.ToLower(strings.TrimSpace(request.Params.Arguments["units"]))
+	if units != "" && !isValidUnits(units) {
+		return nil, fmt.Errorf("invalid units %q: must be one of metric, imperial, standard, uk", units)
+	}
+
+	var builder strings.Builder
+	builder.WriteString(fmt.Sprintf("I'm planning a trip to %s, %s. What weather should I expect, and what should I pack?\n\n", strings.Join(cities, ", then "), dates))
+	builder.WriteString("For each city and its dates:\n")
+	builder.WriteString("- If the dates are within the next 5 days, call get_weather_forecast with the city as location, start set to the first date and days covering the stay.\n")
+	builder.WriteString("- If they are further out, call get_weather_history for the same dates last year and present it clearly as a guide to typical weather, not a forecast.\n")
+	if len(cities) > 1 {
+		builder.WriteString("- If the cities share a day within the forecast, call compare_weather with all of them for that day.\n")
+	}
+	if units != "" {
+		builder.WriteString(fmt.Sprintf("Use units %q in every call.\n", units))
+	}
+	builder.WriteString("Then summarize the weather city by city, point out the best and worst days for sightseeing, ")
+	builder.WriteString("and finish with a packing list that covers the warmest and coldest conditions of the whole trip.")
+
+	return mcp.NewGetPromptResult(
+		fmt.Sprintf("Trip weather for %s", strings.Join(cities, ", ")),
+		[]mcp.PromptMessage{
+			mcp.NewPromptMessage(mcp.RoleUser, mcp.NewTextContent(builder.String())),
+		},
+	), nil
+}
+
+func mathProblemPromptHandler(ctx context.Context, request mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+	problem := strings.TrimSpace(request.Params.Arguments["problem"])
+	if problem == "" {
+		return nil, fmt.Errorf("problem is required")
+	}
+	level := strings.ToLower(strings.TrimSpace(request.Params.Arguments["level"]))
+	if level == "" {
+		level = "intermediate"
+	}
+	if !slices.Contains(mathLevels, level) {
+		return nil, fmt.Errorf("invalid level %q: must be one of %s", level, strings.Join(mathLevels, ", "))
+	}
+
+	var builder strings.Builder
+	builder.WriteString(fmt.Sprintf("Solve this problem and explain the solution:\n\n%s\n\n", problem))
+	builder.WriteString("Work through it step by step. Evaluate every numeric step with the calculate tool instead of by hand, ")
+	builder.WriteString("writing the expression in its syntax (+, -, *, /, ^, sqrt(), sin(), log() and so on), and show each expression with its result. ")
+	switch level {
+	case "beginner":
+		builder.WriteString("Explain it for a beginner: define each term, say why every step is taken, and avoid shortcuts. ")
+	case "advanced":
+		builder.WriteString("Keep the explanation concise and skip routine algebra, but state the method and any assumptions. ")
+	default:
+		builder.WriteString("Explain the reasoning behind each step without spelling out routine arithmetic. ")
+	}
+	builder.WriteString("End with the final answer on its own line, with units if the problem has them, and a quick check that it makes sense.")
+
+	return mcp.NewGetPromptResult(
+		"Solve and explain a math problem",
+		[]mcp.PromptMessage{
+			mcp.NewPromptMessage(mcp.RoleUser, mcp.NewTextContent(builder.String())),
+		},
+	), nil
+}
+
+// toolArguments describes the location and units prompts ask tools to be called with
+func toolArguments(location string, units string) string {
+	arguments := "no location, so my IP location is used"
+	if location != "" {
+		arguments = fmt.Sprintf("location %q", location)
+	}
+	if units != "" {
+		arguments += fmt.Sprintf(" and units %q", units)
+	}
+	return arguments
+}
+
+// splitCities splits a semicolon-separated list of cities; commas are kept because they qualify
+// city names with a state or country
+func splitCities(value string) []string {
+	var cities []string
+	for _, city := range strings.Split(value, ";") {
+		if city = strings.TrimSpace(city); city != "" {
+			cities = append(cities, city)
+		}
+	}
+	return cities
+}
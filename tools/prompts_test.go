@@ -0,0 +1,146 @@
+package tools
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestActivityPrompt(t *testing.T) {
+	prompt := NewActivityPrompt()
+
+	t.Run("KnownActivity", func(t *testing.T) {
+		request := mcp.GetPromptRequest{}
+		request.Params.Arguments = map[string]string{"activity": "Cycling", "location": "Valencia,ES", "when": "this weekend"}
+
+		result, err := prompt.Handler(context.Background(), request)
+		if err != nil {
+			t.Fatalf("Expected success, got error: %v", err)
+		}
+		if len(result.Messages) != 1 || result.Messages[0].Role != mcp.RoleUser {
+			t.Fatalf("Expected a single user message, got %+v", result.Messages)
+		}
+		text := result.Messages[0].Content.(mcp.TextContent).Text
+		expected := []string{
+			"I'm thinking about Cycling in Valencia,ES, ideally this weekend.",
+			"weather_suitability tool with location \"Valencia,ES\" and the \"cycling\" activity profile",
+		}
+		for _, s := range expected {
+			if !strings.Contains(text, s) {
+				t.Errorf("Expected %q in prompt:\n%s", s, text)
+			}
+		}
+	})
+
+	t.Run("OtherActivity", func(t *testing.T) {
+		request := mcp.GetPromptRequest{}
+		request.Params.Arguments = map[string]string{"activity": "a garden party"}
+
+		result, err := prompt.Handler(context.Background(), request)
+		if err != nil {
+			t.Fatalf("Expected success, got error: %v", err)
+		}
+		if text := result.Messages[0].Content.(mcp.TextContent).Text; !strings.Contains(text, "closest to this") {
+			t.Errorf("Expected the prompt to ask for the closest profile:\n%s", text)
+		}
+	})
+
+	t.Run("MissingActivity", func(t *testing.T) {
+		if _, err := prompt.Handler(context.Background(), mcp.GetPromptRequest{}); err == nil || !strings.Contains(err.Error(), "activity is required") {
+			t.Errorf("Expected an error for a missing activity, got %v", err)
+		}
+	})
+}
+
+// promptText gets a prompt with the given arguments and returns the text of its single message
+func promptText(t *testing.T, handler func(context.Context, mcp.GetPromptRequest) (*mcp.GetPromptResult, error), args map[string]string) (string, error) {
+	t.Helper()
+
+	request := mcp.GetPromptRequest{}
+	request.Params.Arguments = args
+	result, err := handler(context.Background(), request)
+	if err != nil {
+		return "", err
+	}
+	if len(result.Messages) != 1 || result.Messages[0].Role != mcp.RoleUser {
+		t.Fatalf("Expected a single user message, got %+v", result.Messages)
+	}
+	return result.Messages[0].Content.(mcp.TextContent).Text, nil
+}
+
+func TestWorkflowPrompts(t *testing.T) {
+	testCases := []struct {
+		name     string
+		handler  func(context.Context, mcp.GetPromptRequest) (*mcp.GetPromptResult, error)
+		args     map[string]string
+		expected []string
+	}{
+		{
+			"DailyBriefing",
+			NewDailyBriefingPrompt().Handler,
+			map[string]string{"location": "Valencia,ES", "units": "metric"},
+			[]string{"daily briefing for Valencia,ES", `location "Valencia,ES" and units "metric"`, "get_air_quality", "get_astronomy"},
+		},
+		{
+			"DailyBriefingFromIP",
+			NewDailyBriefingPrompt().Handler,
+			map[string]string{},
+			[]string{"daily briefing for my location", "no location, so my IP location is used"},
+		},
+		{
+			"DiagnoseIP",
+			NewDiagnoseIPPrompt().Handler,
+			map[string]string{"ip": "8.8.8.8"},
+			[]string{"Diagnose the IP address 8.8.8.8", `get_ip_data with ip "8.8.8.8"`, "data center"},
+		},
+		{
+			"TripWeather",
+			NewTripWeatherPrompt().Handler,
+			map[string]string{"cities": "Lisbon,PT; Porto,PT", "dates": "this weekend", "units": "uk"},
+			[]string{"trip to Lisbon,PT, then Porto,PT, this weekend", "get_weather_history", "compare_weather", `units "uk"`},
+		},
+		{
+			"MathProblem",
+			NewMathProblemPrompt().Handler,
+			map[string]string{"problem": "What is the hypotenuse of a 3 by 4 triangle?", "level": "Beginner"},
+			[]string{"3 by 4 triangle", "calculate tool", "for a beginner"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			text, err := promptText(t, tc.handler, tc.args)
+			if err != nil {
+				t.Fatalf("Expected success, got error: %v", err)
+			}
+			for _, s := range tc.expected {
+				if !strings.Contains(text, s) {
+					t.Errorf("Expected %q in prompt:\n%s", s, text)
+				}
+			}
+		})
+	}
+}
+
+func TestWorkflowPrompts_Validation(t *testing.T) {
+	testCases := []struct {
+		handler  func(context.Context, mcp.GetPromptRequest) (*mcp.GetPromptResult, error)
+		args     map[string]string
+		errorMsg string
+	}{
+		{NewDailyBriefingPrompt().Handler, map[string]string{"units": "furlongs"}, "invalid units"},
+		{NewDiagnoseIPPrompt().Handler, map[string]string{}, "ip is required"},
+		{NewDiagnoseIPPrompt().Handler, map[string]string{"ip": "example.com"}, "invalid IP address"},
+		{NewTripWeatherPrompt().Handler, map[string]string{"cities": " ; ", "dates": "tomorrow"}, "cities is required"},
+		{NewTripWeatherPrompt().Handler, map[string]string{"cities": "Paris"}, "dates is required"},
+		{NewMathProblemPrompt().Handler, map[string]string{"problem": "1+1", "level": "expert"}, "invalid level"},
+	}
+
+	for _, tc := range testCases {
+		if _, err := promptText(t, tc.handler, tc.args); err == nil || !strings.Contains(err.Error(), tc.errorMsg) {
+			t.Errorf("Expected error %q for %v, got %v", tc.errorMsg, tc.args, err)
+		}
+	}
+}
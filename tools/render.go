@@ -0,0 +1,95 @@
+package tools
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const (
+	formatMarkdown = "markdown"
+	formatPlain    = "plain"
+	formatCompact  = "compact"
+	formatJSON     = "json"
+)
+
+// compactSummarizer is implemented by structured results that can describe themselves in one line
+type compactSummarizer interface {
+	CompactSummary() string
+}
+
+// withFormat adds the shared format argument to a tool definition
+func withFormat() mcp.ToolOption {
+	return mcp.WithString("format",
+		mcp.Description("Output format (optional). 'markdown' (default), 'plain' text without markup, 'compact' one-line summary, or 'json' for the structured result"),
+		mcp.Enum(formatMarkdown, formatPlain, formatCompact, formatJSON),
+	)
+}
+
+// parseFormat reads the format argument, defaulting to markdown
+func parseFormat(request mcp.CallToolRequest) (string, error) {
+	format := strings.ToLower(request.GetString("format", formatMarkdown))
+	switch format {
+	case formatMarkdown, formatPlain, formatCompact, formatJSON:
+		return format, nil
+	default:
+		return "", fmt.Errorf("invalid format %q: must be one of markdown, plain, compact, json", format)
+	}
+}
+
+// renderResult renders a tool's Markdown output in the requested format and returns it together
+// with the structured content, which is the same whatever the format
+func renderResult(format string, markdown string, structured any) *mcp.CallToolResult {
+	var text string
+	switch format {
+	case formatPlain:
+		text = markdownToPlain(markdown)
+	case formatCompact:
+		if summarizer, ok := structured.(compactSummarizer); ok {
+			text = summarizer.CompactSummary()
+		} else {
+			text = strings.SplitN(markdownToPlain(markdown), "\n", 2)[0]
+		}
+	case formatJSON:
+		encoded, err := json.MarshalIndent(structured, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to encode result as JSON: %v", err))
+		}
+		text = string(encoded)
+	default:
+		text = markdown
+	}
+
+	return mcp.NewToolResultStructured(structured, text)
+}
+
+var (
+	markdownBold     = regexp.MustCompile(`\*\*([^*]+)\*\*`)
+	markdownEmphasis = regexp.MustCompile(`\*([^*]+)\*`)
+)
+
+// markdownToPlain strips the Markdown our formatters produce: headings, bold labels, emphasis,
+// code spans and list bullets, keeping the line structure
+func markdownToPlain(markdown string) string {
+	lines := strings.Split(markdown, "\n")
+	for i, line := range lines {
+		trimmed := strings.TrimLeft(line, " ")
+		switch {
+		case strings.HasPrefix(trimmed, "## "):
+			line = strings.TrimPrefix(trimmed, "## ") + ":"
+		case strings.HasPrefix(trimmed, "# "):
+			line = strings.TrimPrefix(trimmed, "# ")
+		case strings.HasPrefix(trimmed, "- "):
+			line = strings.TrimPrefix(trimmed, "- ")
+		}
+
+		line = markdownBold.ReplaceAllString(line, "$1")
+		line = markdownEmphasis.ReplaceAllString(line, "$1")
+		line = strings.ReplaceAll(line, "`", "")
+		lines[i] = line
+	}
+	return strings.Join(lines, "\n")
+}
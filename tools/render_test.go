@@ -0,0 +1,93 @@
+package tools
+
+import (
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestMarkdownToPlain(t *testing.T) {
+	markdown := "# Weather for Valencia\n\n## Current Conditions\n- **Temperature:** 28.4°C\n- *Observed* at `10:00`\n"
+	expected := "Weather for Valencia\n\nCurrent Conditions:\nTemperature: 28.4°C\nObserved at 10:00\n"
+
+	if got := markdownToPlain(markdown); got != expected {
+		t.Errorf("markdownToPlain() =\n%q\nexpected\n%q", got, expected)
+	}
+}
+
+func TestFormatArgument(t *testing.T) {
+	os.Setenv("OPENWEATHER_API_KEY", "test_api_key")
+	defer os.Unsetenv("OPENWEATHER_API_KEY")
+	newOpenWeatherMapTestServer(t)
+	newNWSTestServer(t)
+
+	weatherTool := NewWeatherTool()
+	forecastTool := NewWeatherForecastTool()
+	calculator := NewCalculatorTool()
+
+	t.Run("Plain", func(t *testing.T) {
+		content, isError := callToolText(t, weatherTool.Handler, map[string]any{"location": "39.4697,-0.3763", "units": "metric", "format": "plain"})
+		if isError {
+			t.Fatalf("Expected success, got error: %s", content)
+		}
+		if strings.Contains(content, "#") || strings.Contains(content, "**") {
+			t.Errorf("Expected no Markdown in plain output:\n%s", content)
+		}
+		if !strings.Contains(content, "Temperature: 28.4°C") {
+			t.Errorf("Expected temperature line in plain output:\n%s", content)
+		}
+	})
+
+	t.Run("Compact", func(t *testing.T) {
+		testCases := []struct {
+			name     string
+			handler  func(t *testing.T) (string, bool)
+			expected string
+		}{
+			{"weather", func(t *testing.T) (string, bool) {
+				return callToolText(t, weatherTool.Handler, map[string]any{"location": "39.4697,-0.3763", "units": "metric", "format": "compact"})
+			}, "Valencia, ES: 28.4°C"},
+			{"forecast", func(t *testing.T) (string, bool) {
+				return callToolText(t, forecastTool.Handler, map[string]any{"location": "39.4676,-0.3771", "granularity": "daily", "format": "compact"})
+			}, "Tue Sep 2"},
+			{"calculator", func(t *testing.T) (string, bool) {
+				return callToolText(t, calculator.Handler, map[string]any{"expression": "2 + 3", "format": "compact"})
+			}, "2 + 3 = 5"},
+		}
+
+		for _, tc := range testCases {
+			content, isError := tc.handler(t)
+			if isError {
+				t.Fatalf("%s: expected success, got error: %s", tc.name, content)
+			}
+			if strings.Contains(content, "\n") || !strings.Contains(content, tc.expected) {
+				t.Errorf("%s: expected a single line containing %q, got:\n%s", tc.name, tc.expected, content)
+			}
+		}
+	})
+
+	t.Run("JSON", func(t *testing.T) {
+		args := map[string]any{"location": "40.7128,-74.0060", "provider": "nws", "format": "json"}
+		content, isError := callToolText(t, weatherTool.Handler, args)
+		if isError {
+			t.Fatalf("Expected success, got error: %s", content)
+		}
+
+		var decoded map[string]any
+		if err := json.Unmarshal([]byte(content), &decoded); err != nil {
+			t.Fatalf("Expected JSON output, got %v:\n%s", err, content)
+		}
+		structured := callToolStructured(t, weatherTool.Tool, weatherTool.Handler, args)
+		if decoded["temperature"] != structured["temperature"] || decoded["station"] != structured["station"] {
+			t.Errorf("JSON text %v does not match structured content %v", decoded, structured)
+		}
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		content, isError := callToolText(t, calculator.Handler, map[string]any{"expression": "1 + 1", "format": "xml"})
+		if !isError || !strings.Contains(content, "invalid format") {
+			t.Errorf("Expected invalid format error, got: %s", content)
+		}
+	})
+}
@@ -0,0 +1,1008 @@
+package tools
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+// ReportLocation identifies the place a weather report is for
+type ReportLocation struct {
+	Name      string  `json:"name"`
+	Country   string  `json:"country,omitempty"`
+	Lat       float64 `json:"lat"`
+	Lon       float64 `json:"lon"`
+	Timezone  string  `json:"timezone,omitempty" jsonschema:"description=IANA zone or UTC offset used for local times"`
+	Requested string  `json:"requested,omitempty" jsonschema:"description=Location as requested by the caller"`
+}
+
+// WeatherReport is the structured form of current conditions. Values are in the units named by Units.
+type WeatherReport struct {
+	Provider       string         `json:"provider" jsonschema:"enum=openweathermap,enum=nws"`
+	Location       ReportLocation `json:"location"`
+	Units          UnitSystem     `json:"units"`
+	ObservedAt     string         `json:"observedAt,omitempty" jsonschema:"format=date-time"`
+	Condition      string         `json:"condition,omitempty"`
+	Temperature    *float64       `json:"temperature,omitempty"`
+	FeelsLike      *float64       `json:"feelsLike,omitempty"`
+	TempMin        *float64       `json:"tempMin,omitempty"`
+	TempMax        *float64       `json:"tempMax,omitempty"`
+	Humidity       *float64       `json:"humidity,omitempty" jsonschema:"description=Relative humidity in percent"`
+	Pressure       *float64       `json:"pressure,omitempty" jsonschema:"description=Pressure at sea level"`
+	GroundPressure *float64       `json:"groundPressure,omitempty" jsonschema:"description=Pressure at ground level"`
+	WindSpeed      *float64       `json:"windSpeed,omitempty"`
+	WindGust       *float64       `json:"windGust,omitempty"`
+	WindDirection  *int           `json:"windDirection,omitempty" jsonschema:"description=Direction the wind comes from in degrees"`
+	Visibility     *float64       `json:"visibility,omitempty"`
+	Cloudiness     *int           `json:"cloudiness,omitempty" jsonschema:"description=Cloud cover in percent"`
+	RainLastHour   *float64       `json:"rainLastHour,omitempty"`
+	SnowLastHour   *float64       `json:"snowLastHour,omitempty"`
+	Sunrise        string         `json:"sunrise,omitempty" jsonschema:"format=date-time"`
+	Sunset         string         `json:"sunset,omitempty" jsonschema:"format=date-time"`
+	Station        string         `json:"station,omitempty"`
+	Comfort        *ComfortReport `json:"comfort,omitempty" jsonschema:"description=Metrics derived from temperature, humidity and wind"`
+}
+
+// ComfortReport holds dew point, heat index, wind chill, apparent temperature and their ratings.
+// Heat index applies from 26.7°C (80°F) and wind chill up to 10°C with wind above 4.8 km/h.
+type ComfortReport struct {
+	DewPoint            float64  `json:"dewPoint"`
+	HeatIndex           *float64 `json:"heatIndex,omitempty"`
+	WindChill           *float64 `json:"windChill,omitempty"`
+	ApparentTemperature float64  `json:"apparentTemperature" jsonschema:"description=Heat index or wind chill when they apply, otherwise the air temperature"`
+	ApparentCategory    string   `json:"apparentCategory" jsonschema:"enum=Dangerously Cold,enum=Very Cold,enum=Cold,enum=Cool,enum=Mild,enum=Warm,enum=Hot,enum=Very Hot,enum=Dangerously Hot"`
+	HumidityComfort     string   `json:"humidityComfort" jsonschema:"enum=Dry,enum=Comfortable,enum=Slightly Humid,enum=Humid,enum=Muggy,enum=Oppressive"`
+	Beaufort            int      `json:"beaufort" jsonschema:"minimum=0,maximum=12"`
+	BeaufortDescription string   `json:"beaufortDescription"`
+}
+
+// ForecastReport is the structured form of a forecast. OpenWeatherMap forecasts fill Slots and
+// Summaries according to the granularity; NWS forecasts fill Periods.
+type ForecastReport struct {
+	Provider    string            `json:"provider" jsonschema:"enum=openweathermap,enum=nws"`
+	Location    ReportLocation    `json:"location"`
+	Units       UnitSystem        `json:"units"`
+	Granularity string            `json:"granularity,omitempty"`
+	Message     string            `json:"message,omitempty" jsonschema:"description=Why no forecast data was returned"`
+	Slots       []ForecastSlot    `json:"slots,omitempty"`
+	Summaries   []ForecastSummary `json:"summaries,omitempty"`
+	Periods     []ForecastPeriod  `json:"periods,omitempty"`
+}
+
+// ForecastSlot is a single 3-hour forecast step
+type ForecastSlot struct {
+	Time                string        `json:"time" jsonschema:"format=date-time"`
+	Temperature         float64       `json:"temperature"`
+	FeelsLike           float64       `json:"feelsLike"`
+	Condition           string        `json:"condition,omitempty"`
+	PrecipitationChance float64       `json:"precipitationChance" jsonschema:"description=Probability of precipitation in percent"`
+	WindSpeed           float64       `json:"windSpeed"`
+	WindDirection       int           `json:"windDirection"`
+	Humidity            int           `json:"humidity"`
+	Pressure            float64       `json:"pressure" jsonschema:"description=Pressure at sea level"`
+	GroundPressure      *float64      `json:"groundPressure,omitempty" jsonschema:"description=Pressure at ground level"`
+	Cloudiness          int           `json:"cloudiness"`
+	WindGust            *float64      `json:"windGust,omitempty"`
+	Visibility          *float64      `json:"visibility,omitempty"`
+	Rain                *float64      `json:"rain,omitempty" jsonschema:"description=Rain volume over the 3 hours"`
+	Snow                *float64      `json:"snow,omitempty" jsonschema:"description=Snow volume over the 3 hours"`
+	Comfort             ComfortReport `json:"comfort"`
+}
+
+// ForecastSummary condenses the slots of a day, or of one part of a day
+type ForecastSummary struct {
+	Label               string  `json:"label"`
+	Date                string  `json:"date" jsonschema:"format=date"`
+	Part                string  `json:"part,omitempty" jsonschema:"enum=Night,enum=Morning,enum=Afternoon,enum=Evening"`
+	TempMin             float64 `json:"tempMin"`
+	TempMax             float64 `json:"tempMax"`
+	FeelsLikeMin        float64 `json:"feelsLikeMin"`
+	FeelsLikeMax        float64 `json:"feelsLikeMax"`
+	Condition           string  `json:"condition,omitempty"`
+	PrecipitationChance float64 `json:"precipitationChance"`
+	MaxWindSpeed        float64 `json:"maxWindSpeed"`
+	Humidity            float64 `json:"humidity"`
+	Pressure            float64 `json:"pressure" jsonschema:"description=Average pressure at sea level"`
+	PressureChange      float64 `json:"pressureChange" jsonschema:"description=Pressure change from the first to the last slot"`
+	PressureTrend       string  `json:"pressureTrend" jsonschema:"enum=steady,enum=rising,enum=rising quickly,enum=falling,enum=falling quickly"`
+	Cloudiness          float64 `json:"cloudiness"`
+	Rain                float64 `json:"rain" jsonschema:"description=Total rain accumulation"`
+	Snow                float64 `json:"snow" jsonschema:"description=Total snow accumulation"`
+	MaxWindGust         float64 `json:"maxWindGust"`
+	MinVisibility       float64 `json:"minVisibility"`
+	DewPointMax         float64 `json:"dewPointMax"`
+	ApparentMin         float64 `json:"apparentMin"`
+	ApparentMax         float64 `json:"apparentMax"`
+	BeaufortMax         int     `json:"beaufortMax" jsonschema:"minimum=0,maximum=12"`
+}
+
+// ForecastPeriod is a National Weather Service day or night forecast period
+type ForecastPeriod struct {
+	Name                string   `json:"name"`
+	Start               string   `json:"start" jsonschema:"format=date-time"`
+	End                 string   `json:"end" jsonschema:"format=date-time"`
+	IsDaytime           bool     `json:"isDaytime"`
+	Temperature         float64  `json:"temperature"`
+	Condition           string   `json:"condition"`
+	Wind                string   `json:"wind,omitempty"`
+	PrecipitationChance *float64 `json:"precipitationChance,omitempty"`
+	Detailed            string   `json:"detailed,omitempty"`
+}
+
+// AirQualityReport is the structured form of get_air_quality. AQI is OpenWeatherMap's 1-5 index
+// and concentrations are in µg/m³.
+type AirQualityReport struct {
+	Provider      string             `json:"provider" jsonschema:"enum=openweathermap"`
+	Location      ReportLocation     `json:"location"`
+	MeasuredAt    string             `json:"measuredAt" jsonschema:"format=date-time"`
+	AQI           int                `json:"aqi" jsonschema:"minimum=1,maximum=5"`
+	Category      string             `json:"category" jsonschema:"enum=Good,enum=Fair,enum=Moderate,enum=Poor,enum=Very Poor"`
+	Guidance      string             `json:"guidance"`
+	MainPollutant string             `json:"mainPollutant"`
+	Pollutants    []PollutantReading `json:"pollutants"`
+	Forecast      []AirQualityDay    `json:"forecast,omitempty"`
+}
+
+// PollutantReading is one pollutant's concentration and its level on the 1-5 scale
+type PollutantReading struct {
+	Code          string  `json:"code" jsonschema:"enum=pm2_5,enum=pm10,enum=o3,enum=no2,enum=so2,enum=co"`
+	Name          string  `json:"name"`
+	Concentration float64 `json:"concentration" jsonschema:"description=Concentration in µg/m³"`
+	Level         int     `json:"level" jsonschema:"minimum=1,maximum=5"`
+	Category      string  `json:"category"`
+}
+
+// AirQualityDay summarizes one local day of the air quality forecast
+type AirQualityDay struct {
+	Date             string  `json:"date" jsonschema:"format=date"`
+	AQIMin           int     `json:"aqiMin" jsonschema:"minimum=1,maximum=5"`
+	AQIMax           int     `json:"aqiMax" jsonschema:"minimum=1,maximum=5"`
+	Category         string  `json:"category" jsonschema:"description=Category of the worst hour"`
+	MainPollutant    string  `json:"mainPollutant"`
+	MaxConcentration float64 `json:"maxConcentration" jsonschema:"description=Highest concentration of the main pollutant in µg/m³"`
+}
+
+// HistoryReport is the structured form of get_weather_history. Dates are local to the location.
+type HistoryReport struct {
+	Provider  string         `json:"provider" jsonschema:"enum=open-meteo"`
+	Location  ReportLocation `json:"location"`
+	Units     UnitSystem     `json:"units"`
+	StartDate string         `json:"startDate" jsonschema:"format=date"`
+	EndDate   string         `json:"endDate" jsonschema:"format=date"`
+	Days      []HistoryDay   `json:"days"`
+	Hours     []HistoryHour  `json:"hours,omitempty"`
+}
+
+// HistoryDay holds the aggregates of one past day. Values are missing when the archive has no data yet.
+type HistoryDay struct {
+	Date          string   `json:"date" jsonschema:"format=date"`
+	Condition     string   `json:"condition,omitempty" jsonschema:"description=Dominant condition group"`
+	TempMin       *float64 `json:"tempMin,omitempty"`
+	TempMax       *float64 `json:"tempMax,omitempty"`
+	TempMean      *float64 `json:"tempMean,omitempty"`
+	Precipitation *float64 `json:"precipitation,omitempty" jsonschema:"description=Total precipitation over the day"`
+	WindSpeedMax  *float64 `json:"windSpeedMax,omitempty"`
+	WindGustMax   *float64 `json:"windGustMax,omitempty"`
+}
+
+// HistoryHour is one hour of past weather
+type HistoryHour struct {
+	Time          string   `json:"time" jsonschema:"format=date-time"`
+	Condition     string   `json:"condition,omitempty"`
+	Temperature   *float64 `json:"temperature,omitempty"`
+	Humidity      *float64 `json:"humidity,omitempty" jsonschema:"description=Relative humidity in percent"`
+	Precipitation *float64 `json:"precipitation,omitempty"`
+	WindSpeed     *float64 `json:"windSpeed,omitempty"`
+}
+
+// ComparisonReport is the structured form of compare_weather. Locations keep the requested order;
+// those that could not be fetched carry an error instead of values.
+type ComparisonReport struct {
+	Units     UnitSystem          `json:"units"`
+	Date      string              `json:"date,omitempty" jsonschema:"format=date,description=Forecast day compared; missing for current conditions"`
+	Locations []ComparedLocation  `json:"locations"`
+	Rankings  []ComparisonRanking `json:"rankings"`
+}
+
+// ComparedLocation is one location of the comparison
+type ComparedLocation struct {
+	Requested string             `json:"requested"`
+	Location  *ReportLocation    `json:"location,omitempty"`
+	Condition string             `json:"condition,omitempty"`
+	Values    map[string]float64 `json:"values,omitempty" jsonschema:"description=Compared values by metric: temperature, feelsLike, humidity, windSpeed, cloudiness and precipitation for current conditions; tempMax, tempMin, precipitationChance, precipitation, maxWindSpeed, humidity and cloudiness for a forecast day"`
+	Error     string             `json:"error,omitempty"`
+}
+
+// ComparisonRanking names the best and worst location for a metric, by their requested names
+type ComparisonRanking struct {
+	Metric     string  `json:"metric"`
+	Best       string  `json:"best"`
+	BestValue  float64 `json:"bestValue"`
+	Worst      string  `json:"worst"`
+	WorstValue float64 `json:"worstValue"`
+}
+
+// AstronomyReport is the structured form of get_astronomy. Times are in the report's timezone.
+type AstronomyReport struct {
+	Location ReportLocation `json:"location"`
+	Date     string         `json:"date" jsonschema:"format=date"`
+	Sun      SunReport      `json:"sun"`
+	Moon     MoonReport     `json:"moon"`
+}
+
+// SunReport holds the sun's events of a day. Sunrise and sunset are missing during polar day or night.
+type SunReport struct {
+	Sunrise              string         `json:"sunrise,omitempty" jsonschema:"format=date-time"`
+	Sunset               string         `json:"sunset,omitempty" jsonschema:"format=date-time"`
+	SolarNoon            string         `json:"solarNoon" jsonschema:"format=date-time"`
+	DayLengthMinutes     int            `json:"dayLengthMinutes"`
+	UpAllDay             bool           `json:"upAllDay,omitempty"`
+	DownAllDay           bool           `json:"downAllDay,omitempty"`
+	CivilTwilight        TwilightReport `json:"civilTwilight"`
+	NauticalTwilight     TwilightReport `json:"nauticalTwilight"`
+	AstronomicalTwilight TwilightReport `json:"astronomicalTwilight"`
+}
+
+// TwilightReport is when a twilight begins in the morning and ends in the evening
+type TwilightReport struct {
+	Dawn     string `json:"dawn,omitempty" jsonschema:"format=date-time"`
+	Dusk     string `json:"dusk,omitempty" jsonschema:"format=date-time"`
+	AllNight bool   `json:"allNight,omitempty" jsonschema:"description=The sun never gets low enough for this twilight to end"`
+	None     bool   `json:"none,omitempty" jsonschema:"description=The sun never gets high enough for this twilight to begin"`
+}
+
+// MoonReport holds the moon's phase at local noon and its rise and set during the day
+type MoonReport struct {
+	Phase        float64 `json:"phase" jsonschema:"minimum=0,maximum=1,description=0 new moon, 0.25 first quarter, 0.5 full moon, 0.75 last quarter"`
+	PhaseName    string  `json:"phaseName" jsonschema:"enum=New Moon,enum=Waxing Crescent,enum=First Quarter,enum=Waxing Gibbous,enum=Full Moon,enum=Waning Gibbous,enum=Last Quarter,enum=Waning Crescent"`
+	Age          float64 `json:"age" jsonschema:"description=Days since the last new moon"`
+	Illumination float64 `json:"illumination" jsonschema:"description=Illuminated fraction of the disc in percent"`
+	Moonrise     string  `json:"moonrise,omitempty" jsonschema:"format=date-time"`
+	Moonset      string  `json:"moonset,omitempty" jsonschema:"format=date-time"`
+	UpAllDay     bool    `json:"upAllDay,omitempty"`
+	DownAllDay   bool    `json:"downAllDay,omitempty"`
+}
+
+// SuitabilityReport is the structured form of weather_suitability. Windows are ordered best first.
+type SuitabilityReport struct {
+	Location ReportLocation    `json:"location"`
+	Units    UnitSystem        `json:"units"`
+	Profile  ProfileReport     `json:"profile"`
+	Windows  []WindowReport    `json:"windows"`
+	Slots    []SlotSuitability `json:"slots"`
+}
+
+// ProfileReport holds the limits the forecast was scored against
+type ProfileReport struct {
+	Activity               string  `json:"activity"`
+	MinTemp                float64 `json:"minTemp"`
+	MaxTemp                float64 `json:"maxTemp"`
+	MaxWind                float64 `json:"maxWind"`
+	MaxPrecipitationChance float64 `json:"maxPrecipitationChance"`
+	Daylight               bool    `json:"daylight" jsonschema:"description=Only times between sunrise and sunset are suitable"`
+	DurationHours          float64 `json:"durationHours" jsonschema:"description=Shortest window suggested"`
+}
+
+// WindowReport is a run of consecutive suitable forecast slots
+type WindowReport struct {
+	Start                  string  `json:"start" jsonschema:"format=date-time"`
+	End                    string  `json:"end" jsonschema:"format=date-time"`
+	Hours                  float64 `json:"hours"`
+	Score                  int     `json:"score" jsonschema:"minimum=0,maximum=100"`
+	TempMin                float64 `json:"tempMin"`
+	TempMax                float64 `json:"tempMax"`
+	MaxWindSpeed           float64 `json:"maxWindSpeed"`
+	MaxPrecipitationChance float64 `json:"maxPrecipitationChance"`
+}
+
+// SlotSuitability scores one 3-hour forecast slot; Limits lists the profile limits it breaks
+type SlotSuitability struct {
+	Time     string   `json:"time" jsonschema:"format=date-time"`
+	Score    int      `json:"score" jsonschema:"minimum=0,maximum=100"`
+	Suitable bool     `json:"suitable"`
+	Daylight bool     `json:"daylight"`
+	Limits   []string `json:"limits,omitempty" jsonschema:"enum=too cold,enum=too hot,enum=too windy,enum=likely precipitation,enum=dark"`
+}
+
+// DisplayName renders the location as "Name, Country"
+func (l ReportLocation) DisplayName() string {
+	if l.Country == "" || strings.HasSuffix(l.Name, ", "+l.Country) {
+		return l.Name
+	}
+	return fmt.Sprintf("%s, %s", l.Name, l.Country)
+}
+
+// CompactSummary renders current conditions as one line, e.g. "Valencia, ES: 26.1°C, Clear Sky, humidity 50%, wind 3.1 m/s"
+func (r WeatherReport) CompactSummary() string {
+	parts := []string{}
+	if r.Temperature != nil {
+		parts = append(parts, fmt.Sprintf("%.1f%s", *r.Temperature, r.Units.Temperature))
+	}
+	if r.Condition != "" {
+		parts = append(parts, r.Condition)
+	}
+	if r.Humidity != nil {
+		parts = append(parts, fmt.Sprintf("humidity %.0f%%", *r.Humidity))
+	}
+	if r.WindSpeed != nil {
+		wind := fmt.Sprintf("wind %.1f %s", *r.WindSpeed, r.Units.WindSpeed)
+		if r.WindDirection != nil {
+			wind += " " + getWindDirection(*r.WindDirection)
+		}
+		parts = append(parts, wind)
+	}
+	return fmt.Sprintf("%s: %s", r.Location.DisplayName(), strings.Join(parts, ", "))
+}
+
+// CompactSummary renders air quality as one line, e.g. "Valencia: AQI 2 (Fair), main pollutant O3 71.3 µg/m³"
+func (r AirQualityReport) CompactSummary() string {
+	summary := fmt.Sprintf("%s: AQI %d (%s)", r.Location.DisplayName(), r.AQI, r.Category)
+	for _, reading := range r.Pollutants {
+		if reading.Name == r.MainPollutant {
+			summary += fmt.Sprintf(", main pollutant %s %.1f µg/m³", reading.Name, reading.Concentration)
+		}
+	}
+	return summary
+}
+
+// CompactSummary renders past weather as one line of semicolon-separated days, e.g. "Valencia: 2025-09-02 21.5-29.6°C 0.0 mm Clear"
+func (r HistoryReport) CompactSummary() string {
+	var entries []string
+	for _, day := range r.Days {
+		if day.TempMin == nil || day.TempMax == nil {
+			entries = append(entries, day.Date+" no data")
+			continue
+		}
+		entry := fmt.Sprintf("%s %.1f-%.1f%s", day.Date, *day.TempMin, *day.TempMax, r.Units.Temperature)
+		if day.Precipitation != nil {
+			entry += fmt.Sprintf(" %.1f %s", *day.Precipitation, r.Units.Precipitation)
+		}
+		if day.Condition != "" {
+			entry += " " + day.Condition
+		}
+		entries = append(entries, entry)
+	}
+	if len(entries) == 0 {
+		entries = append(entries, "no history data")
+	}
+	return fmt.Sprintf("%s: %s", r.Location.DisplayName(), strings.Join(entries, "; "))
+}
+
+// CompactSummary renders the comparison as one line of semicolon-separated locations
+func (r ComparisonReport) CompactSummary() string {
+	var entries []string
+	for _, location := range r.Locations {
+		if location.Error != "" {
+			entries = append(entries, location.Requested+" unavailable")
+			continue
+		}
+		name := location.Requested
+		if location.Location != nil {
+			name = location.Location.DisplayName()
+		}
+		parts := []string{name}
+		for _, key := range []string{"temperature", "tempMax"} {
+			if value, ok := location.Values[key]; ok {
+				parts = append(parts, fmt.Sprintf("%.1f%s", value, r.Units.Temperature))
+			}
+		}
+		if location.Condition != "" {
+			parts = append(parts, location.Condition)
+		}
+		entries = append(entries, strings.Join(parts, " "))
+	}
+	return "Comparison: " + strings.Join(entries, "; ")
+}
+
+// CompactSummary renders the day as one line, e.g. "Valencia, ES 2025-09-02: sunrise 07:24, sunset 20:29, ..."
+func (r AstronomyReport) CompactSummary() string {
+	var parts []string
+	switch {
+	case r.Sun.UpAllDay:
+		parts = append(parts, "sun up all day")
+	case r.Sun.DownAllDay:
+		parts = append(parts, "sun down all day")
+	default:
+		parts = append(parts, "sunrise "+compactClock(r.Sun.Sunrise), "sunset "+compactClock(r.Sun.Sunset))
+	}
+	parts = append(parts, fmt.Sprintf("day %s", formatDayLength(time.Duration(r.Sun.DayLengthMinutes)*time.Minute)))
+	parts = append(parts, fmt.Sprintf("%s %.0f%%", r.Moon.PhaseName, r.Moon.Illumination))
+	return fmt.Sprintf("%s %s: %s", r.Location.DisplayName(), r.Date, strings.Join(parts, ", "))
+}
+
+// compactClock shortens an RFC 3339 time to its 24-hour clock
+func compactClock(value string) string {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t.Format("15:04")
+	}
+	return value
+}
+
+// CompactSummary renders the best windows as one line
+func (r SuitabilityReport) CompactSummary() string {
+	if len(r.Windows) == 0 {
+		return fmt.Sprintf("%s %s: no suitable window", r.Location.DisplayName(), r.Profile.Activity)
+	}
+	var entries []string
+	for _, window := range r.Windows {
+		entries = append(entries, fmt.Sprintf("%s-%s score %d", compactDateTime(window.Start), compactClock(window.End), window.Score))
+	}
+	return fmt.Sprintf("%s %s: %s", r.Location.DisplayName(), r.Profile.Activity, strings.Join(entries, "; "))
+}
+
+// compactDateTime shortens an RFC 3339 time to its date and 24-hour clock
+func compactDateTime(value string) string {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t.Format("2006-01-02 15:04")
+	}
+	return value
+}
+
+// CompactSummary renders the forecast as one line of semicolon-separated days, parts of days,
+// slots or NWS periods, whichever the report holds
+func (r ForecastReport) CompactSummary() string {
+	var entries []string
+	switch {
+	case len(r.Periods) > 0:
+		for _, period := range r.Periods {
+			entries = append(entries, fmt.Sprintf("%s %.0f%s %s", period.Name, period.Temperature, r.Units.Temperature, period.Condition))
+		}
+	case len(r.Summaries) > 0:
+		for _, summary := range r.Summaries {
+			entries = append(entries, fmt.Sprintf("%s %.0f-%.0f%s %s", summary.Label, summary.TempMin, summary.TempMax, r.Units.Temperature, summary.Condition))
+		}
+	case len(r.Slots) > 0:
+		for _, slot := range r.Slots {
+			label := slot.Time
+			if t, err := time.Parse(time.RFC3339, slot.Time); err == nil {
+				label = t.Format("Mon 3PM")
+			}
+			entries = append(entries, fmt.Sprintf("%s %.0f%s %s", label, slot.Temperature, r.Units.Temperature, slot.Condition))
+		}
+	case r.Message != "":
+		entries = append(entries, r.Message)
+	default:
+		entries = append(entries, "no forecast data")
+	}
+	return fmt.Sprintf("%s: %s", r.Location.DisplayName(), strings.Join(entries, "; "))
+}
+
+// NewWeatherReport converts OpenWeatherMap current conditions (fetched in metric) into a report in units
+func NewWeatherReport(data WeatherData, originalLocation string, units string) WeatherReport {
+	system := getUnitSystem(units)
+	loc := offsetLocation(data.Timezone)
+
+	report := WeatherReport{
+		Provider: providerOpenWeatherMap,
+		Location: ReportLocation{
+			Name:      data.Name,
+			Country:   data.Sys.Country,
+			Lat:       data.Coord.Lat,
+			Lon:       data.Coord.Lon,
+			Timezone:  loc.String(),
+			Requested: originalLocation,
+		},
+		Units:         system,
+		Temperature:   reportValue(system.ConvertTemperature(data.Main.Temp)),
+		FeelsLike:     reportValue(system.ConvertTemperature(data.Main.FeelsLike)),
+		TempMin:       reportValue(system.ConvertTemperature(data.Main.TempMin)),
+		TempMax:       reportValue(system.ConvertTemperature(data.Main.TempMax)),
+		Humidity:      reportValue(float64(data.Main.Humidity)),
+		Pressure:      reportValue(system.ConvertPressure(float64(data.Main.Pressure))),
+		WindSpeed:     reportValue(system.ConvertWindSpeed(data.Wind.Speed)),
+		WindDirection: &data.Wind.Deg,
+		Cloudiness:    &data.Clouds.All,
+		Sunrise:       reportTime(data.Sys.Sunrise, loc),
+		Sunset:        reportTime(data.Sys.Sunset, loc),
+		ObservedAt:    reportTime(data.Dt, loc),
+	}
+
+	comfort := newComfortReport(deriveMetrics(data.Main.Temp, float64(data.Main.Humidity), data.Wind.Speed), system)
+	report.Comfort = &comfort
+
+	if len(data.Weather) > 0 {
+		report.Condition = toTitle(data.Weather[0].Description)
+	}
+	if data.Visibility > 0 {
+		report.Visibility = reportValue(system.ConvertDistance(float64(data.Visibility)))
+	}
+	if data.Main.GrndLevel > 0 {
+		report.GroundPressure = reportValue(system.ConvertPressure(float64(data.Main.GrndLevel)))
+	}
+	if data.Wind.Gust > 0 {
+		report.WindGust = reportValue(system.ConvertWindSpeed(data.Wind.Gust))
+	}
+	if data.Rain != nil {
+		report.RainLastHour = reportValue(system.ConvertPrecipitation(data.Rain.OneH))
+	}
+	if data.Snow != nil {
+		report.SnowLastHour = reportValue(system.ConvertPrecipitation(data.Snow.OneH))
+	}
+
+	return report
+}
+
+// NewForecastReport converts the part of an OpenWeatherMap forecast selected by opts into a report in units
+func NewForecastReport(data ForecastData, originalLocation string, units string, opts ForecastOptions) ForecastReport {
+	system := getUnitSystem(units)
+	selection := selectForecast(data, opts)
+
+	report := ForecastReport{
+		Provider: providerOpenWeatherMap,
+		Location: ReportLocation{
+			Name:      data.City.Name,
+			Country:   data.City.Country,
+			Lat:       data.City.Coord.Lat,
+			Lon:       data.City.Coord.Lon,
+			Timezone:  selection.Location.String(),
+			Requested: originalLocation,
+		},
+		Units:       system,
+		Granularity: opts.Granularity,
+		Message:     selection.Empty,
+	}
+
+	for _, item := range selection.Slots {
+		slot := ForecastSlot{
+			Time:                reportTime(item.Dt, selection.Location),
+			Temperature:         roundReportValue(system.ConvertTemperature(item.Main.Temp)),
+			FeelsLike:           roundReportValue(system.ConvertTemperature(item.Main.FeelsLike)),
+			PrecipitationChance: roundReportValue(item.Pop * 100),
+			WindSpeed:           roundReportValue(system.ConvertWindSpeed(item.Wind.Speed)),
+			WindDirection:       item.Wind.Deg,
+			Humidity:            item.Main.Humidity,
+			Pressure:            roundReportValue(system.ConvertPressure(float64(item.Main.Pressure))),
+			Cloudiness:          item.Clouds.All,
+			Comfort:             newComfortReport(item.Metrics(), system),
+		}
+		if len(item.Weather) > 0 {
+			slot.Condition = toTitle(item.Weather[0].Description)
+		}
+		if item.Main.GrndLevel > 0 {
+			slot.GroundPressure = reportValue(system.ConvertPressure(float64(item.Main.GrndLevel)))
+		}
+		if item.Wind.Gust > 0 {
+			slot.WindGust = reportValue(system.ConvertWindSpeed(item.Wind.Gust))
+		}
+		if item.Visibility > 0 {
+			slot.Visibility = reportValue(system.ConvertDistance(float64(item.Visibility)))
+		}
+		if item.Rain != nil {
+			slot.Rain = reportValue(system.ConvertPrecipitation(item.Rain.ThreeH))
+		}
+		if item.Snow != nil {
+			slot.Snow = reportValue(system.ConvertPrecipitation(item.Snow.ThreeH))
+		}
+		report.Slots = append(report.Slots, slot)
+	}
+
+	for _, group := range selection.Groups {
+		stats := summarizeForecastItems(group.Items)
+		report.Summaries = append(report.Summaries, ForecastSummary{
+			Label:               group.Label,
+			Date:                group.Date,
+			Part:                group.Part,
+			TempMin:             roundReportValue(system.ConvertTemperature(stats.MinTemp)),
+			TempMax:             roundReportValue(system.ConvertTemperature(stats.MaxTemp)),
+			FeelsLikeMin:        roundReportValue(system.ConvertTemperature(stats.MinFeelsLike)),
+			FeelsLikeMax:        roundReportValue(system.ConvertTemperature(stats.MaxFeelsLike)),
+			Condition:           stats.Condition,
+			PrecipitationChance: roundReportValue(stats.MaxPop * 100),
+			MaxWindSpeed:        roundReportValue(system.ConvertWindSpeed(stats.MaxWind)),
+			Humidity:            roundReportValue(stats.Humidity),
+			Pressure:            roundReportValue(system.ConvertPressure(stats.Pressure)),
+			Cloudiness:          roundReportValue(stats.Clouds),
+			PressureChange:      roundReportValue(system.ConvertPressure(stats.PressureChange)),
+			PressureTrend:       pressureTrend(stats.PressureChange, stats.Hours),
+			Rain:                roundReportValue(system.ConvertPrecipitation(stats.Rain)),
+			Snow:                roundReportValue(system.ConvertPrecipitation(stats.Snow)),
+			MaxWindGust:         roundReportValue(system.ConvertWindSpeed(stats.MaxGust)),
+			MinVisibility:       roundReportValue(system.ConvertDistance(stats.MinVisibility)),
+			DewPointMax:         roundReportValue(system.ConvertTemperature(stats.MaxDewPoint)),
+			ApparentMin:         roundReportValue(system.ConvertTemperature(stats.MinApparent)),
+			ApparentMax:         roundReportValue(system.ConvertTemperature(stats.MaxApparent)),
+			BeaufortMax:         beaufortForce(stats.MaxWind),
+		})
+	}
+
+	return report
+}
+
+// NewNWSWeatherReport converts an NWS station observation into a report in units
+func NewNWSWeatherReport(point NWSPointData, observation NWSObservationData, originalLocation string, units string) WeatherReport {
+	system := getUnitSystem(units)
+	props := observation.Properties
+
+	report := WeatherReport{
+		Provider:   providerNWS,
+		Location:   nwsReportLocation(point, originalLocation),
+		Units:      system,
+		ObservedAt: props.Timestamp,
+		Condition:  props.TextDescription,
+		Station:    props.StationName,
+	}
+
+	if temp := props.Temperature.Value; temp != nil {
+		report.Temperature = reportValue(system.ConvertTemperature(*temp))
+	}
+	if humidity := props.RelativeHumidity.Value; humidity != nil {
+		report.Humidity = reportValue(*humidity)
+	}
+	if pressure := props.BarometricPressure.Value; pressure != nil {
+		report.Pressure = reportValue(system.ConvertPressure(*pressure / 100))
+	}
+	if speed := props.WindSpeed.Value; speed != nil {
+		report.WindSpeed = reportValue(system.ConvertWindSpeed(kmhToMetersPerSecond(*speed)))
+	}
+	if gust := props.WindGust.Value; gust != nil {
+		report.WindGust = reportValue(system.ConvertWindSpeed(kmhToMetersPerSecond(*gust)))
+	}
+	if dir := props.WindDirection.Value; dir != nil {
+		degrees := int(*dir)
+		report.WindDirection = &degrees
+	}
+	if visibility := props.Visibility.Value; visibility != nil {
+		report.Visibility = reportValue(system.ConvertDistance(*visibility))
+	}
+	if metrics, ok := nwsDerivedMetrics(props); ok {
+		comfort := newComfortReport(metrics, system)
+		report.Comfort = &comfort
+	}
+
+	return report
+}
+
+// NewNWSForecastReport converts NWS forecast periods into a report in units
+func NewNWSForecastReport(point NWSPointData, forecast NWSForecastData, originalLocation string, units string) ForecastReport {
+	system := getUnitSystem(units)
+
+	report := ForecastReport{
+		Provider: providerNWS,
+		Location: nwsReportLocation(point, originalLocation),
+		Units:    system,
+		Periods:  []ForecastPeriod{},
+	}
+
+	for _, period := range forecast.Properties.Periods {
+		celsius := period.Temperature
+		if period.TemperatureUnit == "F" {
+			celsius = fahrenheitToCelsius(period.Temperature)
+		}
+
+		reportPeriod := ForecastPeriod{
+			Name:        period.Name,
+			Start:       period.StartTime,
+			End:         period.EndTime,
+			IsDaytime:   period.IsDaytime,
+			Temperature: math.Round(system.ConvertTemperature(celsius)),
+			Condition:   period.ShortForecast,
+			Detailed:    period.DetailedForecast,
+		}
+		if period.WindSpeed != "" {
+			reportPeriod.Wind = period.WindDirection + " " + period.WindSpeed
+		}
+		if pop := period.ProbabilityOfPrecipitation.Value; pop != nil {
+			reportPeriod.PrecipitationChance = pop
+		}
+		report.Periods = append(report.Periods, reportPeriod)
+	}
+
+	return report
+}
+
+// NewAirQualityReport converts current air pollution, and the forecast if fetched, into a report
+// with times in loc
+func NewAirQualityReport(current AirPollutionData, forecast *AirPollutionData, locationName string, loc *time.Location) AirQualityReport {
+	now := current.List[0]
+	level := aqiLevel(now.Main.AQI)
+
+	report := AirQualityReport{
+		Provider: providerOpenWeatherMap,
+		Location: ReportLocation{
+			Name:     locationName,
+			Lat:      current.Coord.Lat,
+			Lon:      current.Coord.Lon,
+			Timezone: loc.String(),
+		},
+		MeasuredAt:    reportTime(now.Dt, loc),
+		AQI:           level,
+		Category:      aqiCategories[level],
+		Guidance:      aqiGuidance[level],
+		MainPollutant: mainPollutant(now.Components).Name,
+		Pollutants:    make([]PollutantReading, 0, len(pollutants)),
+	}
+
+	for _, p := range pollutants {
+		value := p.Value(now.Components)
+		report.Pollutants = append(report.Pollutants, PollutantReading{
+			Code:          p.Code,
+			Name:          p.Name,
+			Concentration: roundReportValue(value),
+			Level:         p.Level(value),
+			Category:      aqiCategories[p.Level(value)],
+		})
+	}
+
+	if forecast != nil {
+		for _, day := range summarizeAirQualityForecast(forecast.List, loc) {
+			report.Forecast = append(report.Forecast, AirQualityDay{
+				Date:             day.Date.Format(time.DateOnly),
+				AQIMin:           aqiLevel(day.MinAQI),
+				AQIMax:           aqiLevel(day.MaxAQI),
+				Category:         aqiCategories[aqiLevel(day.MaxAQI)],
+				MainPollutant:    day.Main.Name,
+				MaxConcentration: roundReportValue(day.Peak),
+			})
+		}
+	}
+
+	return report
+}
+
+// NewHistoryReport converts an Open-Meteo archive response (fetched in metric) into a report in units
+func NewHistoryReport(data OpenMeteoArchiveData, locationName string, units string) HistoryReport {
+	system := getUnitSystem(units)
+	loc := data.Location()
+
+	report := HistoryReport{
+		Provider: providerOpenMeteo,
+		Location: ReportLocation{
+			Name:     locationName,
+			Lat:      data.Latitude,
+			Lon:      data.Longitude,
+			Timezone: loc.String(),
+		},
+		Units: system,
+		Days:  make([]HistoryDay, 0, len(data.Daily.Time)),
+	}
+
+	if n := len(data.Daily.Time); n > 0 {
+		report.StartDate, report.EndDate = data.Daily.Time[0], data.Daily.Time[n-1]
+	}
+
+	for i, date := range data.Daily.Time {
+		day := HistoryDay{
+			Date:          date,
+			TempMin:       historyReportValue(historyValue(data.Daily.TempMin, i), system.ConvertTemperature),
+			TempMax:       historyReportValue(historyValue(data.Daily.TempMax, i), system.ConvertTemperature),
+			TempMean:      historyReportValue(historyValue(data.Daily.TempMean, i), system.ConvertTemperature),
+			Precipitation: historyReportValue(historyValue(data.Daily.Precipitation, i), system.ConvertPrecipitation),
+			WindSpeedMax:  historyReportValue(historyValue(data.Daily.WindSpeedMax, i), system.ConvertWindSpeed),
+			WindGustMax:   historyReportValue(historyValue(data.Daily.WindGustMax, i), system.ConvertWindSpeed),
+		}
+		if code := historyValue(data.Daily.WeatherCode, i); code != nil {
+			day.Condition = wmoCondition(*code)
+		}
+		report.Days = append(report.Days, day)
+	}
+
+	for i, value := range data.Hourly.Time {
+		timestamp, err := time.ParseInLocation("2006-01-02T15:04", value, loc)
+		if err != nil {
+			continue
+		}
+		hour := HistoryHour{
+			Time:          timestamp.Format(time.RFC3339),
+			Temperature:   historyReportValue(historyValue(data.Hourly.Temperature, i), system.ConvertTemperature),
+			Humidity:      historyReportValue(historyValue(data.Hourly.Humidity, i), nil),
+			Precipitation: historyReportValue(historyValue(data.Hourly.Precipitation, i), system.ConvertPrecipitation),
+			WindSpeed:     historyReportValue(historyValue(data.Hourly.WindSpeed, i), system.ConvertWindSpeed),
+		}
+		if code := historyValue(data.Hourly.WeatherCode, i); code != nil {
+			hour.Condition = wmoCondition(*code)
+		}
+		report.Hours = append(report.Hours, hour)
+	}
+
+	return report
+}
+
+// NewComparisonReport converts compared locations (metric values) into a report in units
+func NewComparisonReport(entries []comparisonEntry, metrics []comparisonMetric, units string) ComparisonReport {
+	system := getUnitSystem(units)
+	kinds := make(map[string]comparisonMetric, len(metrics))
+	for _, metric := range metrics {
+		kinds[metric.Key] = metric
+	}
+
+	report := ComparisonReport{
+		Units:     system,
+		Locations: make([]ComparedLocation, 0, len(entries)),
+		Rankings:  []ComparisonRanking{},
+	}
+
+	for _, entry := range entries {
+		if report.Date == "" {
+			report.Date = entry.Date
+		}
+		compared := ComparedLocation{Requested: entry.Requested, Error: entry.Err}
+		if entry.Err == "" {
+			location := entry.Location
+			compared.Location = &location
+			compared.Condition = entry.Condition
+			compared.Values = make(map[string]float64, len(entry.Values))
+			for key, value := range entry.Values {
+				compared.Values[key] = roundReportValue(kinds[key].convert(system, value))
+			}
+		}
+		report.Locations = append(report.Locations, compared)
+	}
+
+	for _, ranking := range rankComparison(entries, metrics) {
+		key := ranking.Metric.Key
+		report.Rankings = append(report.Rankings, ComparisonRanking{
+			Metric:     key,
+			Best:       ranking.Best.Requested,
+			BestValue:  roundReportValue(ranking.Metric.convert(system, ranking.Best.Values[key])),
+			Worst:      ranking.Worst.Requested,
+			WorstValue: roundReportValue(ranking.Metric.convert(system, ranking.Worst.Values[key])),
+		})
+	}
+
+	return report
+}
+
+// NewAstronomyReport converts the sun and moon events of a day into a report
+func NewAstronomyReport(location ReportLocation, date time.Time, sun sunDay, moon moonDay) AstronomyReport {
+	report := AstronomyReport{
+		Location: location,
+		Date:     date.Format(time.DateOnly),
+		Sun: SunReport{
+			SolarNoon:            sun.Noon.Format(time.RFC3339),
+			DayLengthMinutes:     int(sun.DayLength().Round(time.Minute).Minutes()),
+			UpAllDay:             sun.Horizon.AlwaysAbove,
+			DownAllDay:           sun.Horizon.AlwaysBelow,
+			CivilTwilight:        newTwilightReport(sun.Civil),
+			NauticalTwilight:     newTwilightReport(sun.Nautical),
+			AstronomicalTwilight: newTwilightReport(sun.Astronomical),
+		},
+		Moon: MoonReport{
+			Phase:        roundReportValue(moon.Phase),
+			PhaseName:    moon.PhaseName(),
+			Age:          roundReportValue(moon.Age()),
+			Illumination: roundReportValue(moon.Illumination * 100),
+			UpAllDay:     moon.AlwaysUp,
+			DownAllDay:   moon.AlwaysDown,
+		},
+	}
+	if !sun.Horizon.Rise.IsZero() {
+		report.Sun.Sunrise = sun.Horizon.Rise.Format(time.RFC3339)
+		report.Sun.Sunset = sun.Horizon.Set.Format(time.RFC3339)
+	}
+	if !moon.Rise.IsZero() {
+		report.Moon.Moonrise = moon.Rise.Format(time.RFC3339)
+	}
+	if !moon.Set.IsZero() {
+		report.Moon.Moonset = moon.Set.Format(time.RFC3339)
+	}
+	return report
+}
+
+func newTwilightReport(crossing horizonCrossing) TwilightReport {
+	report := TwilightReport{AllNight: crossing.AlwaysAbove, None: crossing.AlwaysBelow}
+	if !crossing.Rise.IsZero() {
+		report.Dawn = crossing.Rise.Format(time.RFC3339)
+		report.Dusk = crossing.Set.Format(time.RFC3339)
+	}
+	return report
+}
+
+// NewSuitabilityReport converts a suitability assessment into a report in units
+func NewSuitabilityReport(assessment suitabilityAssessment, data ForecastData, originalLocation string, units string) SuitabilityReport {
+	system := getUnitSystem(units)
+	profile := assessment.Profile
+
+	report := SuitabilityReport{
+		Location: ReportLocation{
+			Name:      data.City.Name,
+			Country:   data.City.Country,
+			Lat:       data.City.Coord.Lat,
+			Lon:       data.City.Coord.Lon,
+			Timezone:  assessment.Location.String(),
+			Requested: originalLocation,
+		},
+		Units: system,
+		Profile: ProfileReport{
+			Activity:               profile.Name,
+			MinTemp:                roundReportValue(system.ConvertTemperature(profile.MinTemp)),
+			MaxTemp:                roundReportValue(system.ConvertTemperature(profile.MaxTemp)),
+			MaxWind:                roundReportValue(system.ConvertWindSpeed(profile.MaxWind)),
+			MaxPrecipitationChance: profile.MaxPrecipitationChance,
+			Daylight:               profile.Daylight,
+			DurationHours:          profile.Duration.Hours(),
+		},
+		Windows: []WindowReport{},
+		Slots:   make([]SlotSuitability, 0, len(assessment.Slots)),
+	}
+
+	for i, window := range assessment.Windows {
+		if i == maxSuitabilityWindows {
+			break
+		}
+		stats := summarizeForecastItems(windowItems(window))
+		report.Windows = append(report.Windows, WindowReport{
+			Start:                  window.Start().Format(time.RFC3339),
+			End:                    window.End().Format(time.RFC3339),
+			Hours:                  window.Duration().Hours(),
+			Score:                  window.Score(),
+			TempMin:                roundReportValue(system.ConvertTemperature(stats.MinTemp)),
+			TempMax:                roundReportValue(system.ConvertTemperature(stats.MaxTemp)),
+			MaxWindSpeed:           roundReportValue(system.ConvertWindSpeed(stats.MaxWind)),
+			MaxPrecipitationChance: roundReportValue(stats.MaxPop * 100),
+		})
+	}
+
+	for _, slot := range assessment.Slots {
+		report.Slots = append(report.Slots, SlotSuitability{
+			Time:     slot.Start.Format(time.RFC3339),
+			Score:    slot.Score,
+			Suitable: slot.Suitable(),
+			Daylight: slot.Daylight,
+			Limits:   slot.Limits,
+		})
+	}
+
+	return report
+}
+
+// historyReportValue converts and rounds an archive value, keeping nulls as missing
+func historyReportValue(v *float64, convert func(float64) float64) *float64 {
+	if v == nil {
+		return nil
+	}
+	if convert == nil {
+		return reportValue(*v)
+	}
+	return reportValue(convert(*v))
+}
+
+// newComfortReport converts derived metrics (°C) into a report in the unit system
+func newComfortReport(metrics derivedMetrics, system UnitSystem) ComfortReport {
+	report := ComfortReport{
+		DewPoint:            roundReportValue(system.ConvertTemperature(metrics.DewPoint)),
+		ApparentTemperature: roundReportValue(system.ConvertTemperature(metrics.Apparent)),
+		ApparentCategory:    metrics.Category,
+		HumidityComfort:     metrics.Comfort,
+		Beaufort:            metrics.Beaufort,
+		BeaufortDescription: metrics.BeaufortName(),
+	}
+	if metrics.HeatIndex != nil {
+		report.HeatIndex = reportValue(system.ConvertTemperature(*metrics.HeatIndex))
+	}
+	if metrics.WindChill != nil {
+		report.WindChill = reportValue(system.ConvertTemperature(*metrics.WindChill))
+	}
+	return report
+}
+
+func nwsReportLocation(point NWSPointData, originalLocation string) ReportLocation {
+	location := ReportLocation{
+		Name:      nwsPointName(point),
+		Country:   "US",
+		Timezone:  point.Properties.TimeZone,
+		Requested: originalLocation,
+	}
+	if coords := point.Geometry.Coordinates; len(coords) == 2 {
+		location.Lat, location.Lon = coords[1], coords[0]
+	}
+	return location
+}
+
+// roundReportValue keeps two decimals, enough for every unit we report
+func roundReportValue(v float64) float64 {
+	return math.Round(v*100) / 100
+}
+
+func reportValue(v float64) *float64 {
+	rounded := roundReportValue(v)
+	return &rounded
+}
+
+// reportTime renders a Unix timestamp as RFC 3339 in the location's zone, or "" when it is missing
+func reportTime(unix int64, loc *time.Location) string {
+	if unix <= 0 {
+		return ""
+	}
+	return time.Unix(unix, 0).In(loc).Format(time.RFC3339)
+}
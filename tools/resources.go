@@ -0,0 +1,140 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const (
+	mimeTypeJSON     = "application/json"
+	mimeTypeMarkdown = "text/markdown"
+)
+
+type CurrentWeatherResource struct {
+	Template mcp.ResourceTemplate
+	Handler  func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error)
+}
+
+type ForecastResource struct {
+	Template mcp.ResourceTemplate
+	Handler  func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error)
+}
+
+type IPResource struct {
+	Template mcp.ResourceTemplate
+	Handler  func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error)
+}
+
+func NewCurrentWeatherResource() *CurrentWeatherResource {
+	return &CurrentWeatherResource{
+		Template: currentWeatherResourceTemplate(),
+		Handler:  currentWeatherResourceHandler,
+	}
+}
+
+func NewForecastResource() *ForecastResource {
+	return &ForecastResource{
+		Template: forecastResourceTemplate(),
+		Handler:  forecastResourceHandler,
+	}
+}
+
+func NewIPResource() *IPResource {
+	return &IPResource{
+		Template: ipResourceTemplate(),
+		Handler:  ipResourceHandler,
+	}
+}
+
+func currentWeatherResourceTemplate() mcp.ResourceTemplate {
+	return mcp.NewResourceTemplate("weather://current/{location}", "Current weather",
+		mcp.WithTemplateDescription("Current weather conditions for a city name (e.g., 'London' or 'New York,US') or coordinates (e.g., '40.7128,-74.0060'), as JSON and Markdown. Requires OPENWEATHER_API_KEY."),
+	)
+}
+
+func forecastResourceTemplate() mcp.ResourceTemplate {
+	return mcp.NewResourceTemplate("weather://forecast/{location}", "Weather forecast",
+		mcp.WithTemplateDescription("The next 24 hours in 3-hour slots and 5 daily summaries for a city name or coordinates, as JSON and Markdown. Requires OPENWEATHER_API_KEY."),
+	)
+}
+
+func ipResourceTemplate() mcp.ResourceTemplate {
+	return mcp.NewResourceTemplate("ip://{address}", "IP address information",
+		mcp.WithTemplateDescription("Geolocation and network information for an IPv4 or IPv6 address, as JSON and Markdown"),
+	)
+}
+
+func currentWeatherResourceHandler(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	return readToolResource(ctx, request, "location", weatherToolHandler)
+}
+
+func forecastResourceHandler(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	return readToolResource(ctx, request, "location", weatherForecastToolHandler)
+}
+
+func ipResourceHandler(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	address := templateArgument(request, "address")
+	if net.ParseIP(address) == nil {
+		return nil, fmt.Errorf("invalid IP address %q", address)
+	}
+
+	ipData, err := FetchIPData(ctx, address)
+	if err != nil {
+		return nil, err
+	}
+
+	return resourceContents(request.Params.URI, FormatIPDataAsMarkdown(*ipData), *ipData)
+}
+
+// readToolResource reads a resource by calling a tool handler with the template variable as its
+// argument, so resources and tools share validation, location resolution and formatting
+func readToolResource(ctx context.Context, request mcp.ReadResourceRequest, name string,
+	handler func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error)) ([]mcp.ResourceContents, error) {
+	value := templateArgument(request, name)
+	if value == "" {
+		return nil, fmt.Errorf("%s is required", name)
+	}
+
+	toolRequest := mcp.CallToolRequest{Header: request.Header, Params: mcp.CallToolParams{Arguments: map[string]any{name: value}}}
+	result, err := handler(ctx, toolRequest)
+	if err != nil {
+		return nil, err
+	}
+	if result.IsError {
+		return nil, fmt.Errorf("%s", resultText(result))
+	}
+
+	return resourceContents(request.Params.URI, resultText(result), result.StructuredContent)
+}
+
+// templateArgument returns a variable matched from the resource URI. Values are already
+// percent-decoded; an unencoded comma, as in coordinates, splits the value into a list, which is
+// joined back together.
+func templateArgument(request mcp.ReadResourceRequest, name string) string {
+	switch value := request.Params.Arguments[name].(type) {
+	case string:
+		return strings.TrimSpace(value)
+	case []string:
+		return strings.TrimSpace(strings.Join(value, ","))
+	default:
+		return ""
+	}
+}
+
+// resourceContents returns a resource as its structured JSON followed by its Markdown rendering
+func resourceContents(uri string, markdown string, structured any) ([]mcp.ResourceContents, error) {
+	encoded, err := json.MarshalIndent(structured, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode resource as JSON: %v", err)
+	}
+
+	return []mcp.ResourceContents{
+		mcp.TextResourceContents{URI: uri, MIMEType: mimeTypeJSON, Text: string(encoded)},
+		mcp.TextResourceContents{URI: uri, MIMEType: mimeTypeMarkdown, Text: markdown},
+	}, nil
+}
@@ -0,0 +1,90 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// readResource reads a URI through an MCP server with the resource templates registered, so the
+// URI is matched against the templates the way a client request would be
+func readResource(t *testing.T, uri string) (*mcp.ReadResourceResult, string) {
+	t.Helper()
+
+	s := server.NewMCPServer("test", "1.0.0", server.WithResourceCapabilities(false, false))
+	currentWeather := NewCurrentWeatherResource()
+	forecast := NewForecastResource()
+	ip := NewIPResource()
+	s.AddResourceTemplate(currentWeather.Template, currentWeather.Handler)
+	s.AddResourceTemplate(forecast.Template, forecast.Handler)
+	s.AddResourceTemplate(ip.Template, ip.Handler)
+
+	message := fmt.Sprintf(`{"jsonrpc":"2.0","id":1,"method":"resources/read","params":{"uri":%q}}`, uri)
+	ctx := context.WithValue(context.Background(), ClientIPKey, "8.8.8.8")
+	switch response := s.HandleMessage(ctx, json.RawMessage(message)).(type) {
+	case mcp.JSONRPCResponse:
+		result, ok := response.Result.(mcp.ReadResourceResult)
+		if !ok {
+			t.Fatalf("Unexpected result type %T", response.Result)
+		}
+		return &result, ""
+	case mcp.JSONRPCError:
+		return nil, response.Error.Message
+	default:
+		t.Fatalf("Unexpected response type %T", response)
+		return nil, ""
+	}
+}
+
+func TestWeatherResources(t *testing.T) {
+	os.Setenv("OPENWEATHER_API_KEY", "test_api_key")
+	defer os.Unsetenv("OPENWEATHER_API_KEY")
+	newOpenWeatherMapTestServer(t)
+
+	testCases := []struct {
+		uri      string
+		markdown string
+		field    string
+	}{
+		{"weather://current/39.4697,-0.3763", "# Weather Information: Valencia", "temperature"},
+		{"weather://current/39.4697%2C-0.3763", "# Weather Information: Valencia", "temperature"},
+		{"weather://forecast/39.4697,-0.3763", "## Next 24 Hours", "summaries"},
+	}
+
+	for _, tc := range testCases {
+		result, errorMsg := readResource(t, tc.uri)
+		if result == nil {
+			t.Errorf("%s: expected contents, got error: %s", tc.uri, errorMsg)
+			continue
+		}
+		if len(result.Contents) != 2 {
+			t.Fatalf("%s: expected JSON and Markdown contents, got %d", tc.uri, len(result.Contents))
+		}
+
+		encoded, _ := result.Contents[0].(mcp.TextResourceContents)
+		var structured map[string]any
+		if encoded.URI != tc.uri || encoded.MIMEType != mimeTypeJSON || json.Unmarshal([]byte(encoded.Text), &structured) != nil || structured[tc.field] == nil {
+			t.Errorf("%s: expected JSON with %q, got %+v", tc.uri, tc.field, encoded)
+		}
+		markdown, _ := result.Contents[1].(mcp.TextResourceContents)
+		if markdown.MIMEType != mimeTypeMarkdown || !strings.Contains(markdown.Text, tc.markdown) {
+			t.Errorf("%s: expected Markdown with %q, got %+v", tc.uri, tc.markdown, markdown)
+		}
+	}
+
+	if _, errorMsg := readResource(t, "weather://current/91,0"); !strings.Contains(errorMsg, "coordinates out of range") {
+		t.Errorf("Expected the tool's validation error, got: %s", errorMsg)
+	}
+}
+
+func TestIPResource_InvalidAddress(t *testing.T) {
+	if _, errorMsg := readResource(t, "ip://not-an-ip"); !strings.Contains(errorMsg, `invalid IP address "not-an-ip"`) {
+		t.Errorf("Expected an invalid address error, got: %s", errorMsg)
+	}
+}
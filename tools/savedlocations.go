@@ -0,0 +1,480 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Riddlerrr/lazymcp/storage"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const (
+	maxSavedLocations = 200
+
+	// savedLocationPrefix marks a location argument as a saved name, so that a saved name never
+	// shadows the real place it is spelled like
+	savedLocationPrefix = "@"
+)
+
+// savedLocationName limits names to a letter first, then letters, digits, spaces, dots, dashes
+// and underscores
+var savedLocationName = regexp.MustCompile(`^\pL[\pL\pN ._-]{0,49}$`)
+
+// SavedLocation is a named place, geocoded once when it was saved
+type SavedLocation struct {
+	Name     string    `json:"name"`
+	Place    string    `json:"place"`
+	Query    string    `json:"query"`
+	State    string    `json:"state,omitempty"`
+	Country  string    `json:"country,omitempty"`
+	Lat      float64   `json:"lat"`
+	Lon      float64   `json:"lon"`
+	Timezone string    `json:"timezone,omitempty"`
+	SavedAt  time.Time `json:"savedAt"`
+}
+
+// resolved turns the saved location into the outcome of resolving its name
+func (l SavedLocation) resolved() *ResolvedLocation {
+	return &ResolvedLocation{
+		Query:    savedLocationPrefix + l.Name,
+		Name:     l.Place,
+		State:    l.State,
+		Country:  l.Country,
+		Lat:      l.Lat,
+		Lon:      l.Lon,
+		Timezone: l.Timezone,
+		Source:   locationSourceSaved,
+	}
+}
+
+// SavedLocationsResult is the structured form of list_saved_locations
+type SavedLocationsResult struct {
+	Locations []SavedLocation `json:"locations"`
+}
+
+// CompactSummary lists the saved names and their places on one line
+func (r SavedLocationsResult) CompactSummary() string {
+	if len(r.Locations) == 0 {
+		return "No saved locations"
+	}
+	entries := make([]string, 0, len(r.Locations))
+	for _, location := range r.Locations {
+		entries = append(entries, fmt.Sprintf("%s%s: %s", savedLocationPrefix, location.Name, location.Place))
+	}
+	return strings.Join(entries, "; ")
+}
+
+// SavedLocationResult is the structured form of the tools that change one saved location
+type SavedLocationResult struct {
+	Action   string        `json:"action" jsonschema:"enum=saved,enum=replaced,enum=renamed,enum=deleted"`
+	Location SavedLocation `json:"location"`
+}
+
+// CompactSummary names the location and what happened to it
+func (r SavedLocationResult) CompactSummary() string {
+	return fmt.Sprintf("%s %s%s: %s (%.4f,%.4f)", r.Action, savedLocationPrefix, r.Location.Name, r.Location.Place, r.Location.Lat, r.Location.Lon)
+}
+
+// savedLocationsBucketOf returns the bucket of the saved locations a request uses: the
+// principal's own when the server identifies users, otherwise those shared by every client
+func savedLocationsBucketOf(ctx context.Context) string {
+	if _, principal := requestIdentity(ctx); principal != "" {
+		return savedLocationsBucket + "/" + principal
+	}
+	return savedLocationsBucket
+}
+
+// checkSavedLocationWriter refuses changes from anonymous requests when the server identifies
+// users, so that only authenticated users can change saved locations
+func checkSavedLocationWriter(ctx context.Context) error {
+	if principal, identified := ctx.Value(PrincipalKey).(string); identified && principal == "" {
+		return fmt.Errorf("saving locations needs an authenticated user")
+	}
+	return nil
+}
+
+// getSavedLocation looks up a saved location by name, with or without the @ prefix and
+// regardless of case
+func getSavedLocation(ctx context.Context, name string) (SavedLocation, bool) {
+	var location SavedLocation
+	err := state.View(func(tx storage.Tx) error {
+		return tx.Get(savedLocationsBucketOf(ctx), savedLocationKey(name), &location)
+	})
+	if err != nil && err != storage.ErrNotFound {
+		log.Printf("Failed to read saved location %q: %v", name, err)
+	}
+	return location, err == nil
+}
+
+// listSavedLocations returns the saved locations sorted by name
+func listSavedLocations(ctx context.Context) ([]SavedLocation, error) {
+	bucket := savedLocationsBucketOf(ctx)
+	locations := []SavedLocation{}
+	err := state.View(func(tx storage.Tx) error {
+		keys, err := tx.Keys(bucket)
+		if err != nil {
+			return err
+		}
+		for _, key := range keys {
+			var location SavedLocation
+			if err := tx.Get(bucket, key, &location); err != nil {
+				return err
+			}
+			locations = append(locations, location)
+		}
+		return nil
+	})
+	return locations, err
+}
+
+// savedLocationNames returns the saved names with their @ prefix, for completion
+func savedLocationNames(ctx context.Context) []string {
+	locations, err := listSavedLocations(ctx)
+	if err != nil {
+		log.Printf("Failed to list saved locations: %v", err)
+	}
+	var names []string
+	for _, location := range locations {
+		names = append(names, savedLocationPrefix+location.Name)
+	}
+	return names
+}
+
+// putSavedLocation saves a location, replacing one of the same name only when asked to. It
+// reports whether a location was replaced.
+func putSavedLocation(ctx context.Context, location SavedLocation, replace bool) (bool, error) {
+	bucket, key := savedLocationsBucketOf(ctx), savedLocationKey(location.Name)
+	exists := false
+	err := state.Update(func(tx storage.Tx) error {
+		keys, err := tx.Keys(bucket)
+		if err != nil {
+			return err
+		}
+		exists = slices.Contains(keys, key)
+		if exists && !replace {
+			return fmt.Errorf("a location named %q is already saved; pass replace=true to overwrite it", location.Name)
+		}
+		if !exists && len(keys) >= maxSavedLocations {
+			return fmt.Errorf("saved location limit reached: at most %d locations", maxSavedLocations)
+		}
+		return tx.Put(bucket, key, location, 0)
+	})
+	return exists, err
+}
+
+func renameSavedLocation(ctx context.Context, name string, newName string) (SavedLocation, error) {
+	bucket := savedLocationsBucketOf(ctx)
+	key, newKey := savedLocationKey(name), savedLocationKey(newName)
+	var renamed SavedLocation
+	err := state.Update(func(tx storage.Tx) error {
+		if err := tx.Get(bucket, key, &renamed); err == storage.ErrNotFound {
+			return fmt.Errorf("no saved location named %q", name)
+		} else if err != nil {
+			return err
+		}
+		if newKey != key {
+			if err := tx.Get(bucket, newKey, &SavedLocation{}); err == nil {
+				return fmt.Errorf("a location named %q is already saved", newName)
+			} else if err != storage.ErrNotFound {
+				return err
+			}
+		}
+
+		renamed.Name = newName
+		if err := tx.Delete(bucket, key); err != nil {
+			return err
+		}
+		return tx.Put(bucket, newKey, renamed, 0)
+	})
+	return renamed, err
+}
+
+func removeSavedLocation(ctx context.Context, name string) (SavedLocation, error) {
+	bucket := savedLocationsBucketOf(ctx)
+	var location SavedLocation
+	err := state.Update(func(tx storage.Tx) error {
+		if err := tx.Get(bucket, savedLocationKey(name), &location); err == storage.ErrNotFound {
+			return fmt.Errorf("no saved location named %q", name)
+		} else if err != nil {
+			return err
+		}
+		return tx.Delete(bucket, savedLocationKey(name))
+	})
+	return location, err
+}
+
+// savedLocationKey is the store key of a name, which makes names case-insensitive
+func savedLocationKey(name string) string {
+	return strings.ToLower(savedLocationNameOf(name))
+}
+
+// savedLocationNameOf strips the @ prefix a name may be given with
+func savedLocationNameOf(name string) string {
+	return strings.TrimPrefix(strings.TrimSpace(name), savedLocationPrefix)
+}
+
+// validateSavedLocationName checks a name for a saved location
+func validateSavedLocationName(name string) error {
+	if !savedLocationName.MatchString(name) {
+		return fmt.Errorf("invalid name %q: use up to 50 letters, digits, spaces, dots, dashes or underscores, starting with a letter", name)
+	}
+	return nil
+}
+
+type SaveLocationTool struct {
+	Tool    mcp.Tool
+	Handler func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error)
+}
+
+type ListSavedLocationsTool struct {
+	Tool    mcp.Tool
+	Handler func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error)
+}
+
+type RenameSavedLocationTool struct {
+	Tool    mcp.Tool
+	Handler func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error)
+}
+
+type DeleteSavedLocationTool struct {
+	Tool    mcp.Tool
+	Handler func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error)
+}
+
+func NewSaveLocationTool() *SaveLocationTool {
+	return &SaveLocationTool{
+		Tool:    saveLocationTool(),
+		Handler: saveLocationToolHandler,
+	}
+}
+
+func NewListSavedLocationsTool() *ListSavedLocationsTool {
+	return &ListSavedLocationsTool{
+		Tool:    listSavedLocationsTool(),
+		Handler: listSavedLocationsToolHandler,
+	}
+}
+
+func NewRenameSavedLocationTool() *RenameSavedLocationTool {
+	return &RenameSavedLocationTool{
+		Tool:    renameSavedLocationTool(),
+		Handler: renameSavedLocationToolHandler,
+	}
+}
+
+func NewDeleteSavedLocationTool() *DeleteSavedLocationTool {
+	return &DeleteSavedLocationTool{
+		Tool:    deleteSavedLocationTool(),
+		Handler: deleteSavedLocationToolHandler,
+	}
+}
+
+func saveLocationTool() mcp.Tool {
+	return mcp.NewTool("save_location",
+		mcp.WithDescription("Save a place under a short name such as 'office' or 'home'. The place is geocoded once, and every tool that takes a location accepts the name with an @ prefix from then on, e.g. '@office'. Saved locations belong to the authenticated user, or are shared by every client when the server does not identify users."),
+		writeTool("Save Location", false, true, true),
+		mcp.WithString("name",
+			mcp.Required(),
+			mcp.Description("Name to save the place under (e.g., 'office', 'datacenter-eu'), with or without the @ prefix. Up to 50 letters, digits, spaces, dots, dashes or underscores, starting with a letter."),
+		),
+		mcp.WithString("location",
+			mcp.Required(),
+			mcp.Description("City name (e.g., 'London' or 'Springfield,MO,US') or coordinates (e.g., '40.7128,-74.0060')"),
+		),
+		mcp.WithBoolean("replace",
+			mcp.Description("Overwrite a saved location of the same name (optional, default false)"),
+		),
+		withFormat(),
+		mcp.WithOutputSchema[SavedLocationResult](),
+	)
+}
+
+func listSavedLocationsTool() mcp.Tool {
+	return mcp.NewTool("list_saved_locations",
+		mcp.WithDescription("List the saved locations with the places and coordinates they stand for"),
+		readOnlyTool("Saved Locations", false),
+		withFormat(),
+		mcp.WithOutputSchema[SavedLocationsResult](),
+	)
+}
+
+func renameSavedLocationTool() mcp.Tool {
+	return mcp.NewTool("rename_saved_location",
+		mcp.WithDescription("Give a saved location a new name"),
+		writeTool("Rename Saved Location", false, false, false),
+		mcp.WithString("name",
+			mcp.Required(),
+			mcp.Description("Current name of the saved location, with or without the @ prefix"),
+		),
+		mcp.WithString("new_name",
+			mcp.Required(),
+			mcp.Description("New name, with or without the @ prefix. Up to 50 letters, digits, spaces, dots, dashes or underscores, starting with a letter."),
+		),
+		withFormat(),
+		mcp.WithOutputSchema[SavedLocationResult](),
+	)
+}
+
+func deleteSavedLocationTool() mcp.Tool {
+	return mcp.NewTool("delete_saved_location",
+		mcp.WithDescription("Delete a saved location"),
+		writeTool("Delete Saved Location", true, true, false),
+		mcp.WithString("name",
+			mcp.Required(),
+			mcp.Description("Name of the saved location, with or without the @ prefix"),
+		),
+		withFormat(),
+		mcp.WithOutputSchema[SavedLocationResult](),
+	)
+}
+
+func saveLocationToolHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	format, err := parseFormat(request)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	if err := checkSavedLocationWriter(ctx); err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	name := savedLocationNameOf(request.GetString("name", ""))
+	if err := validateSavedLocationName(name); err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	query, err := request.RequireString("location")
+	if err != nil || strings.TrimSpace(query) == "" {
+		return mcp.NewToolResultError("location is required"), nil
+	}
+
+	resolved, err := resolveLocationInteractively(ctx, query)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	if resolved.Source == locationSourceCoordinates {
+		// Reverse geocode coordinates so the saved place has a name and country
+		if candidates, err := ReverseGeocode(ctx, resolved.Lat, resolved.Lon, 1, ""); err == nil && len(candidates) > 0 {
+			resolved.Name, resolved.State, resolved.Country = candidates[0].DisplayName(), candidates[0].State, candidates[0].Country
+		}
+	}
+
+	location := SavedLocation{
+		Name:     name,
+		Place:    resolved.Name,
+		Query:    strings.TrimSpace(query),
+		State:    resolved.State,
+		Country:  resolved.Country,
+		Lat:      resolved.Lat,
+		Lon:      resolved.Lon,
+		Timezone: resolved.Timezone,
+		SavedAt:  time.Now().UTC(),
+	}
+	replaced, err := putSavedLocation(ctx, location, request.GetBool("replace", false))
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	result := SavedLocationResult{Action: "saved", Location: location}
+	if replaced {
+		result.Action = "replaced"
+	}
+	return renderResult(format, FormatSavedLocationAsMarkdown(result)+formatAlternativesNote(resolved), result), nil
+}
+
+func listSavedLocationsToolHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	format, err := parseFormat(request)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	locations, err := listSavedLocations(ctx)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to read saved locations: %v", err)), nil
+	}
+	result := SavedLocationsResult{Locations: locations}
+	return renderResult(format, FormatSavedLocationsAsMarkdown(result.Locations), result), nil
+}
+
+func renameSavedLocationToolHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	format, err := parseFormat(request)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	if err := checkSavedLocationWriter(ctx); err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	name := savedLocationNameOf(request.GetString("name", ""))
+	newName := savedLocationNameOf(request.GetString("new_name", ""))
+	if err := validateSavedLocationName(newName); err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	location, err := renameSavedLocation(ctx, name, newName)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	result := SavedLocationResult{Action: "renamed", Location: location}
+	return renderResult(format, FormatSavedLocationAsMarkdown(result), result), nil
+}
+
+func deleteSavedLocationToolHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	format, err := parseFormat(request)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	if err := checkSavedLocationWriter(ctx); err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	location, err := removeSavedLocation(ctx, savedLocationNameOf(request.GetString("name", "")))
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	result := SavedLocationResult{Action: "deleted", Location: location}
+	return renderResult(format, FormatSavedLocationAsMarkdown(result), result), nil
+}
+
+// FormatSavedLocationAsMarkdown describes a saved location and what was done to it
+func FormatSavedLocationAsMarkdown(result SavedLocationResult) string {
+	var builder strings.Builder
+	titles := map[string]string{
+		"saved":    "Location Saved",
+		"replaced": "Location Replaced",
+		"renamed":  "Location Renamed",
+		"deleted":  "Location Deleted",
+	}
+	builder.WriteString(fmt.Sprintf("# %s: %s%s\n\n", titles[result.Action], savedLocationPrefix, result.Location.Name))
+	builder.WriteString(fmt.Sprintf("- **Place:** %s\n", result.Location.Place))
+	builder.WriteString(fmt.Sprintf("- **Coordinates:** %.4f,%.4f\n", result.Location.Lat, result.Location.Lon))
+	if result.Location.Timezone != "" {
+		builder.WriteString(fmt.Sprintf("- **Timezone:** %s\n", result.Location.Timezone))
+	}
+	if result.Action != "deleted" {
+		builder.WriteString(fmt.Sprintf("\n*Pass '%s%s' as the location of any tool to use this place.*\n", savedLocationPrefix, result.Location.Name))
+	}
+	return builder.String()
+}
+
+// FormatSavedLocationsAsMarkdown lists the saved locations as a table
+func FormatSavedLocationsAsMarkdown(locations []SavedLocation) string {
+	var builder strings.Builder
+	builder.WriteString("# Saved Locations\n\n")
+	if len(locations) == 0 {
+		builder.WriteString("No locations saved yet. Use save_location to add one.\n")
+		return builder.String()
+	}
+
+	builder.WriteString("| Name | Place | Coordinates |\n")
+	builder.WriteString("|------|-------|-------------|\n")
+	for _, location := range locations {
+		builder.WriteString(fmt.Sprintf("| %s%s | %s | %.4f,%.4f |\n", savedLocationPrefix, location.Name, location.Place, location.Lat, location.Lon))
+	}
+	return builder.String()
+}
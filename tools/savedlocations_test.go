@@ -0,0 +1,153 @@
+package tools
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+func TestSavedLocations(t *testing.T) {
+	os.Setenv("OPENWEATHER_API_KEY", "test_api_key")
+	defer os.Unsetenv("OPENWEATHER_API_KEY")
+	newOpenWeatherMapTestServer(t)
+	useMemoryState(t)
+
+	text, isError := callToolText(t, saveLocationToolHandler, map[string]any{"name": "Office", "location": "39.4697,-0.3763"})
+	if isError || !strings.Contains(text, "# Location Saved: @Office") || !strings.Contains(text, "- **Place:** Valencia, Valencian Community, ES") {
+		t.Fatalf("Expected the reverse geocoded place to be saved, got: %s", text)
+	}
+
+	// Names are matched regardless of case by every location-accepting tool
+	resolved, err := ResolveLocation(context.Background(), " @office ")
+	if err != nil || resolved.Source != locationSourceSaved || resolved.Country != "ES" || resolved.Lat != 39.4697 {
+		t.Errorf("Expected the saved location, got %+v, %v", resolved, err)
+	}
+	text, isError = callToolText(t, weatherToolHandler, map[string]any{"location": "@office"})
+	if isError || !strings.Contains(text, "# Weather Information: Valencia") {
+		t.Errorf("Expected the weather for the saved location, got: %s", text)
+	}
+
+	text, isError = callToolText(t, saveLocationToolHandler, map[string]any{"name": "OFFICE", "location": "Springfield"})
+	if !isError || !strings.Contains(text, "replace=true") {
+		t.Errorf("Expected a duplicate name to be refused, got: %s", text)
+	}
+	text, isError = callToolText(t, saveLocationToolHandler, map[string]any{"name": "@office", "location": "Springfield", "replace": true})
+	if isError || !strings.Contains(text, "# Location Replaced: @office") || !strings.Contains(text, "matches several places") {
+		t.Errorf("Expected the location to be replaced with an ambiguity note, got: %s", text)
+	}
+
+	callToolText(t, saveLocationToolHandler, map[string]any{"name": "datacenter-eu", "location": "39.4697,-0.3763"})
+	text, _ = callToolText(t, listSavedLocationsToolHandler, map[string]any{"format": "compact"})
+	if text != "@datacenter-eu: Valencia, Valencian Community, ES; @office: Springfield, Illinois, US" {
+		t.Errorf("Unexpected list: %s", text)
+	}
+
+	text, isError = callToolText(t, renameSavedLocationToolHandler, map[string]any{"name": "office", "new_name": "datacenter-eu"})
+	if !isError || !strings.Contains(text, "already saved") {
+		t.Errorf("Expected renaming onto a taken name to fail, got: %s", text)
+	}
+	text, isError = callToolText(t, renameSavedLocationToolHandler, map[string]any{"name": "office", "new_name": "hq"})
+	if isError || !strings.Contains(text, "# Location Renamed: @hq") {
+		t.Errorf("Expected the location to be renamed, got: %s", text)
+	}
+	if _, ok := getSavedLocation(context.Background(), "office"); ok {
+		t.Error("Expected the old name to be gone")
+	}
+
+	text, isError = callToolText(t, deleteSavedLocationToolHandler, map[string]any{"name": "hq"})
+	if isError || !strings.Contains(text, "# Location Deleted: @hq") {
+		t.Errorf("Expected the location to be deleted, got: %s", text)
+	}
+	text, isError = callToolText(t, deleteSavedLocationToolHandler, map[string]any{"name": "hq"})
+	if !isError || !strings.Contains(text, `no saved location named "hq"`) {
+		t.Errorf("Expected deleting a missing location to fail, got: %s", text)
+	}
+
+	if values := completeLocation(context.Background(), "", "@data"); len(values) != 1 || values[0] != "@datacenter-eu" {
+		t.Errorf("Expected saved names among location completions, got %v", values)
+	}
+	if _, err := ResolveLocation(context.Background(), "@nowhere"); err == nil || !strings.Contains(err.Error(), "no saved location") {
+		t.Errorf("Expected an unknown saved name to fail, got %v", err)
+	}
+}
+
+func TestSavedLocations_DoNotShadowPlaces(t *testing.T) {
+	os.Setenv("OPENWEATHER_API_KEY", "test_api_key")
+	defer os.Unsetenv("OPENWEATHER_API_KEY")
+	newOpenWeatherMapTestServer(t)
+	useMemoryState(t)
+
+	callToolText(t, saveLocationToolHandler, map[string]any{"name": "Springfield", "location": "39.4697,-0.3763"})
+	resolved, err := ResolveLocation(context.Background(), "Springfield")
+	if err != nil || resolved.Source == locationSourceSaved || resolved.Country != "US" {
+		t.Errorf("Expected the place name to be geocoded, got %+v, %v", resolved, err)
+	}
+	if resolved, _ := ResolveLocation(context.Background(), "@springfield"); resolved == nil || resolved.Country != "ES" {
+		t.Errorf("Expected the saved location with the prefix, got %+v", resolved)
+	}
+}
+
+func TestSavedLocations_PerPrincipal(t *testing.T) {
+	useMemoryState(t)
+	alice := context.WithValue(context.Background(), PrincipalKey, "alice")
+	bob := context.WithValue(context.Background(), PrincipalKey, "bob")
+	anonymous := context.WithValue(context.Background(), PrincipalKey, "")
+
+	call := func(ctx context.Context, handler server.ToolHandlerFunc, arguments map[string]any) *mcp.CallToolResult {
+		result, _ := handler(ctx, mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: arguments}})
+		return result
+	}
+
+	if result := call(alice, saveLocationToolHandler, map[string]any{"name": "home", "location": "51.5074,-0.1278"}); result.IsError {
+		t.Fatalf("Failed to save location: %s", resultText(result))
+	}
+	if _, ok := getSavedLocation(alice, "@home"); !ok {
+		t.Error("Expected alice to see her saved location")
+	}
+	if _, ok := getSavedLocation(bob, "@home"); ok {
+		t.Error("Expected bob not to see the saved locations of alice")
+	}
+	if _, ok := getSavedLocation(context.Background(), "@home"); ok {
+		t.Error("Expected the shared saved locations not to include those of alice")
+	}
+
+	// When the server identifies users, requests without a user cannot change saved locations
+	for _, handler := range []server.ToolHandlerFunc{saveLocationToolHandler, renameSavedLocationToolHandler, deleteSavedLocationToolHandler} {
+		result := call(anonymous, handler, map[string]any{"name": "home", "new_name": "away", "location": "51.5074,-0.1278"})
+		if !result.IsError || !strings.Contains(resultText(result), "needs an authenticated user") {
+			t.Errorf("Expected an anonymous change to be refused, got: %s", resultText(result))
+		}
+	}
+}
+
+func TestSaveLocation_InvalidName(t *testing.T) {
+	useMemoryState(t)
+
+	for _, name := range []string{"", "40.7,-74.0", "London,GB", "9lives", strings.Repeat("a", 51)} {
+		text, isError := callToolText(t, saveLocationToolHandler, map[string]any{"name": name, "location": "39.4697,-0.3763"})
+		if !isError || !strings.Contains(text, "invalid name") {
+			t.Errorf("Expected %q to be refused, got: %s", name, text)
+		}
+	}
+}
+
+func TestSavedLocations_Persistent(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.db")
+	useBoltState(t, path)
+
+	if text, isError := callToolText(t, saveLocationToolHandler, map[string]any{"name": "home", "location": "51.5074,-0.1278"}); isError {
+		t.Fatalf("Failed to save location: %s", text)
+	}
+
+	// A restart reads the state file back
+	state.Close()
+	useBoltState(t, path)
+	if location, ok := getSavedLocation(context.Background(), "home"); !ok || location.Lon != -0.1278 {
+		t.Errorf("Expected the saved location after a reload, got %+v", location)
+	}
+}
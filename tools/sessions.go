@@ -0,0 +1,126 @@
+package tools
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const (
+	// sessionIdleTTL ends sessions that sent no request for this long, as clients do not always
+	// terminate their sessions
+	sessionIdleTTL     = 24 * time.Hour
+	maxTrackedSessions = 10000
+)
+
+// SessionTracker records the sessions the server issued and, when one ends, runs the cleanups the
+// components keeping per-session state registered with OnEnd. A session ends when the client
+// terminates it with DELETE, after sessionIdleTTL without a request, or when End is called.
+type SessionTracker struct {
+	mu       sync.Mutex
+	sessions map[string]time.Time // session ID -> last request
+	onEnd    []func(sessionID string)
+}
+
+// NewSessionTracker returns a tracker that already cleans up the package's own per-session state:
+// recent completion values, session preferences and elicitation support
+func NewSessionTracker() *SessionTracker {
+	t := &SessionTracker{sessions: make(map[string]time.Time)}
+	t.OnEnd(func(sessionID string) { recent.remove(sessionID) })
+	t.OnEnd(removeSessionPreferences)
+	t.OnEnd(func(sessionID string) { elicitations.removeSession(sessionID) })
+	return t
+}
+
+// OnEnd registers a cleanup to run when a session ends
+func (t *SessionTracker) OnEnd(cleanup func(sessionID string)) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.onEnd = append(t.onEnd, cleanup)
+}
+
+// Issued reports whether the server issued a session ID that has not ended
+func (t *SessionTracker) Issued(sessionID string) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	lastRequest, ok := t.sessions[sessionID]
+	return ok && time.Since(lastRequest) <= sessionIdleTTL
+}
+
+// End ends a session and runs the registered cleanups
+func (t *SessionTracker) End(sessionID string) {
+	t.mu.Lock()
+	delete(t.sessions, sessionID)
+	cleanups := append([]func(string){}, t.onEnd...)
+	t.mu.Unlock()
+
+	for _, cleanup := range cleanups {
+		cleanup(sessionID)
+	}
+}
+
+// issue records a new session, first ending idle sessions and, at the limit, the one idle longest
+func (t *SessionTracker) issue(sessionID string) {
+	var ended []string
+	t.mu.Lock()
+	var oldestID string
+	var oldest time.Time
+	for id, lastRequest := range t.sessions {
+		if time.Since(lastRequest) > sessionIdleTTL {
+			ended = append(ended, id)
+			delete(t.sessions, id)
+		} else if oldestID == "" || lastRequest.Before(oldest) {
+			oldestID, oldest = id, lastRequest
+		}
+	}
+	if len(t.sessions) >= maxTrackedSessions {
+		ended = append(ended, oldestID)
+		delete(t.sessions, oldestID)
+	}
+	t.sessions[sessionID] = time.Now()
+	t.mu.Unlock()
+
+	for _, id := range ended {
+		t.End(id)
+	}
+}
+
+// touch records a request of an issued session
+func (t *SessionTracker) touch(sessionID string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if _, ok := t.sessions[sessionID]; ok {
+		t.sessions[sessionID] = time.Now()
+	}
+}
+
+// Middleware records the session ID mcp-go returns from initialize and ends sessions the client
+// terminates. It wraps the other middlewares, so they see the session as issued.
+func (t *SessionTracker) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sessionID := r.Header.Get(server.HeaderKeySessionID)
+		if r.Method == http.MethodDelete && sessionID != "" {
+			if t.Issued(sessionID) {
+				t.End(sessionID)
+			}
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		request := peekRPCRequest(r)
+		if sessionID != "" {
+			t.touch(sessionID)
+		}
+
+		next.ServeHTTP(w, r)
+		// The session ID is generated by mcp-go and only known from the response
+		if request != nil && request.Method == mcp.MethodInitialize {
+			if id := w.Header().Get(server.HeaderKeySessionID); id != "" {
+				t.issue(id)
+			}
+		}
+	})
+}
@@ -0,0 +1,64 @@
+package tools
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/server"
+)
+
+func TestSessionTracker_Middleware(t *testing.T) {
+	useMemoryState(t)
+	tracker := NewSessionTracker()
+	var ended []string
+	tracker.OnEnd(func(sessionID string) { ended = append(ended, sessionID) })
+
+	handler := RPCMiddleware(tracker.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.Contains(r.URL.Query().Get("body"), "initialize") {
+			w.Header().Set(server.HeaderKeySessionID, "session-a")
+		}
+	})))
+	send := func(method string, sessionID string, body string) {
+		request := httptest.NewRequest(method, "/mcp?body="+body, strings.NewReader(body))
+		if sessionID != "" {
+			request.Header.Set(server.HeaderKeySessionID, sessionID)
+		}
+		handler.ServeHTTP(httptest.NewRecorder(), request)
+	}
+
+	send(http.MethodPost, "", `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{}}`)
+	if !tracker.Issued("session-a") || tracker.Issued("made-up") {
+		t.Fatal("Expected only the session returned by initialize to be issued")
+	}
+
+	setPreferences("session-a", "", Preferences{Units: "imperial"})
+	send(http.MethodDelete, "made-up", "")
+	send(http.MethodDelete, "session-a", "")
+	if tracker.Issued("session-a") || strings.Join(ended, ",") != "session-a" {
+		t.Errorf("Expected DELETE to end only the issued session, got %v", ended)
+	}
+	if prefs, _ := getPreferences("session-a", ""); prefs != (Preferences{}) {
+		t.Errorf("Expected the session preferences to be removed, got %+v", prefs)
+	}
+}
+
+func TestSessionTracker_IdleSessions(t *testing.T) {
+	tracker := NewSessionTracker()
+	var ended []string
+	tracker.OnEnd(func(sessionID string) { ended = append(ended, sessionID) })
+
+	tracker.issue("session-a")
+	tracker.sessions["session-a"] = time.Now().Add(-sessionIdleTTL - time.Minute)
+	if tracker.Issued("session-a") {
+		t.Error("Expected an idle session not to count as issued")
+	}
+
+	// Idle sessions are ended when the next session is issued
+	tracker.issue("session-b")
+	if strings.Join(ended, ",") != "session-a" || !tracker.Issued("session-b") {
+		t.Errorf("Expected the idle session to end, got %v", ended)
+	}
+}
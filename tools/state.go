@@ -0,0 +1,32 @@
+package tools
+
+import "github.com/Riddlerrr/lazymcp/storage"
+
+// Buckets of the state store
+const (
+	principalPreferencesBucket = "principalPreferences" // principal -> Preferences
+	sessionPreferencesBucket   = "sessionPreferences"   // session ID -> sessionPreferences
+	savedLocationsBucket       = "savedLocations"       // lowercased name -> SavedLocation
+)
+
+// state keeps what outlives a session: preferences and saved locations. Per-session state that
+// only means something to the process holding the session, such as subscriptions, stays in memory.
+// It starts in memory until UseStore is called.
+var state storage.Store = storage.NewMemory()
+
+// UseStore brings store up to the current schema and keeps the server state in it from now on. It
+// returns the schema versions before and after migrating.
+func UseStore(store storage.Store) (int, int, error) {
+	from, to, err := storage.Migrate(store, stateMigrations())
+	if err != nil {
+		return 0, 0, err
+	}
+	state = store
+	return from, to, nil
+}
+
+// stateMigrations lists the schema changes of the state store, none so far. Append new migrations
+// from version 1 on; never change one that has been released.
+func stateMigrations() []storage.Migration {
+	return nil
+}
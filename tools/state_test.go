@@ -0,0 +1,51 @@
+package tools
+
+import (
+	"testing"
+
+	"github.com/Riddlerrr/lazymcp/storage"
+)
+
+// useMemoryState gives a test an empty in-memory state store
+func useMemoryState(t *testing.T) {
+	t.Helper()
+	state = storage.NewMemory()
+	t.Cleanup(func() { state = storage.NewMemory() })
+}
+
+// useBoltState keeps the state of a test in the BoltDB file at path
+func useBoltState(t *testing.T, path string) {
+	t.Helper()
+	store, err := storage.OpenBolt(path, false)
+	if err != nil {
+		t.Fatalf("Failed to open the state file: %v", err)
+	}
+	t.Cleanup(func() {
+		store.Close()
+		state = storage.NewMemory()
+	})
+	if _, _, err := UseStore(store); err != nil {
+		t.Fatalf("Failed to migrate the state file: %v", err)
+	}
+}
+
+func TestUseStore_RefusesNewerSchema(t *testing.T) {
+	useMemoryState(t)
+	store := storage.NewMemory()
+	if _, _, err := UseStore(store); err != nil {
+		t.Fatalf("Expected a new store to be accepted, got %v", err)
+	}
+
+	// A store written by a newer server is left alone
+	newer := []storage.Migration{{Version: 1, Description: "newer", Apply: func(tx storage.Tx) error { return nil }}}
+	if _, _, err := storage.Migrate(store, newer); err != nil {
+		t.Fatalf("Failed to migrate the store: %v", err)
+	}
+	previous := state
+	if _, _, err := UseStore(store); err == nil {
+		t.Error("Expected a store with a newer schema to be refused")
+	}
+	if state != previous {
+		t.Error("Expected the refused store not to be used")
+	}
+}
@@ -0,0 +1,181 @@
+package tools
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/santhosh-tekuri/jsonschema/v6"
+)
+
+// callToolStructured calls a tool handler and validates its structured content against the
+// tool's declared output schema, returning the content decoded as generic JSON
+func callToolStructured(t *testing.T, tool mcp.Tool, handler func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error), args map[string]any) map[string]any {
+	t.Helper()
+
+	ctx := context.WithValue(context.Background(), ClientIPKey, "8.8.8.8")
+	result, err := handler(ctx, mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: args}})
+	if err != nil {
+		t.Fatalf("Handler returned error: %v", err)
+	}
+	if result.IsError {
+		text, _ := mcp.AsTextContent(result.Content[0])
+		t.Fatalf("Expected success, got error: %s", text.Text)
+	}
+	if _, ok := mcp.AsTextContent(result.Content[0]); !ok {
+		t.Fatal("Expected a text rendering alongside the structured content")
+	}
+
+	return validateAgainstOutputSchema(t, tool, result.StructuredContent)
+}
+
+func validateAgainstOutputSchema(t *testing.T, tool mcp.Tool, structured any) map[string]any {
+	t.Helper()
+
+	if len(tool.RawOutputSchema) == 0 {
+		t.Fatalf("Tool %s declares no output schema", tool.Name)
+	}
+	schemaDoc, err := jsonschema.UnmarshalJSON(bytes.NewReader(tool.RawOutputSchema))
+	if err != nil {
+		t.Fatalf("Invalid output schema for %s: %v", tool.Name, err)
+	}
+
+	compiler := jsonschema.NewCompiler()
+	compiler.AssertFormat()
+	if err := compiler.AddResource("output.json", schemaDoc); err != nil {
+		t.Fatalf("Failed to add schema for %s: %v", tool.Name, err)
+	}
+	schema, err := compiler.Compile("output.json")
+	if err != nil {
+		t.Fatalf("Failed to compile schema for %s: %v", tool.Name, err)
+	}
+
+	if structured == nil {
+		t.Fatalf("Tool %s returned no structured content", tool.Name)
+	}
+	encoded, err := json.Marshal(structured)
+	if err != nil {
+		t.Fatalf("Failed to marshal structured content: %v", err)
+	}
+	instance, err := jsonschema.UnmarshalJSON(bytes.NewReader(encoded))
+	if err != nil {
+		t.Fatalf("Failed to decode structured content: %v", err)
+	}
+	if err := schema.Validate(instance); err != nil {
+		t.Fatalf("Structured content of %s does not match its output schema: %v\n%s", tool.Name, err, encoded)
+	}
+
+	var decoded map[string]any
+	if err := json.Unmarshal(encoded, &decoded); err != nil {
+		t.Fatalf("Structured content of %s is not a JSON object: %s", tool.Name, encoded)
+	}
+	return decoded
+}
+
+func TestStructuredContent_Calculator(t *testing.T) {
+	calculator := NewCalculatorTool()
+
+	testCases := []struct {
+		expression string
+		result     any
+		valueType  string
+	}{
+		{"2 + 3", float64(5), "integer"},
+		{"sqrt(16)", float64(4), "float"},
+		{"1 < 2", true, "boolean"},
+	}
+
+	for _, tc := range testCases {
+		content := callToolStructured(t, calculator.Tool, calculator.Handler, map[string]any{"expression": tc.expression})
+		if content["expression"] != tc.expression || content["result"] != tc.result || content["type"] != tc.valueType {
+			t.Errorf("calculate(%q) structured = %v, expected result %v of type %s", tc.expression, content, tc.result, tc.valueType)
+		}
+	}
+}
+
+func TestStructuredContent_IP(t *testing.T) {
+	ipTool := NewIPTool()
+	content := callToolStructured(t, ipTool.Tool, ipTool.Handler, map[string]any{})
+	if content["ip"] != "8.8.8.8" {
+		t.Errorf("Unexpected get_ip structured content: %v", content)
+	}
+
+	// get_ip_data calls ip-api.com directly, so validate a representative payload instead
+	ipData := IPData{Query: "8.8.8.8", Status: "success", Country: "United States", CountryCode: "US", City: "Ashburn", Lat: 39.03, Lon: -77.5, Timezone: "America/New_York"}
+	content = validateAgainstOutputSchema(t, NewIPDataTool().Tool, ipData)
+	if content["countryCode"] != "US" {
+		t.Errorf("Unexpected get_ip_data structured content: %v", content)
+	}
+}
+
+func TestStructuredContent_Weather(t *testing.T) {
+	os.Setenv("OPENWEATHER_API_KEY", "test_api_key")
+	defer os.Unsetenv("OPENWEATHER_API_KEY")
+	newOpenWeatherMapTestServer(t)
+	newNWSTestServer(t)
+
+	weatherTool := NewWeatherTool()
+
+	content := callToolStructured(t, weatherTool.Tool, weatherTool.Handler, map[string]any{"location": "39.4697,-0.3763", "units": "metric"})
+	if content["provider"] != "openweathermap" || content["units"].(map[string]any)["temperature"] != "°C" {
+		t.Errorf("Unexpected weather structured content: %v", content)
+	}
+	if content["sunrise"] != "2025-09-02T07:27:03+02:00" {
+		t.Errorf("Expected sunrise in local time, got %v", content["sunrise"])
+	}
+
+	content = callToolStructured(t, weatherTool.Tool, weatherTool.Handler, map[string]any{"location": "40.7128,-74.0060", "provider": "nws"})
+	if content["provider"] != "nws" || content["temperature"] != 71.96 || content["station"] != "New York City, Central Park" {
+		t.Errorf("Unexpected NWS weather structured content: %v", content)
+	}
+}
+
+func TestStructuredContent_Forecast(t *testing.T) {
+	os.Setenv("OPENWEATHER_API_KEY", "test_api_key")
+	defer os.Unsetenv("OPENWEATHER_API_KEY")
+	newOpenWeatherMapTestServer(t)
+	newNWSTestServer(t)
+
+	forecastTool := NewWeatherForecastTool()
+
+	content := callToolStructured(t, forecastTool.Tool, forecastTool.Handler, map[string]any{"location": "39.4676,-0.3771", "hours": 6})
+	slots, _ := content["slots"].([]any)
+	summaries, _ := content["summaries"].([]any)
+	if len(slots) != 2 || len(summaries) == 0 {
+		t.Errorf("Expected 2 slots and daily summaries, got %d slots and %d summaries", len(slots), len(summaries))
+	}
+
+	content = callToolStructured(t, forecastTool.Tool, forecastTool.Handler, map[string]any{"location": "39.4676,-0.3771", "granularity": "daypart", "start": "wednesday", "days": 1})
+	summaries, _ = content["summaries"].([]any)
+	if content["slots"] != nil || len(summaries) != 2 || summaries[0].(map[string]any)["part"] != "Night" {
+		t.Errorf("Expected Wednesday part-of-day summaries only, got %v", content)
+	}
+
+	content = callToolStructured(t, forecastTool.Tool, forecastTool.Handler, map[string]any{"location": "40.7128,-74.0060", "provider": "nws"})
+	periods, _ := content["periods"].([]any)
+	if len(periods) != 4 || periods[0].(map[string]any)["temperature"] != 78.0 {
+		t.Errorf("Unexpected NWS forecast periods: %v", periods)
+	}
+}
+
+func TestStructuredContent_GeocodeAndAlerts(t *testing.T) {
+	os.Unsetenv("OPENWEATHER_API_KEY")
+	newOpenWeatherMapTestServer(t)
+	newNWSTestServer(t)
+
+	geocodeTool := NewGeocodeTool()
+	content := callToolStructured(t, geocodeTool.Tool, geocodeTool.Handler, map[string]any{"query": "Valencia"})
+	candidates, _ := content["candidates"].([]any)
+	if len(candidates) != 3 || content["reverse"] != false {
+		t.Errorf("Unexpected geocode structured content: %v", content)
+	}
+
+	alertsTool := NewWeatherAlertsTool()
+	content = callToolStructured(t, alertsTool.Tool, alertsTool.Handler, map[string]any{"location": "40.7128,-74.0060"})
+	if content["count"] != 2.0 {
+		t.Errorf("Unexpected alerts structured content: %v", content)
+	}
+}
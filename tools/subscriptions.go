@@ -0,0 +1,311 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const (
+	// DefaultPollInterval matches how often OpenWeatherMap refreshes its current observations
+	DefaultPollInterval        = 10 * time.Minute
+	maxSubscriptionsPerSession = 10
+	// maxWatchedResources bounds the upstream requests of every poll, whatever the number of sessions
+	maxWatchedResources = 100
+	// maxNotificationFailures drops the subscriptions of a session mcp-go no longer knows, or that
+	// kept no GET stream open, after this many updates in a row could not be delivered
+	maxNotificationFailures = 3
+)
+
+// The resource subscription methods, which mcp-go does not route itself
+const (
+	methodResourcesSubscribe   mcp.MCPMethod = "resources/subscribe"
+	methodResourcesUnsubscribe mcp.MCPMethod = "resources/unsubscribe"
+)
+
+var errWatchLimit = fmt.Errorf("subscription limit reached: the server watches at most %d resources", maxWatchedResources)
+
+// notifier sends a notification to one client session; *server.MCPServer implements it
+type notifier interface {
+	SendNotificationToSpecificClient(sessionID string, method string, params map[string]any) error
+}
+
+// SubscriptionManager tracks which sessions watch which weather resources, polls the watched
+// resources on a schedule and notifies subscribers when a resource's data changes
+type SubscriptionManager struct {
+	notifier notifier
+	tracker  *SessionTracker
+	interval time.Duration
+	read     func(ctx context.Context, uri string) (string, error)
+
+	mu        sync.Mutex
+	sessions  map[string]map[string]bool // session ID -> subscribed URIs
+	snapshots map[string]string          // URI -> JSON of the last read
+	failures  map[string]int             // session ID -> undelivered updates in a row
+}
+
+// NewSubscriptionManager returns a manager that accepts subscriptions from the sessions tracker
+// issued and drops them when the session ends
+func NewSubscriptionManager(notifier notifier, tracker *SessionTracker, interval time.Duration) *SubscriptionManager {
+	m := &SubscriptionManager{
+		notifier:  notifier,
+		tracker:   tracker,
+		interval:  interval,
+		read:      readWeatherResourceSnapshot,
+		sessions:  make(map[string]map[string]bool),
+		snapshots: make(map[string]string),
+		failures:  make(map[string]int),
+	}
+	tracker.OnEnd(m.RemoveSession)
+	return m
+}
+
+// Subscribe adds a weather resource to a session's subscriptions. The resource is read right away,
+// so an unknown location fails here rather than in the poller, and the read becomes the snapshot
+// later polls are compared with.
+func (m *SubscriptionManager) Subscribe(ctx context.Context, sessionID string, uri string) error {
+	if sessionID == "" {
+		return fmt.Errorf("subscriptions need a session: send the %s header", server.HeaderKeySessionID)
+	}
+	if !m.tracker.Issued(sessionID) {
+		return fmt.Errorf("unknown session %q: initialize a new session", sessionID)
+	}
+
+	m.mu.Lock()
+	subscriptions := m.sessions[sessionID]
+	if subscriptions[uri] {
+		m.mu.Unlock()
+		return nil
+	}
+	if len(subscriptions) >= maxSubscriptionsPerSession {
+		m.mu.Unlock()
+		return fmt.Errorf("subscription limit reached: at most %d resources per session", maxSubscriptionsPerSession)
+	}
+	_, watched := m.snapshots[uri]
+	if !watched && len(m.snapshots) >= maxWatchedResources {
+		m.mu.Unlock()
+		return errWatchLimit
+	}
+	m.mu.Unlock()
+
+	var snapshot string
+	if !watched {
+		var err error
+		if snapshot, err = m.read(ctx, uri); err != nil {
+			return err
+		}
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.sessions[sessionID] == nil {
+		m.sessions[sessionID] = make(map[string]bool)
+	}
+	if len(m.sessions[sessionID]) >= maxSubscriptionsPerSession {
+		return fmt.Errorf("subscription limit reached: at most %d resources per session", maxSubscriptionsPerSession)
+	}
+	if _, ok := m.snapshots[uri]; !ok {
+		if len(m.snapshots) >= maxWatchedResources {
+			return errWatchLimit
+		}
+		m.snapshots[uri] = snapshot
+	}
+	m.sessions[sessionID][uri] = true
+	return nil
+}
+
+// Unsubscribe removes a resource from a session's subscriptions
+func (m *SubscriptionManager) Unsubscribe(sessionID string, uri string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.sessions[sessionID], uri)
+	if len(m.sessions[sessionID]) == 0 {
+		delete(m.sessions, sessionID)
+	}
+	m.forgetUnwatched()
+}
+
+// RemoveSession drops every subscription of a session that ended
+func (m *SubscriptionManager) RemoveSession(sessionID string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.sessions, sessionID)
+	delete(m.failures, sessionID)
+	m.forgetUnwatched()
+}
+
+// forgetUnwatched drops the snapshots of resources no session subscribes to any more. The caller
+// holds m.mu.
+func (m *SubscriptionManager) forgetUnwatched() {
+	for uri := range m.snapshots {
+		if len(m.subscribers(uri)) == 0 {
+			delete(m.snapshots, uri)
+		}
+	}
+}
+
+// subscribers lists the sessions subscribed to a resource. The caller holds m.mu.
+func (m *SubscriptionManager) subscribers(uri string) []string {
+	var sessionIDs []string
+	for sessionID, subscriptions := range m.sessions {
+		if subscriptions[uri] {
+			sessionIDs = append(sessionIDs, sessionID)
+		}
+	}
+	sort.Strings(sessionIDs)
+	return sessionIDs
+}
+
+// recordDelivery counts the updates a session missed in a row. Sessions only receive notifications
+// while they hold a GET stream open, so a few misses are tolerated before the subscriptions go.
+func (m *SubscriptionManager) recordDelivery(sessionID string, err error) {
+	m.mu.Lock()
+	if !errors.Is(err, server.ErrSessionNotFound) {
+		delete(m.failures, sessionID)
+		m.mu.Unlock()
+		if err != nil {
+			log.Printf("Failed to notify session %s: %v", sessionID, err)
+		}
+		return
+	}
+	m.failures[sessionID]++
+	drop := m.failures[sessionID] >= maxNotificationFailures
+	m.mu.Unlock()
+
+	if drop {
+		log.Printf("Dropping the subscriptions of session %s: %d updates in a row could not be delivered", sessionID, maxNotificationFailures)
+		m.RemoveSession(sessionID)
+	}
+}
+
+// Run polls the subscribed resources every interval until the context is cancelled
+func (m *SubscriptionManager) Run(ctx context.Context) {
+	ticker := time.NewTicker(m.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			m.Poll(ctx)
+		}
+	}
+}
+
+// Poll re-reads every subscribed resource once and notifies the subscribers of those whose data
+// differs from the last snapshot. A failed read keeps the previous snapshot.
+func (m *SubscriptionManager) Poll(ctx context.Context) {
+	m.mu.Lock()
+	uris := make([]string, 0, len(m.snapshots))
+	for uri := range m.snapshots {
+		uris = append(uris, uri)
+	}
+	m.mu.Unlock()
+	sort.Strings(uris)
+
+	for _, uri := range uris {
+		snapshot, err := m.read(ctx, uri)
+		if err != nil {
+			log.Printf("Failed to refresh subscribed resource %s: %v", uri, err)
+			continue
+		}
+
+		m.mu.Lock()
+		previous, ok := m.snapshots[uri]
+		if !ok || previous == snapshot {
+			m.mu.Unlock()
+			continue
+		}
+		m.snapshots[uri] = snapshot
+		sessionIDs := m.subscribers(uri)
+		m.mu.Unlock()
+
+		for _, sessionID := range sessionIDs {
+			err := m.notifier.SendNotificationToSpecificClient(sessionID, mcp.MethodNotificationResourceUpdated, map[string]any{"uri": uri})
+			m.recordDelivery(sessionID, err)
+		}
+	}
+}
+
+// Middleware answers resources/subscribe and resources/unsubscribe requests, which mcp-go does not
+// route. Everything else is passed on to the Streamable HTTP handler.
+func (m *SubscriptionManager) Middleware(contextFunc server.HTTPContextFunc, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sessionID := r.Header.Get(server.HeaderKeySessionID)
+		request := peekRPCRequest(r)
+		if request == nil || (request.Method != methodResourcesSubscribe && request.Method != methodResourcesUnsubscribe) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		var params struct {
+			URI string `json:"uri"`
+		}
+		if err := json.Unmarshal(request.Params, &params); err != nil || params.URI == "" {
+			writeRPCResponse(w, r, mcp.NewJSONRPCError(request.ID, mcp.INVALID_PARAMS, "uri is required", nil))
+			return
+		}
+
+		ctx := r.Context()
+		if contextFunc != nil {
+			ctx = contextFunc(ctx, r)
+		}
+
+		var response any = mcp.NewJSONRPCResponse(request.ID, mcp.Result{})
+		if request.Method == methodResourcesSubscribe {
+			if err := m.Subscribe(ctx, sessionID, params.URI); err != nil {
+				response = mcp.NewJSONRPCError(request.ID, mcp.INVALID_PARAMS, err.Error(), nil)
+			}
+		} else {
+			m.Unsubscribe(sessionID, params.URI)
+		}
+		writeRPCResponse(w, r, response)
+	})
+}
+
+// readWeatherResourceSnapshot reads a weather resource and returns its JSON contents, which
+// identify a change in the data regardless of the output language
+func readWeatherResourceSnapshot(ctx context.Context, uri string) (string, error) {
+	weatherResources := []struct {
+		Template mcp.ResourceTemplate
+		Handler  func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error)
+	}{
+		{currentWeatherResourceTemplate(), currentWeatherResourceHandler},
+		{forecastResourceTemplate(), forecastResourceHandler},
+	}
+
+	for _, resource := range weatherResources {
+		if !resource.Template.URITemplate.Regexp().MatchString(uri) {
+			continue
+		}
+
+		request := mcp.ReadResourceRequest{Params: mcp.ReadResourceParams{URI: uri, Arguments: map[string]any{}}}
+		for name, value := range resource.Template.URITemplate.Match(uri) {
+			request.Params.Arguments[name] = value.V
+		}
+		contents, err := resource.Handler(ctx, request)
+		if err != nil {
+			return "", err
+		}
+		for _, content := range contents {
+			if text, ok := content.(mcp.TextResourceContents); ok && text.MIMEType == mimeTypeJSON {
+				return text.Text, nil
+			}
+		}
+		return "", fmt.Errorf("resource %s has no JSON contents", uri)
+	}
+
+	return "", fmt.Errorf("resource %q does not support subscriptions: only weather://current/{location} and weather://forecast/{location} can be watched", uri)
+}
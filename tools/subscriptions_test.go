@@ -0,0 +1,219 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/server"
+)
+
+type recordingNotifier struct {
+	notifications []string
+	gone          map[string]bool // sessions mcp-go does not know
+}
+
+func (n *recordingNotifier) SendNotificationToSpecificClient(sessionID string, method string, params map[string]any) error {
+	if n.gone[sessionID] {
+		return server.ErrSessionNotFound
+	}
+	n.notifications = append(n.notifications, fmt.Sprintf("%s %s %s", sessionID, method, params["uri"]))
+	return nil
+}
+
+// newTestSubscriptionManager returns a manager whose resources read as the values in data, for
+// the issued sessions "a" and "b"
+func newTestSubscriptionManager(data map[string]string) (*SubscriptionManager, *recordingNotifier) {
+	notifier := &recordingNotifier{gone: make(map[string]bool)}
+	tracker := NewSessionTracker()
+	tracker.issue("a")
+	tracker.issue("b")
+	manager := NewSubscriptionManager(notifier, tracker, DefaultPollInterval)
+	manager.read = func(ctx context.Context, uri string) (string, error) {
+		snapshot, ok := data[uri]
+		if !ok {
+			return "", fmt.Errorf("unknown resource %s", uri)
+		}
+		return snapshot, nil
+	}
+	return manager, notifier
+}
+
+func TestSubscriptionManager_Poll(t *testing.T) {
+	data := map[string]string{"weather://current/London": "12", "weather://current/Paris": "15"}
+	manager, notifier := newTestSubscriptionManager(data)
+	ctx := context.Background()
+
+	for _, subscription := range [][2]string{{"a", "weather://current/London"}, {"b", "weather://current/London"}, {"b", "weather://current/Paris"}} {
+		if err := manager.Subscribe(ctx, subscription[0], subscription[1]); err != nil {
+			t.Fatalf("Subscribe(%s, %s) failed: %v", subscription[0], subscription[1], err)
+		}
+	}
+
+	manager.Poll(ctx)
+	if len(notifier.notifications) != 0 {
+		t.Errorf("Expected no notifications for unchanged data, got %v", notifier.notifications)
+	}
+
+	data["weather://current/London"] = "13"
+	manager.Poll(ctx)
+	expected := []string{
+		"a notifications/resources/updated weather://current/London",
+		"b notifications/resources/updated weather://current/London",
+	}
+	if strings.Join(notifier.notifications, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("Expected %v, got %v", expected, notifier.notifications)
+	}
+
+	// A failed read keeps the last snapshot and notifies no one
+	notifier.notifications = nil
+	delete(data, "weather://current/Paris")
+	manager.Unsubscribe("a", "weather://current/London")
+	manager.RemoveSession("b")
+	data["weather://current/London"] = "14"
+	manager.Poll(ctx)
+	if len(notifier.notifications) != 0 || len(manager.snapshots) != 0 {
+		t.Errorf("Expected no subscriptions left, got notifications %v and snapshots %v", notifier.notifications, manager.snapshots)
+	}
+}
+
+func TestSubscriptionManager_Subscribe(t *testing.T) {
+	data := map[string]string{}
+	for i := 0; i <= maxSubscriptionsPerSession; i++ {
+		data[fmt.Sprintf("weather://current/%d,0", i)] = "{}"
+	}
+	manager, _ := newTestSubscriptionManager(data)
+	ctx := context.Background()
+
+	if err := manager.Subscribe(ctx, "", "weather://current/0,0"); err == nil || !strings.Contains(err.Error(), "need a session") {
+		t.Errorf("Expected an error without a session, got %v", err)
+	}
+	if err := manager.Subscribe(ctx, "a", "weather://current/Atlantis"); err == nil {
+		t.Error("Expected an error for a resource that cannot be read")
+	}
+
+	for i := 0; i < maxSubscriptionsPerSession; i++ {
+		if err := manager.Subscribe(ctx, "a", fmt.Sprintf("weather://current/%d,0", i)); err != nil {
+			t.Fatalf("Subscription %d failed: %v", i, err)
+		}
+	}
+	if err := manager.Subscribe(ctx, "a", "weather://current/0,0"); err != nil {
+		t.Errorf("Subscribing twice should be a no-op, got %v", err)
+	}
+	last := fmt.Sprintf("weather://current/%d,0", maxSubscriptionsPerSession)
+	if err := manager.Subscribe(ctx, "a", last); err == nil || !strings.Contains(err.Error(), "subscription limit reached") {
+		t.Errorf("Expected the subscription limit, got %v", err)
+	}
+	if err := manager.Subscribe(ctx, "b", last); err != nil {
+		t.Errorf("The limit should apply per session, got %v", err)
+	}
+
+	if err := manager.Subscribe(ctx, "made-up", last); err == nil || !strings.Contains(err.Error(), "unknown session") {
+		t.Errorf("Expected a session the server did not issue to be refused, got %v", err)
+	}
+
+	// Ending the session drops its subscriptions
+	manager.tracker.End("a")
+	if len(manager.sessions["a"]) != 0 || len(manager.snapshots) != 1 {
+		t.Errorf("Expected only the subscription of b to be left, got %v", manager.sessions)
+	}
+}
+
+func TestSubscriptionManager_WatchLimit(t *testing.T) {
+	data := map[string]string{}
+	manager, _ := newTestSubscriptionManager(data)
+	ctx := context.Background()
+
+	for i := 0; i < maxWatchedResources; i++ {
+		uri := fmt.Sprintf("weather://current/%d,0", i)
+		data[uri] = "{}"
+		sessionID := fmt.Sprintf("session-%d", i/maxSubscriptionsPerSession)
+		manager.tracker.issue(sessionID)
+		if err := manager.Subscribe(ctx, sessionID, uri); err != nil {
+			t.Fatalf("Subscription %d failed: %v", i, err)
+		}
+	}
+
+	data["weather://current/London"] = "{}"
+	if err := manager.Subscribe(ctx, "a", "weather://current/London"); err == nil || !strings.Contains(err.Error(), "the server watches at most") {
+		t.Errorf("Expected the server-wide limit, got %v", err)
+	}
+	if err := manager.Subscribe(ctx, "a", "weather://current/0,0"); err != nil {
+		t.Errorf("Expected a watched resource to stay available, got %v", err)
+	}
+}
+
+func TestSubscriptionManager_UndeliverableSession(t *testing.T) {
+	data := map[string]string{"weather://current/London": "12"}
+	manager, notifier := newTestSubscriptionManager(data)
+	ctx := context.Background()
+	manager.Subscribe(ctx, "a", "weather://current/London")
+	manager.Subscribe(ctx, "b", "weather://current/London")
+	notifier.gone["a"] = true
+
+	for i := 0; i < maxNotificationFailures; i++ {
+		data["weather://current/London"] = fmt.Sprint(13 + i)
+		manager.Poll(ctx)
+	}
+	if len(manager.sessions["a"]) != 0 || !manager.sessions["b"]["weather://current/London"] {
+		t.Errorf("Expected only the undeliverable session to lose its subscriptions, got %v", manager.sessions)
+	}
+	if len(notifier.notifications) != maxNotificationFailures {
+		t.Errorf("Expected b to receive every update, got %v", notifier.notifications)
+	}
+}
+
+func TestSubscriptionManager_Middleware(t *testing.T) {
+	manager, _ := newTestSubscriptionManager(map[string]string{"weather://current/London": "{}"})
+	passed := 0
+	handler := RPCMiddleware(manager.Middleware(nil, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		passed++
+	})))
+
+	send := func(method string, body string) map[string]any {
+		request := httptest.NewRequest(method, "/mcp", strings.NewReader(body))
+		request.Header.Set(server.HeaderKeySessionID, "a")
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, request)
+
+		var response map[string]any
+		json.Unmarshal(recorder.Body.Bytes(), &response)
+		return response
+	}
+
+	response := send(http.MethodPost, `{"jsonrpc":"2.0","id":1,"method":"resources/subscribe","params":{"uri":"weather://current/London"}}`)
+	if response["result"] == nil || !manager.sessions["a"]["weather://current/London"] {
+		t.Errorf("Expected a successful subscription, got %v", response)
+	}
+
+	response = send(http.MethodPost, `{"jsonrpc":"2.0","id":2,"method":"resources/subscribe","params":{"uri":"weather://current/Atlantis"}}`)
+	if errorObject, _ := response["error"].(map[string]any); errorObject == nil || response["id"] != float64(2) {
+		t.Errorf("Expected an error response, got %v", response)
+	}
+
+	send(http.MethodPost, `{"jsonrpc":"2.0","id":3,"method":"resources/read","params":{"uri":"weather://current/London"}}`)
+	send(http.MethodDelete, "")
+	if passed != 2 {
+		t.Errorf("Expected other requests to pass through, got %d passed", passed)
+	}
+}
+
+func TestReadWeatherResourceSnapshot(t *testing.T) {
+	os.Setenv("OPENWEATHER_API_KEY", "test_api_key")
+	defer os.Unsetenv("OPENWEATHER_API_KEY")
+	newOpenWeatherMapTestServer(t)
+
+	snapshot, err := readWeatherResourceSnapshot(context.Background(), "weather://current/39.4697,-0.3763")
+	if err != nil || !json.Valid([]byte(snapshot)) || !strings.Contains(snapshot, `"temperature"`) {
+		t.Errorf("Expected the current weather as JSON, got %s, %v", snapshot, err)
+	}
+
+	if _, err := readWeatherResourceSnapshot(context.Background(), "ip://8.8.8.8"); err == nil || !strings.Contains(err.Error(), "does not support subscriptions") {
+		t.Errorf("Expected IP resources to be rejected, got %v", err)
+	}
+}
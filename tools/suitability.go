@@ -0,0 +1,494 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const (
+	forecastSlotDuration  = 3 * time.Hour
+	maxSuitabilityWindows = 5
+)
+
+// Score penalties: up to suitabilityRangePenalty points for each factor while it is within the
+// profile's limits, and more per unit beyond them
+const (
+	suitabilityRangePenalty    = 10.0
+	suitabilityTempPenalty     = 8.0  // per °C outside the range
+	suitabilityWindPenalty     = 10.0 // per m/s over the limit
+	suitabilityPrecipPenalty   = 1.5  // per percentage point over the limit
+	suitabilityDaylightPenalty = 50.0
+)
+
+// Reasons a slot falls outside an activity profile
+const (
+	limitTooCold       = "too cold"
+	limitTooHot        = "too hot"
+	limitTooWindy      = "too windy"
+	limitPrecipitation = "likely precipitation"
+	limitDark          = "dark"
+)
+
+// ActivityProfile holds the conditions an activity needs. Temperatures are in °C and wind in m/s,
+// like the forecast data.
+type ActivityProfile struct {
+	Name                   string
+	MinTemp                float64
+	MaxTemp                float64
+	MaxWind                float64
+	MaxPrecipitationChance float64
+	Daylight               bool
+	Duration               time.Duration
+}
+
+// activityProfiles are the built-in profiles; custom starts from broad limits for overrides to narrow
+var activityProfiles = map[string]ActivityProfile{
+	"cycling":       {Name: "cycling", MinTemp: 8, MaxTemp: 28, MaxWind: 8, MaxPrecipitationChance: 30, Daylight: true, Duration: 3 * time.Hour},
+	"running":       {Name: "running", MinTemp: 5, MaxTemp: 24, MaxWind: 10, MaxPrecipitationChance: 40, Duration: 3 * time.Hour},
+	"hiking":        {Name: "hiking", MinTemp: 5, MaxTemp: 26, MaxWind: 10, MaxPrecipitationChance: 20, Daylight: true, Duration: 6 * time.Hour},
+	"picnic":        {Name: "picnic", MinTemp: 18, MaxTemp: 30, MaxWind: 6, MaxPrecipitationChance: 20, Daylight: true, Duration: 3 * time.Hour},
+	"outdoor_event": {Name: "outdoor_event", MinTemp: 15, MaxTemp: 30, MaxWind: 8, MaxPrecipitationChance: 10, Duration: 3 * time.Hour},
+	"beach":         {Name: "beach", MinTemp: 24, MaxTemp: 35, MaxWind: 7, MaxPrecipitationChance: 10, Daylight: true, Duration: 3 * time.Hour},
+	"custom":        {Name: "custom", MinTemp: 10, MaxTemp: 25, MaxWind: 10, MaxPrecipitationChance: 30, Duration: 3 * time.Hour},
+}
+
+var activityNames = []string{"cycling", "running", "hiking", "picnic", "outdoor_event", "beach", "custom"}
+
+// profileOverrides are the limits a caller set explicitly, in the units of the request
+type profileOverrides struct {
+	MinTemp                *float64
+	MaxTemp                *float64
+	MaxWind                *float64
+	MaxPrecipitationChance *float64
+	Daylight               *bool
+	DurationHours          *float64
+}
+
+// apply returns the profile with the overrides converted from the unit system and set
+func (o profileOverrides) apply(profile ActivityProfile, system UnitSystem) ActivityProfile {
+	if o.MinTemp != nil {
+		profile.MinTemp = system.TemperatureToCelsius(*o.MinTemp)
+	}
+	if o.MaxTemp != nil {
+		profile.MaxTemp = system.TemperatureToCelsius(*o.MaxTemp)
+	}
+	if o.MaxWind != nil {
+		profile.MaxWind = system.WindSpeedToMetersPerSecond(*o.MaxWind)
+	}
+	if o.MaxPrecipitationChance != nil {
+		profile.MaxPrecipitationChance = *o.MaxPrecipitationChance
+	}
+	if o.Daylight != nil {
+		profile.Daylight = *o.Daylight
+	}
+	if o.DurationHours != nil {
+		profile.Duration = time.Duration(*o.DurationHours * float64(time.Hour))
+	}
+	return profile
+}
+
+// slotAssessment is how well one forecast slot suits a profile. Limits lists the profile limits the
+// slot breaks; it is suitable when there are none.
+type slotAssessment struct {
+	Item     ForecastItem
+	Start    time.Time
+	Score    int
+	Daylight bool
+	Limits   []string
+}
+
+func (a slotAssessment) Suitable() bool {
+	return len(a.Limits) == 0
+}
+
+// suitabilityWindow is a run of consecutive suitable slots
+type suitabilityWindow struct {
+	Slots []slotAssessment
+}
+
+func (w suitabilityWindow) Start() time.Time {
+	return w.Slots[0].Start
+}
+
+func (w suitabilityWindow) End() time.Time {
+	return w.Slots[len(w.Slots)-1].Start.Add(forecastSlotDuration)
+}
+
+func (w suitabilityWindow) Duration() time.Duration {
+	return w.End().Sub(w.Start())
+}
+
+// Score is the mean score of the window's slots
+func (w suitabilityWindow) Score() int {
+	total := 0
+	for _, slot := range w.Slots {
+		total += slot.Score
+	}
+	return int(math.Round(float64(total) / float64(len(w.Slots))))
+}
+
+// suitabilityAssessment is a whole forecast scored against a profile
+type suitabilityAssessment struct {
+	Profile  ActivityProfile
+	Location *time.Location
+	Slots    []slotAssessment
+	Windows  []suitabilityWindow
+}
+
+type WeatherSuitabilityTool struct {
+	Tool    mcp.Tool
+	Handler func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error)
+}
+
+func NewWeatherSuitabilityTool() *WeatherSuitabilityTool {
+	return &WeatherSuitabilityTool{
+		Tool:    weatherSuitabilityTool(),
+		Handler: weatherSuitabilityToolHandler,
+	}
+}
+
+func weatherSuitabilityTool() mcp.Tool {
+	return mcp.NewTool("weather_suitability",
+		mcp.WithDescription("Find the best times in the next 5 days for an outdoor activity. Scores each 3-hour forecast slot against an activity profile (temperature range, maximum wind, maximum precipitation chance, daylight) and returns the best time windows. Uses client's IP location by default, or accepts a custom location parameter (city name or 'lat,lon' coordinates)"),
+		readOnlyTool("Activity Suitability", true),
+		mcp.WithString("activity",
+			mcp.Required(),
+			mcp.Description("Activity profile: 'cycling', 'running', 'hiking', 'picnic', 'outdoor_event', 'beach', or 'custom' to start from broad limits. The limit arguments override the profile."),
+			mcp.Enum(activityNames...),
+		),
+		mcp.WithString("location",
+			mcp.Description("Location to check (optional). Can be city name (e.g., 'London' or 'New York,US'), coordinates (e.g., '40.7128,-74.0060') or a saved location (e.g., '@home'). Uses client IP location if not provided."),
+		),
+		mcp.WithNumber("min_temp",
+			mcp.Description("Lowest acceptable temperature, in the temperature unit of the units argument (optional)"),
+		),
+		mcp.WithNumber("max_temp",
+			mcp.Description("Highest acceptable temperature, in the temperature unit of the units argument (optional)"),
+		),
+		mcp.WithNumber("max_wind",
+			mcp.Description("Highest acceptable wind speed, in the wind unit of the units argument (optional)"),
+			mcp.Min(0),
+		),
+		mcp.WithNumber("max_precipitation_chance",
+			mcp.Description("Highest acceptable chance of precipitation in percent (optional)"),
+			mcp.Min(0),
+			mcp.Max(100),
+		),
+		mcp.WithBoolean("daylight",
+			mcp.Description("Only accept times between sunrise and sunset (optional)"),
+		),
+		mcp.WithNumber("duration_hours",
+			mcp.Description("Shortest window worth suggesting, in hours (optional, 3-24). Forecast slots are 3 hours long, so windows are whole slots."),
+			mcp.Min(3),
+			mcp.Max(24),
+		),
+		withUnits("Defaults to the customary units of the location's country."),
+		withLanguage(),
+		withFormat(),
+		mcp.WithOutputSchema[SuitabilityReport](),
+	)
+}
+
+func weatherSuitabilityToolHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	activity, err := request.RequireString("activity")
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	base, ok := activityProfiles[strings.ToLower(activity)]
+	if !ok {
+		return mcp.NewToolResultError(fmt.Sprintf("invalid activity %q: must be one of %s", activity, strings.Join(activityNames, ", "))), nil
+	}
+
+	overrides, err := parseProfileOverrides(request)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	// Overrides are in the request's units, which are only known once the location is resolved
+	return handleWeatherRequest(
+		ctx,
+		request,
+		buildForecastURLFromLocation,
+		func(data ForecastData, originalLocation string, units string, lang string) string {
+			assessment := assessSuitability(data, overrides.apply(base, getUnitSystem(units)))
+			return FormatSuitabilityAsMarkdown(assessment, data, originalLocation, units, lang)
+		},
+		func(data ForecastData, originalLocation string, units string) SuitabilityReport {
+			assessment := assessSuitability(data, overrides.apply(base, getUnitSystem(units)))
+			return NewSuitabilityReport(assessment, data, originalLocation, units)
+		},
+	)
+}
+
+// parseProfileOverrides reads the limit arguments, checking they describe a possible range
+func parseProfileOverrides(request mcp.CallToolRequest) (profileOverrides, error) {
+	var overrides profileOverrides
+	arguments := request.GetArguments()
+
+	number := func(name string) *float64 {
+		if _, ok := arguments[name]; !ok {
+			return nil
+		}
+		value := request.GetFloat(name, 0)
+		return &value
+	}
+	overrides.MinTemp = number("min_temp")
+	overrides.MaxTemp = number("max_temp")
+	overrides.MaxWind = number("max_wind")
+	overrides.MaxPrecipitationChance = number("max_precipitation_chance")
+	overrides.DurationHours = number("duration_hours")
+	if _, ok := arguments["daylight"]; ok {
+		daylight := request.GetBool("daylight", false)
+		overrides.Daylight = &daylight
+	}
+
+	switch {
+	case overrides.MinTemp != nil && overrides.MaxTemp != nil && *overrides.MinTemp > *overrides.MaxTemp:
+		return overrides, fmt.Errorf("min_temp %g is above max_temp %g", *overrides.MinTemp, *overrides.MaxTemp)
+	case overrides.MaxWind != nil && *overrides.MaxWind < 0:
+		return overrides, fmt.Errorf("max_wind must not be negative")
+	case overrides.MaxPrecipitationChance != nil && (*overrides.MaxPrecipitationChance < 0 || *overrides.MaxPrecipitationChance > 100):
+		return overrides, fmt.Errorf("max_precipitation_chance must be between 0 and 100")
+	case overrides.DurationHours != nil && (*overrides.DurationHours < 3 || *overrides.DurationHours > 24):
+		return overrides, fmt.Errorf("duration_hours must be between 3 and 24")
+	}
+
+	return overrides, nil
+}
+
+// assessSuitability scores every forecast slot against the profile and finds the windows of
+// consecutive suitable slots lasting at least the profile's duration, best first
+func assessSuitability(data ForecastData, profile ActivityProfile) suitabilityAssessment {
+	assessment := suitabilityAssessment{Profile: profile, Location: offsetLocation(data.City.Timezone)}
+
+	suns := map[string]sunDay{}
+	for _, item := range data.List {
+		start := time.Unix(item.Dt, 0).In(assessment.Location)
+
+		// A slot is in daylight when its midpoint falls between sunrise and sunset of its local day
+		day := start.Format(time.DateOnly)
+		sun, ok := suns[day]
+		if !ok {
+			midnight := time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, assessment.Location)
+			sun = computeSunDay(midnight, data.City.Coord.Lat, data.City.Coord.Lon)
+			suns[day] = sun
+		}
+
+		assessment.Slots = append(assessment.Slots, assessSlot(item, start, isDaylight(sun, start.Add(forecastSlotDuration/2)), profile))
+	}
+
+	var run []slotAssessment
+	flush := func() {
+		if len(run) > 0 && time.Duration(len(run))*forecastSlotDuration >= profile.Duration {
+			assessment.Windows = append(assessment.Windows, suitabilityWindow{Slots: run})
+		}
+		run = nil
+	}
+	for _, slot := range assessment.Slots {
+		if !slot.Suitable() || (len(run) > 0 && slot.Start.Sub(run[len(run)-1].Start) > forecastSlotDuration) {
+			flush()
+		}
+		if slot.Suitable() {
+			run = append(run, slot)
+		}
+	}
+	flush()
+
+	sort.SliceStable(assessment.Windows, func(i, j int) bool {
+		return assessment.Windows[i].Score() > assessment.Windows[j].Score()
+	})
+
+	return assessment
+}
+
+func isDaylight(sun sunDay, t time.Time) bool {
+	switch {
+	case sun.Horizon.AlwaysAbove:
+		return true
+	case sun.Horizon.AlwaysBelow:
+		return false
+	default:
+		return !t.Before(sun.Horizon.Rise) && t.Before(sun.Horizon.Set)
+	}
+}
+
+// assessSlot scores a slot from 0 to 100. Each factor costs up to suitabilityRangePenalty points
+// within the profile's limits, the more the closer it gets to them, and more beyond them.
+func assessSlot(item ForecastItem, start time.Time, daylight bool, profile ActivityProfile) slotAssessment {
+	slot := slotAssessment{Item: item, Start: start, Daylight: daylight}
+	penalty := 0.0
+
+	temp := item.Main.Temp
+	switch {
+	case temp < profile.MinTemp:
+		penalty += suitabilityRangePenalty + (profile.MinTemp-temp)*suitabilityTempPenalty
+		slot.Limits = append(slot.Limits, limitTooCold)
+	case temp > profile.MaxTemp:
+		penalty += suitabilityRangePenalty + (temp-profile.MaxTemp)*suitabilityTempPenalty
+		slot.Limits = append(slot.Limits, limitTooHot)
+	case profile.MaxTemp > profile.MinTemp:
+		middle := (profile.MinTemp + profile.MaxTemp) / 2
+		penalty += suitabilityRangePenalty * math.Abs(temp-middle) / (profile.MaxTemp - middle)
+	}
+
+	wind := item.Wind.Speed
+	if wind > profile.MaxWind {
+		penalty += suitabilityRangePenalty + (wind-profile.MaxWind)*suitabilityWindPenalty
+		slot.Limits = append(slot.Limits, limitTooWindy)
+	} else if profile.MaxWind > 0 {
+		penalty += suitabilityRangePenalty * wind / profile.MaxWind
+	}
+
+	chance := item.Pop * 100
+	if chance > profile.MaxPrecipitationChance {
+		penalty += suitabilityRangePenalty + (chance-profile.MaxPrecipitationChance)*suitabilityPrecipPenalty
+		slot.Limits = append(slot.Limits, limitPrecipitation)
+	} else if profile.MaxPrecipitationChance > 0 {
+		penalty += suitabilityRangePenalty * chance / profile.MaxPrecipitationChance
+	}
+
+	if profile.Daylight && !daylight {
+		penalty += suitabilityDaylightPenalty
+		slot.Limits = append(slot.Limits, limitDark)
+	}
+
+	slot.Score = int(math.Round(math.Max(0, 100-penalty)))
+	return slot
+}
+
+// mainLimit is the limit most often broken by the slots, or "" when all are suitable
+func mainLimit(slots []slotAssessment) string {
+	counts := map[string]int{}
+	best := ""
+	for _, slot := range slots {
+		for _, limit := range slot.Limits {
+			counts[limit]++
+			if best == "" || counts[limit] > counts[best] {
+				best = limit
+			}
+		}
+	}
+	return best
+}
+
+// describeProfile renders a profile's limits, e.g. "8.0°C - 28.0°C, wind up to 8.0 m/s, ..."
+func describeProfile(profile ActivityProfile, system UnitSystem, l *Localizer) string {
+	parts := []string{
+		fmt.Sprintf("%s - %s", system.FormatTemperature(profile.MinTemp), system.FormatTemperature(profile.MaxTemp)),
+		l.T("wind up to %s", system.FormatWindSpeed(profile.MaxWind)),
+		l.T("precipitation chance up to %.0f%%", profile.MaxPrecipitationChance),
+	}
+	if profile.Daylight {
+		parts = append(parts, l.T("daylight only"))
+	}
+	parts = append(parts, l.T("at least %s", formatDayLength(profile.Duration)))
+	return strings.Join(parts, ", ")
+}
+
+// FormatSuitabilityAsMarkdown renders the best windows for an activity and a daily outlook
+func FormatSuitabilityAsMarkdown(assessment suitabilityAssessment, data ForecastData, originalLocation, units, lang string) string {
+	l := newLocalizer(lang)
+	system := l.Units(units)
+
+	locationName := originalLocation
+	if locationName == "" {
+		locationName = fmt.Sprintf("%s, %s", data.City.Name, data.City.Country)
+	}
+
+	var builder strings.Builder
+	builder.WriteString(fmt.Sprintf("# %s\n\n", l.T("Activity Suitability: %s in %s", l.T(activityLabel(assessment.Profile.Name)), locationName)))
+	builder.WriteString(fmt.Sprintf("**%s:** %s\n", l.T("Profile"), describeProfile(assessment.Profile, system, l)))
+
+	builder.WriteString(fmt.Sprintf("\n## %s\n", l.T("Best Windows")))
+	if len(assessment.Windows) == 0 {
+		builder.WriteString(l.T("No time in the forecast meets the profile.") + "\n")
+		if best := bestSlot(assessment.Slots); best != nil {
+			builder.WriteString(fmt.Sprintf("%s\n", l.T("The closest is %s (score %d): %s.",
+				l.WeekdayClock(best.Start), best.Score, translateLimits(best.Limits, l))))
+		}
+	}
+	for i, window := range assessment.Windows {
+		if i == maxSuitabilityWindows {
+			break
+		}
+		stats := summarizeForecastItems(windowItems(window))
+		builder.WriteString(fmt.Sprintf("%d. **%s - %s** (%s): %s, %s, %s, %s\n",
+			i+1,
+			l.WeekdayClock(window.Start()),
+			l.Clock(window.End()),
+			formatDayLength(window.Duration()),
+			l.T("score %d", window.Score()),
+			fmt.Sprintf("%s - %s", system.FormatTemperature(stats.MinTemp), system.FormatTemperature(stats.MaxTemp)),
+			l.T("wind up to %s", system.FormatWindSpeed(stats.MaxWind)),
+			l.T("%.0f%% chance precipitation", stats.MaxPop*100),
+		))
+	}
+
+	builder.WriteString(fmt.Sprintf("\n## %s\n", l.T("Daily Outlook")))
+	for _, day := range groupAssessmentsByDay(assessment.Slots) {
+		suitable := 0
+		for _, slot := range day {
+			if slot.Suitable() {
+				suitable++
+			}
+		}
+		line := fmt.Sprintf("- **%s**: %s, %s", l.Day(day[0].Start), l.T("%d of %d slots suitable", suitable, len(day)), l.T("best score %d", bestSlot(day).Score))
+		if limit := mainLimit(day); limit != "" {
+			line += ", " + l.T("mostly %s", l.T(limit))
+		}
+		builder.WriteString(line + "\n")
+	}
+
+	builder.WriteString(fmt.Sprintf("\n*%s*\n", l.T("Scores run from 0 to 100; windows only include slots within every limit.")))
+
+	return builder.String()
+}
+
+func activityLabel(name string) string {
+	return toTitle(strings.ReplaceAll(name, "_", " "))
+}
+
+func translateLimits(limits []string, l *Localizer) string {
+	translated := make([]string, len(limits))
+	for i, limit := range limits {
+		translated[i] = l.T(limit)
+	}
+	return strings.Join(translated, ", ")
+}
+
+// bestSlot returns the highest scoring slot, the earliest on ties
+func bestSlot(slots []slotAssessment) *slotAssessment {
+	var best *slotAssessment
+	for i := range slots {
+		if best == nil || slots[i].Score > best.Score {
+			best = &slots[i]
+		}
+	}
+	return best
+}
+
+func windowItems(window suitabilityWindow) []ForecastItem {
+	items := make([]ForecastItem, len(window.Slots))
+	for i, slot := range window.Slots {
+		items[i] = slot.Item
+	}
+	return items
+}
+
+// groupAssessmentsByDay splits slots into local days, keeping their order
+func groupAssessmentsByDay(slots []slotAssessment) [][]slotAssessment {
+	var days [][]slotAssessment
+	for i, slot := range slots {
+		if i == 0 || slot.Start.Format(time.DateOnly) != slots[i-1].Start.Format(time.DateOnly) {
+			days = append(days, nil)
+		}
+		days[len(days)-1] = append(days[len(days)-1], slot)
+	}
+	return days
+}
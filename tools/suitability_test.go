@@ -0,0 +1,141 @@
+package tools
+
+import (
+	"math"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+// suitabilityForecast builds two days of 3-hour slots in Valencia starting at local midnight on
+// 2025-09-03, with rain likely on the first afternoon and a milder second day
+func suitabilityForecast() ForecastData {
+	var data ForecastData
+	data.City.Name, data.City.Country = "Valencia", "ES"
+	data.City.Coord.Lat, data.City.Coord.Lon = 39.4697, -0.3763
+	data.City.Timezone = 7200
+
+	start := time.Date(2025, 9, 3, 0, 0, 0, 0, offsetLocation(7200))
+	for i := 0; i < 16; i++ {
+		item := ForecastItem{Dt: start.Add(time.Duration(i) * forecastSlotDuration).Unix()}
+		temp := 20.0
+		if i >= 8 {
+			temp = 18
+		}
+		item.Main.Temp, item.Main.TempMin, item.Main.TempMax = temp, temp, temp
+		item.Wind.Speed = 3
+		if i == 5 { // 15:00 on the first day
+			item.Pop = 0.8
+		}
+		data.List = append(data.List, item)
+	}
+	return data
+}
+
+func TestAssessSuitability(t *testing.T) {
+	assessment := assessSuitability(suitabilityForecast(), activityProfiles["cycling"])
+
+	expected := []string{
+		"Thu 09:00-21:00",
+		"Wed 09:00-15:00",
+		"Wed 18:00-21:00",
+	}
+	if len(assessment.Windows) != len(expected) {
+		t.Fatalf("Expected %d windows, got %d", len(expected), len(assessment.Windows))
+	}
+	for i, window := range assessment.Windows {
+		if got := window.Start().Format("Mon 15:04") + "-" + window.End().Format("15:04"); got != expected[i] {
+			t.Errorf("Window %d = %s, expected %s", i, got, expected[i])
+		}
+	}
+
+	limits := map[int]string{0: limitDark, 2: limitDark, 5: limitPrecipitation, 7: limitDark}
+	for i, limit := range limits {
+		if slot := assessment.Slots[i]; slot.Suitable() || slot.Limits[0] != limit {
+			t.Errorf("Slot %d limits = %v, expected %s", i, slot.Limits, limit)
+		}
+	}
+	if score := assessment.Slots[8+3].Score; score != 96 {
+		t.Errorf("Expected a score of 96 at 18°C, got %d", score)
+	}
+
+	// A longer minimum duration drops the short windows
+	hiking := activityProfiles["cycling"]
+	hiking.Duration = 6 * time.Hour
+	if windows := assessSuitability(suitabilityForecast(), hiking).Windows; len(windows) != 2 {
+		t.Errorf("Expected 2 windows of at least 6 hours, got %d", len(windows))
+	}
+}
+
+func TestProfileOverrides(t *testing.T) {
+	minTemp, maxWind, daylight := 50.0, 10.0, false
+	overrides := profileOverrides{MinTemp: &minTemp, MaxWind: &maxWind, Daylight: &daylight}
+
+	profile := overrides.apply(activityProfiles["cycling"], getUnitSystem(unitsImperial))
+	if math.Abs(profile.MinTemp-10) > 0.01 || math.Abs(profile.MaxWind-4.47) > 0.01 || profile.Daylight {
+		t.Errorf("Unexpected profile after imperial overrides: %+v", profile)
+	}
+	if profile.MaxTemp != 28 || profile.MaxPrecipitationChance != 30 {
+		t.Errorf("Limits without overrides should keep the profile's values: %+v", profile)
+	}
+}
+
+func TestFormatSuitabilityAsMarkdown(t *testing.T) {
+	data := suitabilityForecast()
+	assessment := assessSuitability(data, activityProfiles["cycling"])
+
+	result := FormatSuitabilityAsMarkdown(assessment, data, "Valencia,ES", unitsMetric, langEnglish)
+	expected := []string{
+		"# Activity Suitability: Cycling in Valencia,ES",
+		"**Profile:** 8.0°C - 28.0°C, wind up to 8.0 m/s, precipitation chance up to 30%, daylight only, at least 3h 00m",
+		"1. **Thu 9:00 AM - 9:00 PM** (12h 00m): score 96, 18.0°C - 18.0°C, wind up to 3.0 m/s, 0% chance precipitation",
+		"- **Wed Sep 3**: 3 of 8 slots suitable, best score 94, mostly dark",
+	}
+	for _, text := range expected {
+		if !strings.Contains(result, text) {
+			t.Errorf("Expected %q in output:\n%s", text, result)
+		}
+	}
+
+	beach := FormatSuitabilityAsMarkdown(assessSuitability(data, activityProfiles["beach"]), data, "Valencia,ES", unitsMetric, langSpanish)
+	if !strings.Contains(beach, "Ningún momento del pronóstico cumple el perfil.") || !strings.Contains(beach, "demasiado frío") {
+		t.Errorf("Expected a translated explanation when nothing fits:\n%s", beach)
+	}
+}
+
+func TestWeatherSuitabilityTool(t *testing.T) {
+	os.Setenv("OPENWEATHER_API_KEY", "test_api_key")
+	defer os.Unsetenv("OPENWEATHER_API_KEY")
+	newOpenWeatherMapTestServer(t)
+
+	suitabilityTool := NewWeatherSuitabilityTool()
+
+	t.Run("Validation", func(t *testing.T) {
+		testCases := []struct {
+			args     map[string]any
+			errorMsg string
+		}{
+			{map[string]any{"activity": "skydiving"}, "invalid activity"},
+			{map[string]any{"activity": "custom", "min_temp": 25, "max_temp": 10}, "is above max_temp"},
+			{map[string]any{"activity": "custom", "duration_hours": 48}, "duration_hours must be between 3 and 24"},
+		}
+		for _, tc := range testCases {
+			content, isError := callToolText(t, suitabilityTool.Handler, tc.args)
+			if !isError || !strings.Contains(content, tc.errorMsg) {
+				t.Errorf("Expected error %q for %v, got: %s", tc.errorMsg, tc.args, content)
+			}
+		}
+	})
+
+	t.Run("Structured", func(t *testing.T) {
+		structured := callToolStructured(t, suitabilityTool.Tool, suitabilityTool.Handler, map[string]any{"location": "39.4697,-0.3763", "activity": "running", "max_wind": 5})
+		profile, _ := structured["profile"].(map[string]any)
+		if profile["activity"] != "running" || profile["maxWind"] != float64(5) {
+			t.Errorf("Unexpected profile: %v", profile)
+		}
+		if slots, ok := structured["slots"].([]any); !ok || len(slots) == 0 {
+			t.Errorf("Expected scored slots, got %v", structured["slots"])
+		}
+	})
+}
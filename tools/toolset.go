@@ -0,0 +1,196 @@
+package tools
+
+import (
+	"log"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// apiKeyTools are the tools that cannot answer without a working OpenWeatherMap API key. The
+// current weather and forecast tools stay listed, as their NWS provider needs no key.
+var apiKeyTools = map[string]bool{
+	"get_air_quality":     true,
+	"compare_weather":     true,
+	"weather_suitability": true,
+}
+
+// readOnlyTool annotates a tool that only looks data up: it changes nothing, so calling it again
+// with the same arguments is harmless. openWorld marks tools that reach external services.
+func readOnlyTool(title string, openWorld bool) mcp.ToolOption {
+	return mcp.WithToolAnnotation(mcp.ToolAnnotation{
+		Title:           title,
+		ReadOnlyHint:    mcp.ToBoolPtr(true),
+		DestructiveHint: mcp.ToBoolPtr(false),
+		IdempotentHint:  mcp.ToBoolPtr(true),
+		OpenWorldHint:   mcp.ToBoolPtr(openWorld),
+	})
+}
+
+// writeTool annotates a tool that changes server state: destructive when it removes data,
+// idempotent when repeating a call has no further effect
+func writeTool(title string, destructive bool, idempotent bool, openWorld bool) mcp.ToolOption {
+	return mcp.WithToolAnnotation(mcp.ToolAnnotation{
+		Title:           title,
+		ReadOnlyHint:    mcp.ToBoolPtr(false),
+		DestructiveHint: mcp.ToBoolPtr(destructive),
+		IdempotentHint:  mcp.ToBoolPtr(idempotent),
+		OpenWorldHint:   mcp.ToBoolPtr(openWorld),
+	})
+}
+
+// apiKeyStatus remembers which OpenWeatherMap API key was rejected, and tells the tool set when
+// that changes. Keeping the key rather than a flag lets a reloaded configuration with a new key
+// re-enable the tools straight away.
+type apiKeyStatus struct {
+	mu          sync.Mutex
+	rejectedKey string
+	onChange    func()
+}
+
+var apiKeyState = &apiKeyStatus{}
+
+// setRejectedKey records the rejected key, or "" once a key works, calling onChange when it differs
+// from before
+func (s *apiKeyStatus) setRejectedKey(key string) {
+	s.mu.Lock()
+	changed := s.rejectedKey != key
+	s.rejectedKey = key
+	onChange := s.onChange
+	s.mu.Unlock()
+
+	if changed && onChange != nil {
+		onChange()
+	}
+}
+
+func (s *apiKeyStatus) isRejected(key string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return key != "" && s.rejectedKey == key
+}
+
+// recordAPIKeyResponse tracks the API key state from the status of an OpenWeatherMap response
+func recordAPIKeyResponse(url string, statusCode int) {
+	if !strings.HasPrefix(url, openWeatherMapBaseURL) {
+		return
+	}
+	switch statusCode {
+	case http.StatusUnauthorized:
+		apiKeyState.setRejectedKey(os.Getenv("OPENWEATHER_API_KEY"))
+	case http.StatusOK:
+		apiKeyState.setRejectedKey("")
+	}
+}
+
+// ToolSet registers tools with the server and keeps the listed ones in line with the
+// configuration: tools named in DISABLED_TOOLS, and tools that need an OpenWeatherMap API key while
+// none is set or the key was rejected, are removed until the cause goes away. With the tool
+// listChanged capability on, mcp-go notifies connected sessions of every change.
+type ToolSet struct {
+	server *server.MCPServer
+
+	mu      sync.Mutex
+	tools   []server.ServerTool
+	enabled map[string]bool
+}
+
+func NewToolSet(mcpServer *server.MCPServer) *ToolSet {
+	set := &ToolSet{
+		server:  mcpServer,
+		enabled: make(map[string]bool),
+	}
+
+	apiKeyState.mu.Lock()
+	apiKeyState.onChange = set.Refresh
+	apiKeyState.mu.Unlock()
+
+	return set
+}
+
+// Add registers a tool. It is listed from the next Refresh on, if it is enabled.
+func (t *ToolSet) Add(tool mcp.Tool, handler server.ToolHandlerFunc) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.tools = append(t.tools, server.ServerTool{Tool: tool, Handler: handler})
+}
+
+// Refresh re-reads the configuration and adds or removes the tools whose state changed. Call it
+// after registering the tools and whenever the environment is reloaded.
+func (t *ToolSet) Refresh() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	disabled := disabledTools()
+	var added []server.ServerTool
+	var removed []string
+	for _, tool := range t.tools {
+		name := tool.Tool.Name
+		reason := toolDisabledReason(name, disabled)
+		enabled := reason == ""
+		if enabled == t.enabled[name] {
+			continue
+		}
+
+		t.enabled[name] = enabled
+		if enabled {
+			added = append(added, tool)
+			log.Printf("Enabled tool %s", name)
+		} else {
+			removed = append(removed, name)
+			log.Printf("Disabled tool %s: %s", name, reason)
+		}
+	}
+
+	if len(added) > 0 {
+		t.server.AddTools(added...)
+	}
+	if len(removed) > 0 {
+		t.server.DeleteTools(removed...)
+	}
+}
+
+// Enabled lists the names of the tools currently registered with the server, sorted
+func (t *ToolSet) Enabled() []string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var names []string
+	for name, enabled := range t.enabled {
+		if enabled {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// toolDisabledReason explains why a tool should not be listed, or returns "" when it should be
+func toolDisabledReason(name string, disabled map[string]bool) string {
+	switch {
+	case disabled[name]:
+		return "listed in DISABLED_TOOLS"
+	case apiKeyTools[name] && os.Getenv("OPENWEATHER_API_KEY") == "":
+		return "OPENWEATHER_API_KEY is not set"
+	case apiKeyTools[name] && apiKeyState.isRejected(os.Getenv("OPENWEATHER_API_KEY")):
+		return "OpenWeatherMap rejected the API key"
+	default:
+		return ""
+	}
+}
+
+// disabledTools reads the comma-separated tool names of DISABLED_TOOLS
+func disabledTools() map[string]bool {
+	disabled := make(map[string]bool)
+	for _, name := range strings.Split(os.Getenv("DISABLED_TOOLS"), ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			disabled[name] = true
+		}
+	}
+	return disabled
+}
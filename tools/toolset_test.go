@@ -0,0 +1,210 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+func TestToolAnnotations(t *testing.T) {
+	testCases := []struct {
+		tool      mcp.Tool
+		openWorld bool
+	}{
+		{NewCalculatorTool().Tool, false},
+		{NewIPTool().Tool, false},
+		{NewIPDataTool().Tool, true},
+		{NewWeatherTool().Tool, true},
+		{NewWeatherForecastTool().Tool, true},
+		{NewGeocodeTool().Tool, true},
+		{NewWeatherAlertsTool().Tool, true},
+		{NewAirQualityTool().Tool, true},
+		{NewWeatherHistoryTool().Tool, true},
+		{NewCompareWeatherTool().Tool, true},
+		{NewAstronomyTool().Tool, true},
+		{NewWeatherSuitabilityTool().Tool, true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.tool.Name, func(t *testing.T) {
+			annotations := tc.tool.Annotations
+			if annotations.Title == "" {
+				t.Error("Expected a title")
+			}
+			if annotations.ReadOnlyHint == nil || !*annotations.ReadOnlyHint {
+				t.Error("Expected the tool to be read-only")
+			}
+			if annotations.DestructiveHint == nil || *annotations.DestructiveHint {
+				t.Error("Expected the tool not to be destructive")
+			}
+			if annotations.IdempotentHint == nil || !*annotations.IdempotentHint {
+				t.Error("Expected the tool to be idempotent")
+			}
+			if annotations.OpenWorldHint == nil || *annotations.OpenWorldHint != tc.openWorld {
+				t.Errorf("Expected open world hint %v", tc.openWorld)
+			}
+		})
+	}
+}
+
+// listTools returns the names of the tools a server lists, sorted
+func listTools(t *testing.T, mcpServer *server.MCPServer) []string {
+	t.Helper()
+
+	message := `{"jsonrpc":"2.0","id":1,"method":"tools/list"}`
+	response, ok := mcpServer.HandleMessage(context.Background(), json.RawMessage(message)).(mcp.JSONRPCResponse)
+	if !ok {
+		t.Fatalf("Expected a successful response, got %+v", response)
+	}
+	result, ok := response.Result.(mcp.ListToolsResult)
+	if !ok {
+		t.Fatalf("Unexpected result type %T", response.Result)
+	}
+
+	var names []string
+	for _, tool := range result.Tools {
+		names = append(names, tool.Name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// expectListChanged checks that exactly one tools/list_changed notification was sent
+func expectListChanged(t *testing.T, session *notificationSession) {
+	t.Helper()
+
+	select {
+	case notification := <-session.notifications:
+		if notification.Method != mcp.MethodNotificationToolsListChanged {
+			t.Fatalf("Expected %s, got %s", mcp.MethodNotificationToolsListChanged, notification.Method)
+		}
+	default:
+		t.Fatal("Expected a tools/list_changed notification")
+	}
+	select {
+	case notification := <-session.notifications:
+		t.Fatalf("Unexpected notification %s", notification.Method)
+	default:
+	}
+}
+
+func TestToolSet_Refresh(t *testing.T) {
+	os.Unsetenv("OPENWEATHER_API_KEY")
+	os.Unsetenv("DISABLED_TOOLS")
+	defer os.Unsetenv("OPENWEATHER_API_KEY")
+	defer os.Unsetenv("DISABLED_TOOLS")
+	t.Cleanup(func() { apiKeyState = &apiKeyStatus{} })
+
+	mcpServer := server.NewMCPServer("test", "1.0.0", server.WithToolCapabilities(true))
+	session := &notificationSession{notifications: make(chan mcp.JSONRPCNotification, 10)}
+	if err := mcpServer.RegisterSession(context.Background(), session); err != nil {
+		t.Fatalf("Failed to register session: %v", err)
+	}
+
+	toolSet := NewToolSet(mcpServer)
+	calculator := NewCalculatorTool()
+	toolSet.Add(calculator.Tool, calculator.Handler)
+	weatherTool := NewWeatherTool()
+	toolSet.Add(weatherTool.Tool, weatherTool.Handler)
+	airQualityTool := NewAirQualityTool()
+	toolSet.Add(airQualityTool.Tool, airQualityTool.Handler)
+
+	toolSet.Refresh()
+	expectListChanged(t, session)
+	if names, want := listTools(t, mcpServer), []string{"calculate", "get_weather"}; !reflect.DeepEqual(names, want) {
+		t.Fatalf("Expected %v without an API key, got %v", want, names)
+	}
+
+	// An unchanged configuration sends nothing
+	toolSet.Refresh()
+	select {
+	case notification := <-session.notifications:
+		t.Fatalf("Unexpected notification %s", notification.Method)
+	default:
+	}
+
+	os.Setenv("OPENWEATHER_API_KEY", "test_api_key")
+	toolSet.Refresh()
+	expectListChanged(t, session)
+	if names, want := listTools(t, mcpServer), []string{"calculate", "get_air_quality", "get_weather"}; !reflect.DeepEqual(names, want) {
+		t.Fatalf("Expected %v with an API key, got %v", want, names)
+	}
+
+	os.Setenv("DISABLED_TOOLS", " calculate, get_weather ")
+	toolSet.Refresh()
+	expectListChanged(t, session)
+	if names, want := listTools(t, mcpServer), []string{"get_air_quality"}; !reflect.DeepEqual(names, want) {
+		t.Fatalf("Expected %v with DISABLED_TOOLS, got %v", want, names)
+	}
+	if enabled := toolSet.Enabled(); !reflect.DeepEqual(enabled, []string{"get_air_quality"}) {
+		t.Errorf("Expected Enabled to report get_air_quality, got %v", enabled)
+	}
+}
+
+func TestToolSet_RejectedAPIKey(t *testing.T) {
+	os.Setenv("OPENWEATHER_API_KEY", "invalid_key")
+	os.Unsetenv("DISABLED_TOOLS")
+	defer os.Unsetenv("OPENWEATHER_API_KEY")
+	t.Cleanup(func() { apiKeyState = &apiKeyStatus{} })
+
+	status := http.StatusUnauthorized
+	owmServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+		w.Write([]byte(`{"cod":401,"message":"Invalid API key"}`))
+	}))
+	defer owmServer.Close()
+	originalURL := openWeatherMapBaseURL
+	openWeatherMapBaseURL = owmServer.URL
+	defer func() { openWeatherMapBaseURL = originalURL }()
+
+	mcpServer := server.NewMCPServer("test", "1.0.0", server.WithToolCapabilities(true))
+	session := &notificationSession{notifications: make(chan mcp.JSONRPCNotification, 10)}
+	if err := mcpServer.RegisterSession(context.Background(), session); err != nil {
+		t.Fatalf("Failed to register session: %v", err)
+	}
+
+	toolSet := NewToolSet(mcpServer)
+	airQualityTool := NewAirQualityTool()
+	toolSet.Add(airQualityTool.Tool, airQualityTool.Handler)
+	toolSet.Refresh()
+	expectListChanged(t, session)
+
+	if _, err := fetchWeatherData(context.Background(), owmServer.URL+"/data/2.5/weather"); err == nil {
+		t.Fatal("Expected the rejected key to fail the request")
+	}
+	expectListChanged(t, session)
+	if names := listTools(t, mcpServer); len(names) != 0 {
+		t.Fatalf("Expected no tools after the key was rejected, got %v", names)
+	}
+
+	// A reloaded configuration with another key brings the tools back
+	os.Setenv("OPENWEATHER_API_KEY", "new_key")
+	toolSet.Refresh()
+	expectListChanged(t, session)
+	if names := listTools(t, mcpServer); !reflect.DeepEqual(names, []string{"get_air_quality"}) {
+		t.Fatalf("Expected get_air_quality with a new key, got %v", names)
+	}
+
+	// Any OpenWeatherMap request notices a rejected key, and a successful response re-enables the
+	// tools
+	if _, err := GeocodeLocation(context.Background(), "London", 1, ""); err == nil {
+		t.Fatal("Expected the rejected key to fail geocoding")
+	}
+	expectListChanged(t, session)
+	status = http.StatusOK
+	if _, err := fetchWeatherData(context.Background(), owmServer.URL+"/data/2.5/weather"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expectListChanged(t, session)
+	if names := listTools(t, mcpServer); !reflect.DeepEqual(names, []string{"get_air_quality"}) {
+		t.Fatalf("Expected get_air_quality once the key works, got %v", names)
+	}
+}
@@ -0,0 +1,215 @@
+package tools
+
+import (
+	"math"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const (
+	unitsMetric   = "metric"
+	unitsImperial = "imperial"
+	unitsStandard = "standard"
+	unitsUK       = "uk"
+)
+
+// UnitSystem describes how weather quantities are displayed. Values are always fetched
+// in metric (°C, m/s, hPa, meters, mm) and converted when formatted.
+// The decimal separator is set by Localizer.Units and defaults to a point.
+type UnitSystem struct {
+	Name          string `json:"system" jsonschema:"enum=metric,enum=imperial,enum=standard,enum=uk"`
+	Temperature   string `json:"temperature"`
+	WindSpeed     string `json:"windSpeed"`
+	Pressure      string `json:"pressure"`
+	Distance      string `json:"distance"`
+	Precipitation string `json:"precipitation"`
+
+	decimal string
+}
+
+var unitSystems = map[string]UnitSystem{
+	unitsMetric:   {Name: unitsMetric, Temperature: "°C", WindSpeed: "m/s", Pressure: "hPa", Distance: "km", Precipitation: "mm"},
+	unitsImperial: {Name: unitsImperial, Temperature: "°F", WindSpeed: "mph", Pressure: "inHg", Distance: "miles", Precipitation: "in"},
+	unitsStandard: {Name: unitsStandard, Temperature: "K", WindSpeed: "m/s", Pressure: "hPa", Distance: "km", Precipitation: "mm"},
+	unitsUK:       {Name: unitsUK, Temperature: "°C", WindSpeed: "mph", Pressure: "hPa", Distance: "miles", Precipitation: "mm"},
+}
+
+// countryUnitSystems lists the countries that don't use plain metric, keyed by ISO 3166 code.
+// US territories have their own codes but follow US customary units.
+var countryUnitSystems = map[string]string{
+	"US": unitsImperial,
+	"LR": unitsImperial,
+	"MM": unitsImperial,
+	"PR": unitsImperial,
+	"GU": unitsImperial,
+	"VI": unitsImperial,
+	"AS": unitsImperial,
+	"MP": unitsImperial,
+	"UM": unitsImperial,
+	"GB": unitsUK,
+}
+
+// countryNameUnitSystems maps country names and common aliases found in free-text locations
+var countryNameUnitSystems = map[string]string{
+	"USA":              unitsImperial,
+	"UNITED STATES":    unitsImperial,
+	"AMERICA":          unitsImperial,
+	"LIBERIA":          unitsImperial,
+	"MYANMAR":          unitsImperial,
+	"BURMA":            unitsImperial,
+	"PUERTO RICO":      unitsImperial,
+	"GUAM":             unitsImperial,
+	"UK":               unitsUK,
+	"UNITED KINGDOM":   unitsUK,
+	"GREAT BRITAIN":    unitsUK,
+	"ENGLAND":          unitsUK,
+	"SCOTLAND":         unitsUK,
+	"WALES":            unitsUK,
+	"NORTHERN IRELAND": unitsUK,
+}
+
+// getUnitSystem returns the named unit system, defaulting to metric for unknown names
+func getUnitSystem(name string) UnitSystem {
+	if system, ok := unitSystems[strings.ToLower(name)]; ok {
+		return system
+	}
+	return unitSystems[unitsMetric]
+}
+
+func isValidUnits(name string) bool {
+	_, ok := unitSystems[strings.ToLower(name)]
+	return ok
+}
+
+// withUnits adds the optional units argument; defaultNote says what the tool falls back to
+func withUnits(defaultNote string) mcp.ToolOption {
+	return mcp.WithString("units",
+		mcp.Description("Unit system (optional). 'metric' (°C, m/s, hPa, km, mm), 'imperial' (°F, mph, inHg, miles, in), 'standard' (Kelvin, m/s, hPa, km, mm) or 'uk' (°C, mph, hPa, miles, mm). "+defaultNote),
+		mcp.Enum(unitsMetric, unitsImperial, unitsStandard, unitsUK),
+	)
+}
+
+// unitSystemForCountry returns the customary unit system for an ISO 3166 country code
+func unitSystemForCountry(countryCode string) string {
+	if system, ok := countryUnitSystems[strings.ToUpper(countryCode)]; ok {
+		return system
+	}
+	return unitsMetric
+}
+
+// number formats a converted value with the system's decimal separator
+func (u UnitSystem) number(value float64, decimals int) string {
+	return formatDecimal(value, decimals, u.decimal)
+}
+
+func celsiusToFahrenheit(c float64) float64    { return c*9/5 + 32 }
+func fahrenheitToCelsius(f float64) float64    { return (f - 32) * 5 / 9 }
+func celsiusToKelvin(c float64) float64        { return c + 273.15 }
+func metersPerSecondToMph(ms float64) float64  { return ms * 2.2369362921 }
+func kmhToMetersPerSecond(kmh float64) float64 { return kmh / 3.6 }
+func hPaToInHg(hPa float64) float64            { return hPa * 0.0295299831 }
+func metersToMiles(m float64) float64          { return m / 1609.344 }
+func mmToInches(mm float64) float64            { return mm / 25.4 }
+
+// ConvertTemperature converts degrees Celsius into the system's temperature unit
+func (u UnitSystem) ConvertTemperature(celsius float64) float64 {
+	switch u.Temperature {
+	case "°F":
+		return celsiusToFahrenheit(celsius)
+	case "K":
+		return celsiusToKelvin(celsius)
+	default:
+		return celsius
+	}
+}
+
+// TemperatureToCelsius converts a temperature in the system's unit back into degrees Celsius
+func (u UnitSystem) TemperatureToCelsius(value float64) float64 {
+	switch u.Temperature {
+	case "°F":
+		return fahrenheitToCelsius(value)
+	case "K":
+		return value - 273.15
+	default:
+		return value
+	}
+}
+
+func (u UnitSystem) FormatTemperature(celsius float64) string {
+	return u.number(u.ConvertTemperature(celsius), 1) + u.Temperature
+}
+
+// ConvertWindSpeed converts meters per second into the system's wind speed unit
+func (u UnitSystem) ConvertWindSpeed(ms float64) float64 {
+	if u.WindSpeed == "mph" {
+		return metersPerSecondToMph(ms)
+	}
+	return ms
+}
+
+// WindSpeedToMetersPerSecond converts a wind speed in the system's unit back into meters per second
+func (u UnitSystem) WindSpeedToMetersPerSecond(value float64) float64 {
+	if u.WindSpeed == "mph" {
+		return value / metersPerSecondToMph(1)
+	}
+	return value
+}
+
+func (u UnitSystem) FormatWindSpeed(ms float64) string {
+	return u.number(u.ConvertWindSpeed(ms), 1) + " " + u.WindSpeed
+}
+
+// ConvertPressure converts hectopascals into the system's pressure unit
+func (u UnitSystem) ConvertPressure(hPa float64) float64 {
+	if u.Pressure == "inHg" {
+		return hPaToInHg(hPa)
+	}
+	return hPa
+}
+
+func (u UnitSystem) FormatPressure(hPa float64) string {
+	if u.Pressure == "inHg" {
+		return u.number(u.ConvertPressure(hPa), 2) + " inHg"
+	}
+	return u.number(hPa, 0) + " " + u.Pressure
+}
+
+// FormatPressureChange renders a pressure difference with its sign, e.g. "+1.5 hPa" or "-0.04 inHg"
+func (u UnitSystem) FormatPressureChange(hPa float64) string {
+	sign := "+"
+	if hPa < 0 {
+		sign = "-"
+	}
+	if u.Pressure == "inHg" {
+		return sign + u.number(math.Abs(u.ConvertPressure(hPa)), 2) + " inHg"
+	}
+	return sign + u.number(math.Abs(hPa), 1) + " " + u.Pressure
+}
+
+// ConvertDistance converts meters into the system's distance unit
+func (u UnitSystem) ConvertDistance(meters float64) float64 {
+	if u.Distance == "miles" {
+		return metersToMiles(meters)
+	}
+	return meters / 1000
+}
+
+func (u UnitSystem) FormatDistance(meters float64) string {
+	return u.number(u.ConvertDistance(meters), 1) + " " + u.Distance
+}
+
+// ConvertPrecipitation converts millimeters into the system's precipitation unit
+func (u UnitSystem) ConvertPrecipitation(mm float64) float64 {
+	if u.Precipitation == "in" {
+		return mmToInches(mm)
+	}
+	return mm
+}
+
+func (u UnitSystem) FormatPrecipitation(mm float64) string {
+	if u.Precipitation == "in" {
+		return u.number(u.ConvertPrecipitation(mm), 2) + " in"
+	}
+	return u.number(mm, 1) + " " + u.Precipitation
+}
@@ -0,0 +1,117 @@
+package tools
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestUnitSystemFormatting(t *testing.T) {
+	testCases := []struct {
+		units         string
+		temperature   string
+		windSpeed     string
+		pressure      string
+		distance      string
+		precipitation string
+	}{
+		{"metric", "20.0°C", "5.0 m/s", "1013 hPa", "10.0 km", "2.5 mm"},
+		{"imperial", "68.0°F", "11.2 mph", "29.91 inHg", "6.2 miles", "0.10 in"},
+		{"standard", "293.1K", "5.0 m/s", "1013 hPa", "10.0 km", "2.5 mm"},
+		{"uk", "20.0°C", "11.2 mph", "1013 hPa", "6.2 miles", "2.5 mm"},
+		{"unknown", "20.0°C", "5.0 m/s", "1013 hPa", "10.0 km", "2.5 mm"}, // falls back to metric
+	}
+
+	for _, tc := range testCases {
+		system := getUnitSystem(tc.units)
+		if got := system.FormatTemperature(20); got != tc.temperature {
+			t.Errorf("%s FormatTemperature(20) = %s, expected %s", tc.units, got, tc.temperature)
+		}
+		if got := system.FormatWindSpeed(5); got != tc.windSpeed {
+			t.Errorf("%s FormatWindSpeed(5) = %s, expected %s", tc.units, got, tc.windSpeed)
+		}
+		if got := system.FormatPressure(1013); got != tc.pressure {
+			t.Errorf("%s FormatPressure(1013) = %s, expected %s", tc.units, got, tc.pressure)
+		}
+		if got := system.FormatDistance(10000); got != tc.distance {
+			t.Errorf("%s FormatDistance(10000) = %s, expected %s", tc.units, got, tc.distance)
+		}
+		if got := system.FormatPrecipitation(2.5); got != tc.precipitation {
+			t.Errorf("%s FormatPrecipitation(2.5) = %s, expected %s", tc.units, got, tc.precipitation)
+		}
+	}
+}
+
+func TestFormatPressureChange(t *testing.T) {
+	testCases := []struct {
+		units    string
+		change   float64
+		expected string
+	}{
+		{"metric", 2, "+2.0 hPa"},
+		{"metric", -1.5, "-1.5 hPa"},
+		{"metric", 0, "+0.0 hPa"},
+		{"imperial", -2, "-0.06 inHg"},
+		{"uk", 3.4, "+3.4 hPa"},
+	}
+
+	for _, tc := range testCases {
+		if got := getUnitSystem(tc.units).FormatPressureChange(tc.change); got != tc.expected {
+			t.Errorf("%s FormatPressureChange(%.1f) = %s, expected %s", tc.units, tc.change, got, tc.expected)
+		}
+	}
+}
+
+func TestUnitSystemForCountry(t *testing.T) {
+	testCases := []struct {
+		country  string
+		expected string
+	}{
+		{"US", "imperial"},
+		{"us", "imperial"},
+		{"LR", "imperial"},
+		{"MM", "imperial"},
+		{"PR", "imperial"},
+		{"VI", "imperial"},
+		{"GB", "uk"},
+		{"ES", "metric"},
+		{"", "metric"},
+	}
+
+	for _, tc := range testCases {
+		if got := unitSystemForCountry(tc.country); got != tc.expected {
+			t.Errorf("unitSystemForCountry(%q) = %s, expected %s", tc.country, got, tc.expected)
+		}
+	}
+}
+
+func TestWeatherTool_UnitsParameter(t *testing.T) {
+	newNWSTestServer(t)
+
+	weatherTool := NewWeatherTool()
+
+	content, isError := callToolText(t, weatherTool.Handler, map[string]any{
+		"location": "40.7128,-74.0060",
+		"provider": "nws",
+		"units":    "uk",
+	})
+	if isError {
+		t.Fatalf("Expected success, got error: %s", content)
+	}
+	for _, want := range []string{"**Temperature:** 22.2°C", "**Pressure:** 1019 hPa", "**Wind:** 9.2 mph NW (320°)", "**Visibility:** 10.0 miles"} {
+		if !strings.Contains(content, want) {
+			t.Errorf("Expected to find '%s' in uk units output:\n%s", want, content)
+		}
+	}
+
+	content, isError = callToolText(t, weatherTool.Handler, map[string]any{
+		"location": "40.7128,-74.0060",
+		"provider": "nws",
+		"units":    "kelvin",
+	})
+	if !isError {
+		t.Fatalf("Expected error for invalid units, got: %s", content)
+	}
+	if !strings.Contains(content, "invalid units \"kelvin\"") {
+		t.Errorf("Unexpected error message: %s", content)
+	}
+}
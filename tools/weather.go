@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"os"
 	"strconv"
 	"strings"
@@ -44,15 +45,24 @@ type WeatherData struct {
 		TempMax   float64 `json:"temp_max"`
 		Pressure  int     `json:"pressure"`
 		Humidity  int     `json:"humidity"`
+		SeaLevel  int     `json:"sea_level"`
+		GrndLevel int     `json:"grnd_level"`
 	} `json:"main"`
 	Visibility int `json:"visibility"`
 	Wind       struct {
 		Speed float64 `json:"speed"`
 		Deg   int     `json:"deg"`
+		Gust  float64 `json:"gust"`
 	} `json:"wind"`
 	Clouds struct {
 		All int `json:"all"`
 	} `json:"clouds"`
+	Rain *struct {
+		OneH float64 `json:"1h"`
+	} `json:"rain,omitempty"`
+	Snow *struct {
+		OneH float64 `json:"1h"`
+	} `json:"snow,omitempty"`
 	Dt  int64 `json:"dt"`
 	Sys struct {
 		Type    int    `json:"type"`
@@ -144,39 +154,95 @@ func NewWeatherForecastTool() *WeatherForecastTool {
 
 func weatherTool() mcp.Tool {
 	return mcp.NewTool("get_weather",
-		mcp.WithDescription("Get current weather for a location. Uses client's IP location by default, or accepts a custom location parameter (city name or 'lat,lon' coordinates)"),
+		mcp.WithDescription("Get current weather for a location. Uses client's IP location by default, or accepts a custom location parameter (city name or 'lat,lon' coordinates). The UV index is not available: neither provider reports it."),
+		readOnlyTool("Current Weather", true),
 		mcp.WithString("location",
-			mcp.Description("Location to get weather for (optional). Can be city name (e.g., 'London' or 'New York,US') or coordinates (e.g., '40.7128,-74.0060'). Uses client IP location if not provided."),
+			mcp.Description("Location to get weather for (optional). Can be city name (e.g., 'London' or 'New York,US'), coordinates (e.g., '40.7128,-74.0060') or a saved location (e.g., '@home'). Uses client IP location if not provided."),
 		),
+		withUnits("Defaults to the customary units of the location's country."),
+		mcp.WithString("provider",
+			mcp.Description("Weather data provider (optional). 'openweathermap' (default) or 'nws' for US National Weather Service station observations (US locations only, no API key required)."),
+			mcp.Enum(providerOpenWeatherMap, providerNWS),
+		),
+		withLanguage(),
+		withFormat(),
+		mcp.WithOutputSchema[WeatherReport](),
 	)
 }
 
 func weatherForecastTool() mcp.Tool {
 	return mcp.NewTool("get_weather_forecast",
-		mcp.WithDescription("Get 5-day weather forecast for a location. Uses client's IP location by default, or accepts a custom location parameter (city name or 'lat,lon' coordinates)"),
+		mcp.WithDescription("Get 5-day weather forecast for a location. Uses client's IP location by default, or accepts a custom location parameter (city name or 'lat,lon' coordinates). The UV index is not available: neither provider reports it."),
+		readOnlyTool("Weather Forecast", true),
 		mcp.WithString("location",
-			mcp.Description("Location to get forecast for (optional). Can be city name (e.g., 'London' or 'New York,US') or coordinates (e.g., '40.7128,-74.0060'). Uses client IP location if not provided."),
+			mcp.Description("Location to get forecast for (optional). Can be city name (e.g., 'London' or 'New York,US'), coordinates (e.g., '40.7128,-74.0060') or a saved location (e.g., '@home'). Uses client IP location if not provided."),
+		),
+		withUnits("Defaults to the customary units of the location's country."),
+		mcp.WithString("provider",
+			mcp.Description("Weather data provider (optional). 'openweathermap' (default) or 'nws' for US National Weather Service forecast periods (US locations only, no API key required)."),
+			mcp.Enum(providerOpenWeatherMap, providerNWS),
+		),
+		mcp.WithNumber("hours",
+			mcp.Description("How many hours of 3-hour slots to show (optional, 3-120, default 24)"),
+			mcp.Min(3),
+			mcp.Max(maxForecastHours),
+		),
+		mcp.WithNumber("days",
+			mcp.Description("How many days of daily or part-of-day summaries to show (optional, 1-5, default 5)"),
+			mcp.Min(1),
+			mcp.Max(defaultForecastDays),
+		),
+		mcp.WithString("granularity",
+			mcp.Description("How to group the forecast (optional). '3h' for 3-hour slots only, 'daily' for daily summaries only, 'daypart' for night/morning/afternoon/evening summaries. Defaults to 3-hour slots followed by daily summaries."),
+			mcp.Enum(granularity3Hourly, granularityDaily, granularityDaypart),
+		),
+		mcp.WithString("start",
+			mcp.Description("Where the forecast starts (optional): 'now' (default), 'today', 'tomorrow', a weekday name (e.g., 'saturday') or a date (YYYY-MM-DD), in the location's local time"),
 		),
+		mcp.WithArray("fields",
+			mcp.Description("Fields to include (optional). Defaults to temperature, conditions and precipitation."),
+			mcp.WithStringEnumItems(forecastFields),
+		),
+		withLanguage(),
+		withFormat(),
+		mcp.WithOutputSchema[ForecastReport](),
 	)
 }
 
 func weatherToolHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if request.GetString("provider", providerOpenWeatherMap) == providerNWS {
+		return handleNWSWeatherRequest(ctx, request)
+	}
+
 	return handleWeatherRequest(
 		ctx,
 		request,
 		buildWeatherURLFromLocation,
-		getWeatherURLFromIP,
 		FormatWeatherAsMarkdown,
+		NewWeatherReport,
 	)
 }
 
 func weatherForecastToolHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	opts, err := parseForecastOptions(request)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	if request.GetString("provider", providerOpenWeatherMap) == providerNWS {
+		return handleNWSForecastRequest(ctx, request, opts)
+	}
+
 	return handleWeatherRequest(
 		ctx,
 		request,
 		buildForecastURLFromLocation,
-		getForecastURLFromIP,
-		FormatForecastAsMarkdown,
+		func(data ForecastData, originalLocation string, units string, lang string) string {
+			return FormatForecastWithOptions(data, originalLocation, units, lang, opts)
+		},
+		func(data ForecastData, originalLocation string, units string) ForecastReport {
+			return NewForecastReport(data, originalLocation, units, opts)
+		},
 	)
 }
 
@@ -189,78 +255,147 @@ func validateAPIKey() (string, error) {
 	return apiKey, nil
 }
 
-// fetchWeatherData fetches data from the given URL and returns the raw JSON response
-func fetchWeatherData(url string) ([]byte, error) {
-	resp, err := http.Get(url)
+// fetchWeatherData fetches data from the given URL and returns the raw JSON response. The request
+// is aborted when ctx is cancelled.
+func fetchWeatherData(ctx context.Context, url string) ([]byte, error) {
+	statusCode, body, err := getAPIResponse(ctx, url, "weather")
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch weather data: %v", err)
+		return nil, err
 	}
-	defer resp.Body.Close()
 
-	body, err := io.ReadAll(resp.Body)
+	if statusCode != 200 {
+		return nil, fmt.Errorf("weather API error (status %d): %s", statusCode, string(body))
+	}
+
+	return body, nil
+}
+
+// getAPIResponse performs a GET request and returns the status code and body of the response,
+// naming the API as kind in errors. Every weather and geocoding request goes through it, so a
+// rejected OpenWeatherMap key is noticed by whichever request sees it first.
+func getAPIResponse(ctx context.Context, url string, kind string) (int, []byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to create %s request: %v", kind, err)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read weather response: %v", err)
+		return 0, nil, fmt.Errorf("failed to fetch %s data: %v", kind, err)
 	}
+	defer resp.Body.Close()
 
-	if resp.StatusCode != 200 {
-		return nil, fmt.Errorf("weather API error (status %d): %s", resp.StatusCode, string(body))
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to read %s response: %v", kind, err)
 	}
+	recordAPIKeyResponse(url, resp.StatusCode)
 
-	return body, nil
+	return resp.StatusCode, body, nil
 }
 
+// weatherRequestSteps are the stages handleWeatherRequest reports progress for: resolving the
+// location, fetching the data and formatting it
+const weatherRequestSteps = 3
+
 // URLBuilderFunc represents a function that builds weather API URLs from location parameters
 type URLBuilderFunc func(location, apiKey, units string) string
 
-// IPURLBuilderFunc represents a function that builds weather API URLs from IP context
-type IPURLBuilderFunc func(ctx context.Context, apiKey string) (string, string, string, error)
+// FormatterFunc represents a function that formats weather data as markdown in the given language
+type FormatterFunc[T any] func(data T, originalLocation string, units string, lang string) string
 
-// FormatterFunc represents a function that formats weather data as markdown
-type FormatterFunc[T any] func(data T, originalLocation string, units string) string
+// ReportFunc represents a function that converts weather data into its structured report
+type ReportFunc[T any, R any] func(data T, originalLocation string, units string) R
+
+// weatherRequest holds the arguments shared by the OpenWeatherMap tools once they are resolved
+type weatherRequest struct {
+	APIKey       string
+	Format       string
+	Resolved     *ResolvedLocation
+	LocationName string
+	Units        string
+	Lang         string
+}
+
+// resolveWeatherRequest checks the API key and resolves the format, location (or client IP), units
+// and language arguments of an OpenWeatherMap tool call
+func resolveWeatherRequest(ctx context.Context, request mcp.CallToolRequest) (*weatherRequest, error) {
+	format, err := parseFormat(request)
+	if err != nil {
+		return nil, err
+	}
 
-// handleWeatherRequest is a generic handler for both weather and forecast requests
-func handleWeatherRequest[T any](
-	ctx context.Context,
-	request mcp.CallToolRequest,
-	urlBuilder URLBuilderFunc,
-	ipURLBuilder IPURLBuilderFunc,
-	formatter FormatterFunc[T],
-) (*mcp.CallToolResult, error) {
 	// Check if API key is configured
 	apiKey, err := validateAPIKey()
 	if err != nil {
-		return mcp.NewToolResultError(err.Error()), nil
+		return nil, err
 	}
 
-	var weatherURL string
-	var locationName string
-	var units string
+	// Resolve the location argument (or client IP) to coordinates
+	resolved, err := resolveLocationInteractively(ctx, request.GetString("location", ""))
+	if err != nil {
+		return nil, err
+	}
 
-	// Check if location parameter is provided
-	if args, ok := request.Params.Arguments.(map[string]any); ok {
-		if locationParam, exists := args["location"]; exists && locationParam != nil {
-			if locationStr, ok := locationParam.(string); ok && locationStr != "" {
-				locationName = locationStr
-				units = determineUnitsFromLocation(locationStr)
-				weatherURL = urlBuilder(locationStr, apiKey, units)
-			}
-		}
+	locationName := resolved.Query
+	if locationName == "" {
+		locationName = resolved.Name
 	}
 
-	// If no location parameter, use IP-based location
-	if weatherURL == "" {
-		var err error
-		weatherURL, locationName, units, err = ipURLBuilder(ctx, apiKey)
-		if err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("Failed to get location from IP: %v", err)), nil
-		}
+	units, err := resolveUnits(request, resolved)
+	if err != nil {
+		return nil, err
+	}
+
+	lang, err := resolveLanguage(ctx, request, resolved)
+	if err != nil {
+		return nil, err
+	}
+
+	return &weatherRequest{
+		APIKey:       apiKey,
+		Format:       format,
+		Resolved:     resolved,
+		LocationName: locationName,
+		Units:        units,
+		Lang:         lang,
+	}, nil
+}
+
+// Coordinates returns the resolved location as a 'lat,lon' string for the URL builders
+func (r *weatherRequest) Coordinates() string {
+	return fmt.Sprintf("%.4f,%.4f", r.Resolved.Lat, r.Resolved.Lon)
+}
+
+// handleWeatherRequest is a generic handler for both weather and forecast requests. It reports
+// progress at each stage when the client passed a progress token, and stops as soon as the
+// request is cancelled.
+func handleWeatherRequest[T any, R any](
+	ctx context.Context,
+	request mcp.CallToolRequest,
+	urlBuilder URLBuilderFunc,
+	formatter FormatterFunc[T],
+	reporter ReportFunc[T, R],
+) (*mcp.CallToolResult, error) {
+	progress := newProgressReporter(ctx, request, weatherRequestSteps)
+	progress.report(0, "Resolving location")
+
+	params, err := resolveWeatherRequest(ctx, request)
+	if err != nil {
+		return cancellableError(ctx, err), nil
 	}
+	progress.report(1, fmt.Sprintf("Fetching weather data for %s", params.LocationName))
+
+	// Always fetch metric values; formatters convert to the requested unit system.
+	// The language only changes OpenWeatherMap's condition descriptions.
+	weatherURL := urlBuilder(params.Coordinates(), params.APIKey, unitsMetric) + "&lang=" + params.Lang
 
 	// Fetch weather data
-	body, err := fetchWeatherData(weatherURL)
+	body, err := fetchWeatherData(ctx, weatherURL)
 	if err != nil {
-		return mcp.NewToolResultError(err.Error()), nil
+		return cancellableError(ctx, err), nil
 	}
+	progress.report(2, "Formatting the result")
 
 	// Parse response
 	var data T
@@ -268,185 +403,214 @@ func handleWeatherRequest[T any](
 		return mcp.NewToolResultError(fmt.Sprintf("Failed to parse weather response: %v", err)), nil
 	}
 
-	// Format and return result
-	result := formatter(data, locationName, units)
-	return mcp.NewToolResultText(result), nil
+	// Format and return result alongside its structured form
+	result := formatter(data, params.LocationName, params.Units, params.Lang) + formatAlternativesNote(params.Resolved)
+	progress.report(weatherRequestSteps, "Done")
+	return renderResult(params.Format, result, reporter(data, params.LocationName, params.Units)), nil
+}
+
+// cancellableError reports a failed step, or the cancellation that made it fail
+func cancellableError(ctx context.Context, err error) *mcp.CallToolResult {
+	if ctx.Err() != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("request cancelled: %v", ctx.Err()))
+	}
+	return mcp.NewToolResultError(err.Error())
 }
 
 func buildWeatherURLFromLocation(location, apiKey, units string) string {
+	return buildOpenWeatherMapURL("weather", location, apiKey, units)
+}
+
+func buildForecastURLFromLocation(location, apiKey, units string) string {
+	return buildOpenWeatherMapURL("forecast", location, apiKey, units)
+}
+
+// buildOpenWeatherMapURL builds a data/2.5 endpoint URL for coordinates or an escaped city query
+func buildOpenWeatherMapURL(endpoint, location, apiKey, units string) string {
 	// Check if location is coordinates (lat,lon format)
-	if strings.Contains(location, ",") && len(strings.Split(location, ",")) == 2 {
+	if _, _, ok := parseCoordinates(location); ok {
 		coords := strings.Split(location, ",")
 		lat := strings.TrimSpace(coords[0])
 		lon := strings.TrimSpace(coords[1])
-
-		// Validate that both are numbers
-		if _, err1 := strconv.ParseFloat(lat, 64); err1 == nil {
-			if _, err2 := strconv.ParseFloat(lon, 64); err2 == nil {
-				return fmt.Sprintf("https://api.openweathermap.org/data/2.5/weather?lat=%s&lon=%s&appid=%s&units=%s", lat, lon, apiKey, units)
-			}
-		}
+		return fmt.Sprintf("%s/data/2.5/%s?lat=%s&lon=%s&appid=%s&units=%s", openWeatherMapBaseURL, endpoint, lat, lon, apiKey, units)
 	}
 
 	// Treat as city name
-	return fmt.Sprintf("https://api.openweathermap.org/data/2.5/weather?q=%s&appid=%s&units=%s", location, apiKey, units)
+	return fmt.Sprintf("%s/data/2.5/%s?q=%s&appid=%s&units=%s", openWeatherMapBaseURL, endpoint, url.QueryEscape(location), apiKey, units)
 }
 
-func getWeatherURLFromIP(ctx context.Context, apiKey string) (string, string, string, error) {
-	// Use shared IP data fetching logic
-	ipData, err := FetchIPData(ctx, "")
-	if err != nil {
-		return "", "", "", err
+// parseCoordinates parses a 'lat,lon' location string, reporting false for anything else
+func parseCoordinates(location string) (float64, float64, bool) {
+	coords := strings.Split(location, ",")
+	if len(coords) != 2 {
+		return 0, 0, false
 	}
 
-	// Determine units based on country
-	units := "metric"
-	if ipData.CountryCode == "US" {
-		units = "imperial"
+	lat, err := strconv.ParseFloat(strings.TrimSpace(coords[0]), 64)
+	if err != nil {
+		return 0, 0, false
+	}
+	lon, err := strconv.ParseFloat(strings.TrimSpace(coords[1]), 64)
+	if err != nil {
+		return 0, 0, false
 	}
 
-	weatherURL := fmt.Sprintf("https://api.openweathermap.org/data/2.5/weather?lat=%.4f&lon=%.4f&appid=%s&units=%s", ipData.Lat, ipData.Lon, apiKey, units)
-	locationName := fmt.Sprintf("%s, %s", ipData.City, ipData.Country)
-
-	return weatherURL, locationName, units, nil
+	return lat, lon, true
 }
 
-func buildForecastURLFromLocation(location, apiKey, units string) string {
-	// Check if location is coordinates (lat,lon format)
-	if strings.Contains(location, ",") && len(strings.Split(location, ",")) == 2 {
-		coords := strings.Split(location, ",")
-		lat := strings.TrimSpace(coords[0])
-		lon := strings.TrimSpace(coords[1])
-
-		// Validate that both are numbers
-		if _, err1 := strconv.ParseFloat(lat, 64); err1 == nil {
-			if _, err2 := strconv.ParseFloat(lon, 64); err2 == nil {
-				return fmt.Sprintf("https://api.openweathermap.org/data/2.5/forecast?lat=%s&lon=%s&appid=%s&units=%s", lat, lon, apiKey, units)
-			}
+// resolveUnits returns the explicit units argument if given, otherwise the location's customary units
+func resolveUnits(request mcp.CallToolRequest, resolved *ResolvedLocation) (string, error) {
+	if units := request.GetString("units", ""); units != "" {
+		if !isValidUnits(units) {
+			return "", fmt.Errorf("invalid units %q: must be one of metric, imperial, standard, uk", units)
 		}
+		return strings.ToLower(units), nil
 	}
-
-	// Treat as city name
-	return fmt.Sprintf("https://api.openweathermap.org/data/2.5/forecast?q=%s&appid=%s&units=%s", location, apiKey, units)
+	return unitsForLocation(resolved), nil
 }
 
-func getForecastURLFromIP(ctx context.Context, apiKey string) (string, string, string, error) {
-	// Use shared IP data fetching logic
-	ipData, err := FetchIPData(ctx, "")
-	if err != nil {
-		return "", "", "", err
+// unitsForLocation prefers the resolved country and falls back to hints in the query text
+func unitsForLocation(resolved *ResolvedLocation) string {
+	if resolved.Units != "" {
+		return resolved.Units
 	}
-
-	// Determine units based on country
-	units := "metric"
-	if ipData.CountryCode == "US" {
-		units = "imperial"
+	if resolved.Country != "" {
+		return unitSystemForCountry(resolved.Country)
 	}
-
-	forecastURL := fmt.Sprintf("https://api.openweathermap.org/data/2.5/forecast?lat=%.4f&lon=%.4f&appid=%s&units=%s", ipData.Lat, ipData.Lon, apiKey, units)
-	locationName := fmt.Sprintf("%s, %s", ipData.City, ipData.Country)
-
-	return forecastURL, locationName, units, nil
+	return determineUnitsFromLocation(resolved.Query)
 }
 
+// determineUnitsFromLocation guesses the unit system from country or US state qualifiers in a
+// free-text location such as "Chicago, IL" or "Yangon, Myanmar"
 func determineUnitsFromLocation(location string) string {
-	locationUpper := strings.ToUpper(location)
-
-	// Check for Canada first (should be metric, not imperial)
-	if strings.Contains(locationUpper, "CANADA") {
-		return "metric"
+	if _, _, ok := parseCoordinates(location); ok {
+		return unitsMetric
 	}
 
-	// Check if location contains US indicators
-	usIndicators := []string{",US", ", US", "USA", "UNITED STATES"}
+	name, qualifiers := splitLocationQuery(location)
 
-	for _, indicator := range usIndicators {
-		if strings.Contains(locationUpper, indicator) {
-			return "imperial"
+	// Country names may appear as the whole location ("USA") or as a qualifier
+	for _, part := range append([]string{name}, qualifiers...) {
+		if system, ok := countryNameUnitSystems[strings.ToUpper(part)]; ok {
+			return system
 		}
 	}
 
-	// Check for US state codes (common ones) - need space or comma before state code
-	usStates := []string{", CA", ",CA", " CA", ", NY", ",NY", " NY", ", TX", ",TX", " TX",
-		", FL", ",FL", " FL", ", IL", ",IL", " IL", ", PA", ",PA", " PA", ", OH", ",OH", " OH",
-		", GA", ",GA", " GA", ", NC", ",NC", " NC", ", MI", ",MI", " MI"}
-	for _, state := range usStates {
-		if strings.Contains(locationUpper, state) {
-			return "imperial"
+	for _, qualifier := range qualifiers {
+		if isUSStateCode(qualifier) {
+			return unitsImperial
+		}
+		if system, ok := countryUnitSystems[strings.ToUpper(qualifier)]; ok {
+			return system
 		}
 	}
 
-	return "metric"
+	// "Los Angeles CA" style locations without a comma before the state code
+	if fields := strings.Fields(name); len(fields) > 1 {
+		if last := fields[len(fields)-1]; len(last) == 2 && isUSStateCode(last) {
+			return unitsImperial
+		}
+	}
+
+	return unitsMetric
 }
 
-func FormatWeatherAsMarkdown(data WeatherData, originalLocation string, units string) string {
+func FormatWeatherAsMarkdown(data WeatherData, originalLocation string, units string, lang string) string {
 	var builder strings.Builder
-	isImperial := (units == "imperial")
+	l := newLocalizer(lang)
+	system := l.Units(units)
 
-	builder.WriteString(fmt.Sprintf("# Weather Information: %s\n\n", data.Name))
+	builder.WriteString(fmt.Sprintf("# %s: %s\n\n", l.T("Weather Information"), data.Name))
 
 	if originalLocation != "" && originalLocation != data.Name {
-		builder.WriteString(fmt.Sprintf("*Requested location: %s*\n\n", originalLocation))
+		builder.WriteString(fmt.Sprintf("*%s: %s*\n\n", l.T("Requested location"), originalLocation))
 	}
 
 	// Current conditions
-	builder.WriteString("## Current Conditions\n")
+	builder.WriteString(fmt.Sprintf("## %s\n", l.T("Current Conditions")))
 	if len(data.Weather) > 0 {
 		weather := data.Weather[0]
-		builder.WriteString(fmt.Sprintf("- **Condition:** %s (%s)\n", toTitle(weather.Description), weather.Main))
+		builder.WriteString(fmt.Sprintf("- **%s:** %s (%s)\n", l.T("Condition"), l.Title(weather.Description), l.T(weather.Main)))
 	}
 
-	// Temperature formatting based on units
-	if isImperial {
-		builder.WriteString(fmt.Sprintf("- **Temperature:** %.1f°F (feels like %.1f°F)\n", data.Main.Temp, data.Main.FeelsLike))
-		if data.Main.TempMin != data.Main.TempMax {
-			builder.WriteString(fmt.Sprintf("- **Range:** %.1f°F - %.1f°F\n", data.Main.TempMin, data.Main.TempMax))
-		}
-	} else {
-		builder.WriteString(fmt.Sprintf("- **Temperature:** %.1f°C (feels like %.1f°C)\n", data.Main.Temp, data.Main.FeelsLike))
-		if data.Main.TempMin != data.Main.TempMax {
-			builder.WriteString(fmt.Sprintf("- **Range:** %.1f°C - %.1f°C\n", data.Main.TempMin, data.Main.TempMax))
-		}
+	builder.WriteString(fmt.Sprintf("- **%s:** %s (%s)\n", l.T("Temperature"), system.FormatTemperature(data.Main.Temp), l.T("feels like %s", system.FormatTemperature(data.Main.FeelsLike))))
+	if data.Main.TempMin != data.Main.TempMax {
+		builder.WriteString(fmt.Sprintf("- **%s:** %s - %s\n", l.T("Range"), system.FormatTemperature(data.Main.TempMin), system.FormatTemperature(data.Main.TempMax)))
 	}
 
-	builder.WriteString(fmt.Sprintf("- **Humidity:** %d%%\n", data.Main.Humidity))
-	if isImperial {
-		builder.WriteString(fmt.Sprintf("- **Pressure:** %.2f inHg\n", float64(data.Main.Pressure)))
-	} else {
-		builder.WriteString(fmt.Sprintf("- **Pressure:** %d hPa\n", data.Main.Pressure))
+	builder.WriteString(fmt.Sprintf("- **%s:** %d%%\n", l.T("Humidity"), data.Main.Humidity))
+	pressure := system.FormatPressure(float64(data.Main.Pressure))
+	if data.Main.GrndLevel > 0 && data.Main.GrndLevel != data.Main.Pressure {
+		pressure = l.T("%s at sea level, %s at ground level", pressure, system.FormatPressure(float64(data.Main.GrndLevel)))
 	}
+	builder.WriteString(fmt.Sprintf("- **%s:** %s\n", l.T("Pressure"), pressure))
 
-	// Wind and visibility
-	builder.WriteString("\n## Details\n")
+	// Wind, visibility and precipitation
+	builder.WriteString(fmt.Sprintf("\n## %s\n", l.T("Details")))
 	if data.Wind.Speed > 0 {
-		windDirection := getWindDirection(data.Wind.Deg)
-		if isImperial {
-			builder.WriteString(fmt.Sprintf("- **Wind:** %.1f mph %s (%d°)\n", data.Wind.Speed, windDirection, data.Wind.Deg))
-		} else {
-			builder.WriteString(fmt.Sprintf("- **Wind:** %.1f m/s %s (%d°)\n", data.Wind.Speed, windDirection, data.Wind.Deg))
+		windDirection := l.Compass(getWindDirection(data.Wind.Deg))
+		wind := fmt.Sprintf("%s %s (%d°)", system.FormatWindSpeed(data.Wind.Speed), windDirection, data.Wind.Deg)
+		if data.Wind.Gust > data.Wind.Speed {
+			wind += ", " + l.T("gusts %s", system.FormatWindSpeed(data.Wind.Gust))
 		}
+		builder.WriteString(fmt.Sprintf("- **%s:** %s\n", l.T("Wind"), wind))
 	}
 	if data.Visibility > 0 {
-		if isImperial {
-			visibilityMiles := float64(data.Visibility) / 1609.34 // meters to miles
-			builder.WriteString(fmt.Sprintf("- **Visibility:** %.1f miles\n", visibilityMiles))
-		} else {
-			visibilityKm := float64(data.Visibility) / 1000
-			builder.WriteString(fmt.Sprintf("- **Visibility:** %.1f km\n", visibilityKm))
-		}
+		builder.WriteString(fmt.Sprintf("- **%s:** %s\n", l.T("Visibility"), system.FormatDistance(float64(data.Visibility))))
 	}
 	if data.Clouds.All > 0 {
-		builder.WriteString(fmt.Sprintf("- **Cloudiness:** %d%%\n", data.Clouds.All))
+		builder.WriteString(fmt.Sprintf("- **%s:** %d%%\n", l.T("Cloudiness"), data.Clouds.All))
+	}
+	if data.Rain != nil && data.Rain.OneH > 0 {
+		builder.WriteString(fmt.Sprintf("- **%s:** %s\n", l.T("Rain (last hour)"), system.FormatPrecipitation(data.Rain.OneH)))
+	}
+	if data.Snow != nil && data.Snow.OneH > 0 {
+		builder.WriteString(fmt.Sprintf("- **%s:** %s\n", l.T("Snow (last hour)"), system.FormatPrecipitation(data.Snow.OneH)))
 	}
 
-	// Location info
-	builder.WriteString("\n## Location\n")
-	builder.WriteString(fmt.Sprintf("- **City:** %s, %s\n", data.Name, data.Sys.Country))
-	builder.WriteString(fmt.Sprintf("- **Coordinates:** %.4f, %.4f\n", data.Coord.Lat, data.Coord.Lon))
+	writeDerivedMetrics(&builder, deriveMetrics(data.Main.Temp, float64(data.Main.Humidity), data.Wind.Speed), system, l)
+
+	// Sun times and location info in the city's local time
+	loc := offsetLocation(data.Timezone)
+	builder.WriteString(fmt.Sprintf("\n## %s\n", l.T("Location")))
+	builder.WriteString(fmt.Sprintf("- **%s:** %s, %s\n", l.T("City"), data.Name, data.Sys.Country))
+	builder.WriteString(fmt.Sprintf("- **%s:** %.4f, %.4f\n", l.T("Coordinates"), data.Coord.Lat, data.Coord.Lon))
+	writeSunTimes(&builder, data.Sys.Sunrise, data.Sys.Sunset, loc, l)
+	if data.Dt > 0 {
+		builder.WriteString(fmt.Sprintf("- **%s:** %s\n", l.T("Observed"), l.DateTime(time.Unix(data.Dt, 0).In(loc))))
+	}
+	builder.WriteString(fmt.Sprintf("- **%s:** %s\n", l.T("Timezone"), loc.String()))
 
 	return builder.String()
 }
 
+// offsetLocation turns an OpenWeatherMap UTC offset in seconds into a fixed zone named like "UTC+02:00"
+func offsetLocation(offsetSeconds int) *time.Location {
+	if offsetSeconds == 0 {
+		return time.UTC
+	}
+
+	sign := "+"
+	offset := offsetSeconds
+	if offset < 0 {
+		sign = "-"
+		offset = -offset
+	}
+	name := fmt.Sprintf("UTC%s%02d:%02d", sign, offset/3600, offset%3600/60)
+
+	return time.FixedZone(name, offsetSeconds)
+}
+
+// writeSunTimes appends sunrise and sunset in the location's local time, skipping missing values
+func writeSunTimes(builder *strings.Builder, sunrise, sunset int64, loc *time.Location, l *Localizer) {
+	if sunrise > 0 {
+		builder.WriteString(fmt.Sprintf("- **%s:** %s\n", l.T("Sunrise"), l.Clock(time.Unix(sunrise, 0).In(loc))))
+	}
+	if sunset > 0 {
+		builder.WriteString(fmt.Sprintf("- **%s:** %s\n", l.T("Sunset"), l.Clock(time.Unix(sunset, 0).In(loc))))
+	}
+}
+
 func getWindDirection(degrees int) string {
 	directions := []string{"N", "NNE", "NE", "ENE", "E", "ESE", "SE", "SSE", "S", "SSW", "SW", "WSW", "W", "WNW", "NW", "NNW"}
 	index := int((float64(degrees)+11.25)/22.5) % 16
@@ -469,130 +633,213 @@ func toTitle(s string) string {
 	return strings.Join(words, " ")
 }
 
-func FormatForecastAsMarkdown(data ForecastData, originalLocation string, units string) string {
+func FormatForecastAsMarkdown(data ForecastData, originalLocation string, units string, lang string) string {
+	return FormatForecastWithOptions(data, originalLocation, units, lang, defaultForecastOptions())
+}
+
+// FormatForecastWithOptions renders the part of the forecast selected by opts, grouped by its granularity
+func FormatForecastWithOptions(data ForecastData, originalLocation string, units string, lang string, opts ForecastOptions) string {
 	var builder strings.Builder
-	isImperial := (units == "imperial")
+	l := newLocalizer(lang)
+	system := l.Units(units)
+	selection := selectForecast(data, opts)
 
-	builder.WriteString(fmt.Sprintf("# Weather Forecast: %s\n\n", data.City.Name))
+	builder.WriteString(fmt.Sprintf("# %s: %s\n\n", l.T("Weather Forecast"), data.City.Name))
 
 	if originalLocation != "" && originalLocation != data.City.Name {
-		builder.WriteString(fmt.Sprintf("*Requested location: %s*\n\n", originalLocation))
+		builder.WriteString(fmt.Sprintf("*%s: %s*\n\n", l.T("Requested location"), originalLocation))
 	}
 
-	// Next 24 hours (8 three-hour periods)
-	builder.WriteString("## Next 24 Hours\n\n")
-
-	for i, item := range data.List {
-		if i >= 8 { // Only show next 24 hours (8 * 3-hour periods)
-			break
+	if selection.Empty != "" {
+		builder.WriteString(l.T("No forecast data available from %s; the forecast runs until %s.", l.Day(selection.Start), l.DateTime(selection.End)) + "\n")
+	} else {
+		if opts.Granularity == "" || opts.Granularity == granularity3Hourly {
+			if selection.StartLabel == "" {
+				builder.WriteString(fmt.Sprintf("## %s\n\n", l.T("Next %d Hours", opts.Hours)))
+			} else {
+				builder.WriteString(fmt.Sprintf("## %s\n\n", l.T("%d Hours from %s", opts.Hours, l.Day(selection.Start))))
+			}
+			writeForecastSlots(&builder, selection.Slots, selection.Location, system, l, opts)
 		}
 
-		// Format timestamp
-		timestamp := time.Unix(item.Dt, 0)
-		timeStr := timestamp.Format("Mon 3:04 PM")
-
-		// Weather condition
-		condition := ""
-		if len(item.Weather) > 0 {
-			condition = toTitle(item.Weather[0].Description)
+		if opts.Granularity == "" {
+			builder.WriteString("\n")
 		}
 
-		// Temperature with units
-		tempStr := ""
-		if isImperial {
-			tempStr = fmt.Sprintf("%.1f°F", item.Main.Temp)
-		} else {
-			tempStr = fmt.Sprintf("%.1f°C", item.Main.Temp)
+		switch opts.Granularity {
+		case "", granularityDaily:
+			builder.WriteString(fmt.Sprintf("## %s\n\n", l.T("%d-Day Forecast", opts.Days)))
+			writeForecastGroups(&builder, selection.Groups, selection.Location, system, l, opts)
+		case granularityDaypart:
+			builder.WriteString(fmt.Sprintf("## %s\n\n", l.T("Forecast by Part of Day")))
+			writeForecastGroups(&builder, selection.Groups, selection.Location, system, l, opts)
 		}
-
-		// Precipitation probability
-		popStr := ""
-		if item.Pop > 0 {
-			popStr = fmt.Sprintf(" (%.0f%% chance rain)", item.Pop*100)
-		}
-
-		builder.WriteString(fmt.Sprintf("**%s**: %s, %s%s\n", timeStr, tempStr, condition, popStr))
 	}
 
-	// Daily summaries for next 5 days
-	builder.WriteString("\n## 5-Day Forecast\n\n")
+	// Location info
+	builder.WriteString(fmt.Sprintf("\n## %s\n", l.T("Location")))
+	builder.WriteString(fmt.Sprintf("- **%s:** %s, %s\n", l.T("City"), data.City.Name, data.City.Country))
+	builder.WriteString(fmt.Sprintf("- **%s:** %.4f, %.4f\n", l.T("Coordinates"), data.City.Coord.Lat, data.City.Coord.Lon))
+	writeSunTimes(&builder, data.City.Sunrise, data.City.Sunset, selection.Location, l)
+	builder.WriteString(fmt.Sprintf("- **%s:** %s\n", l.T("Timezone"), l.T("%s (all times local)", selection.Location.String())))
 
-	// Group forecast items by day
-	dailyForecasts := make(map[string][]ForecastItem)
-	dayOrder := make([]string, 0)
+	return builder.String()
+}
 
-	for _, item := range data.List {
-		timestamp := time.Unix(item.Dt, 0)
-		dayKey := timestamp.Format("Mon Jan 2")
+// writeForecastSlots lists 3-hour slots with the fields selected in opts
+func writeForecastSlots(builder *strings.Builder, items []ForecastItem, loc *time.Location, system UnitSystem, l *Localizer, opts ForecastOptions) {
+	for i, item := range items {
+		var parts []string
+		if opts.Fields[fieldTemperature] {
+			parts = append(parts, system.FormatTemperature(item.Main.Temp))
+		}
+		if opts.Fields[fieldConditions] && len(item.Weather) > 0 {
+			parts = append(parts, l.Title(item.Weather[0].Description))
+		}
+		if opts.Fields[fieldFeelsLike] {
+			parts = append(parts, l.T("feels like %s", system.FormatTemperature(item.Main.FeelsLike)))
+		}
+		if opts.Fields[fieldWind] {
+			wind := l.T("wind %s", system.FormatWindSpeed(item.Wind.Speed)+" "+l.Compass(getWindDirection(item.Wind.Deg)))
+			if item.Wind.Gust > item.Wind.Speed {
+				wind += fmt.Sprintf(" (%s)", l.T("gusts %s", system.FormatWindSpeed(item.Wind.Gust)))
+			}
+			parts = append(parts, wind)
+		}
+		if opts.Fields[fieldHumidity] {
+			parts = append(parts, l.T("humidity %.0f%%", float64(item.Main.Humidity)))
+		}
+		if opts.Fields[fieldPressure] {
+			// The trend compares with the previous slot, so the first slot has none
+			var details []string
+			if item.Main.GrndLevel > 0 && item.Main.GrndLevel != item.Main.Pressure {
+				details = append(details, l.T("ground %s", system.FormatPressure(float64(item.Main.GrndLevel))))
+			}
+			if i > 0 {
+				details = append(details, l.T(pressureTrend(float64(item.Main.Pressure-items[i-1].Main.Pressure), float64(item.Dt-items[i-1].Dt)/3600)))
+			}
+			pressure := l.T("pressure %s", system.FormatPressure(float64(item.Main.Pressure)))
+			if len(details) > 0 {
+				pressure += fmt.Sprintf(" (%s)", strings.Join(details, ", "))
+			}
+			parts = append(parts, pressure)
+		}
+		if opts.Fields[fieldClouds] {
+			parts = append(parts, l.T("clouds %.0f%%", float64(item.Clouds.All)))
+		}
+		if opts.Fields[fieldVisibility] && item.Visibility > 0 {
+			parts = append(parts, l.T("visibility %s", system.FormatDistance(float64(item.Visibility))))
+		}
+		if opts.Fields[fieldDewPoint] || opts.Fields[fieldApparent] || opts.Fields[fieldBeaufort] {
+			parts = append(parts, formatSlotMetrics(item.Metrics(), system, l, opts)...)
+		}
 
-		if _, exists := dailyForecasts[dayKey]; !exists {
-			dayOrder = append(dayOrder, dayKey)
+		popStr := ""
+		if opts.Fields[fieldPrecipitation] {
+			var precipitation []string
+			if item.Pop > 0 {
+				precipitation = append(precipitation, l.T("%.0f%% chance rain", item.Pop*100))
+			}
+			if item.Rain != nil && item.Rain.ThreeH > 0 {
+				precipitation = append(precipitation, l.T("rain %s", system.FormatPrecipitation(item.Rain.ThreeH)))
+			}
+			if item.Snow != nil && item.Snow.ThreeH > 0 {
+				precipitation = append(precipitation, l.T("snow %s", system.FormatPrecipitation(item.Snow.ThreeH)))
+			}
+			if len(precipitation) > 0 {
+				popStr = fmt.Sprintf(" (%s)", strings.Join(precipitation, ", "))
+			}
 		}
 
-		dailyForecasts[dayKey] = append(dailyForecasts[dayKey], item)
+		timestamp := time.Unix(item.Dt, 0).In(loc)
+		builder.WriteString(fmt.Sprintf("**%s**: %s%s\n", l.WeekdayClock(timestamp), strings.Join(parts, ", "), popStr))
 	}
+}
 
-	// Display daily summaries
-	dayCount := 0
-	for _, day := range dayOrder {
-		if dayCount >= 5 { // Only show 5 days
-			break
-		}
+// formatSlotMetrics renders the derived metrics selected in opts for one slot
+func formatSlotMetrics(metrics derivedMetrics, system UnitSystem, l *Localizer, opts ForecastOptions) []string {
+	var parts []string
+	if opts.Fields[fieldDewPoint] {
+		parts = append(parts, l.T("dew point %s (%s)", system.FormatTemperature(metrics.DewPoint), l.T(metrics.Comfort)))
+	}
+	if opts.Fields[fieldApparent] {
+		parts = append(parts, l.T("apparent %s (%s)", system.FormatTemperature(metrics.Apparent), l.T(metrics.Category)))
+	}
+	if opts.Fields[fieldBeaufort] {
+		parts = append(parts, l.T("Force %d (%s)", metrics.Beaufort, l.T(metrics.BeaufortName())))
+	}
+	return parts
+}
 
-		items := dailyForecasts[day]
-		if len(items) == 0 {
-			continue
-		}
+// writeForecastGroups writes one summary line per day or part of day
+func writeForecastGroups(builder *strings.Builder, groups []forecastGroup, loc *time.Location, system UnitSystem, l *Localizer, opts ForecastOptions) {
+	if len(groups) == 0 {
+		builder.WriteString(l.T("No forecast data available for the requested days.") + "\n")
+	}
 
-		// Calculate daily min/max temperatures
-		minTemp := items[0].Main.TempMin
-		maxTemp := items[0].Main.TempMax
-		maxPop := float64(0)
-		mostCommonCondition := ""
-		conditionCount := make(map[string]int)
+	for _, group := range groups {
+		stats := summarizeForecastItems(group.Items)
 
-		for _, item := range items {
-			if item.Main.TempMin < minTemp {
-				minTemp = item.Main.TempMin
+		var parts []string
+		if opts.Fields[fieldTemperature] {
+			parts = append(parts, fmt.Sprintf("%s - %s", system.FormatTemperature(stats.MinTemp), system.FormatTemperature(stats.MaxTemp)))
+		}
+		if opts.Fields[fieldConditions] && stats.Condition != "" {
+			parts = append(parts, l.T(stats.Condition))
+		}
+		if opts.Fields[fieldPrecipitation] {
+			if stats.MaxPop > 0 {
+				parts = append(parts, l.T("%.0f%% chance precipitation", stats.MaxPop*100))
 			}
-			if item.Main.TempMax > maxTemp {
-				maxTemp = item.Main.TempMax
+			if stats.Rain > 0 {
+				parts = append(parts, l.T("rain %s", system.FormatPrecipitation(stats.Rain)))
 			}
-			if item.Pop > maxPop {
-				maxPop = item.Pop
+			if stats.Snow > 0 {
+				parts = append(parts, l.T("snow %s", system.FormatPrecipitation(stats.Snow)))
 			}
-
-			if len(item.Weather) > 0 {
-				condition := item.Weather[0].Main
-				conditionCount[condition]++
-				if conditionCount[condition] > conditionCount[mostCommonCondition] {
-					mostCommonCondition = condition
-				}
+		}
+		if opts.Fields[fieldFeelsLike] {
+			parts = append(parts, l.T("feels like %s", system.FormatTemperature(stats.MinFeelsLike)+" - "+system.FormatTemperature(stats.MaxFeelsLike)))
+		}
+		if opts.Fields[fieldWind] {
+			wind := l.T("wind up to %s", system.FormatWindSpeed(stats.MaxWind))
+			if stats.MaxGust > stats.MaxWind {
+				wind += fmt.Sprintf(" (%s)", l.T("gusts %s", system.FormatWindSpeed(stats.MaxGust)))
 			}
+			parts = append(parts, wind)
 		}
-
-		// Format daily summary
-		tempRangeStr := ""
-		if isImperial {
-			tempRangeStr = fmt.Sprintf("%.1f°F - %.1f°F", minTemp, maxTemp)
-		} else {
-			tempRangeStr = fmt.Sprintf("%.1f°C - %.1f°C", minTemp, maxTemp)
+		if opts.Fields[fieldHumidity] {
+			parts = append(parts, l.T("humidity %.0f%%", stats.Humidity))
 		}
-
-		precipStr := ""
-		if maxPop > 0 {
-			precipStr = fmt.Sprintf(", %.0f%% chance precipitation", maxPop*100)
+		if opts.Fields[fieldPressure] {
+			pressure := l.T("pressure %s", system.FormatPressure(stats.Pressure))
+			if len(group.Items) > 1 {
+				pressure += fmt.Sprintf(", %s (%s)", l.T(pressureTrend(stats.PressureChange, stats.Hours)), system.FormatPressureChange(stats.PressureChange))
+			}
+			parts = append(parts, pressure)
+		}
+		if opts.Fields[fieldClouds] {
+			parts = append(parts, l.T("clouds %.0f%%", stats.Clouds))
+		}
+		if opts.Fields[fieldVisibility] && stats.MinVisibility > 0 {
+			parts = append(parts, l.T("visibility down to %s", system.FormatDistance(stats.MinVisibility)))
+		}
+		if opts.Fields[fieldDewPoint] {
+			parts = append(parts, l.T("dew point up to %s (%s)", system.FormatTemperature(stats.MaxDewPoint), l.T(comfortLevel(stats.MaxDewPoint))))
+		}
+		if opts.Fields[fieldApparent] {
+			parts = append(parts, l.T("apparent %s (%s)", system.FormatTemperature(stats.MinApparent)+" - "+system.FormatTemperature(stats.MaxApparent), apparentRange(stats.MinApparent, stats.MaxApparent, l)))
+		}
+		if opts.Fields[fieldBeaufort] {
+			force := beaufortForce(stats.MaxWind)
+			parts = append(parts, l.T("up to Force %d (%s)", force, l.T(beaufortNames[force])))
 		}
 
-		builder.WriteString(fmt.Sprintf("**%s**: %s, %s%s\n", day, tempRangeStr, mostCommonCondition, precipStr))
-
-		dayCount++
+		// Labels are rebuilt from the first slot so day and part names follow the language
+		label := l.Day(time.Unix(group.Items[0].Dt, 0).In(loc))
+		if group.Part != "" {
+			label = fmt.Sprintf("%s %s", label, l.T(group.Part))
+		}
+		builder.WriteString(fmt.Sprintf("**%s**: %s\n", label, strings.Join(parts, ", ")))
 	}
-
-	// Location info
-	builder.WriteString("\n## Location\n")
-	builder.WriteString(fmt.Sprintf("- **City:** %s, %s\n", data.City.Name, data.City.Country))
-	builder.WriteString(fmt.Sprintf("- **Coordinates:** %.4f, %.4f\n", data.City.Coord.Lat, data.City.Coord.Lon))
-
-	return builder.String()
 }
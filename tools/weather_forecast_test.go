@@ -6,6 +6,7 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 )
 
 // loadFixture loads test data from a JSON fixture file
@@ -43,7 +44,7 @@ func TestFormatForecastAsMarkdown(t *testing.T) {
 	forecastData := loadForecastFixture(t, "valencia_forecast_response.json")
 
 	t.Run("MetricUnits", func(t *testing.T) {
-		result := FormatForecastAsMarkdown(forecastData, "", "metric")
+		result := FormatForecastAsMarkdown(forecastData, "", "metric", "en")
 
 		// Check header
 		if !strings.Contains(result, "# Weather Forecast: Valencia") {
@@ -94,7 +95,7 @@ func TestFormatForecastAsMarkdown(t *testing.T) {
 	})
 
 	t.Run("ImperialUnits", func(t *testing.T) {
-		result := FormatForecastAsMarkdown(forecastData, "Valencia, Spain", "imperial")
+		result := FormatForecastAsMarkdown(forecastData, "Valencia, Spain", "imperial", "en")
 
 		// Check requested location is shown
 		if !strings.Contains(result, "*Requested location: Valencia, Spain*") {
@@ -113,7 +114,7 @@ func TestFormatForecastAsMarkdown(t *testing.T) {
 	})
 
 	t.Run("StructureValidation", func(t *testing.T) {
-		result := FormatForecastAsMarkdown(forecastData, "", "metric")
+		result := FormatForecastAsMarkdown(forecastData, "", "metric", "en")
 
 		// Verify markdown structure
 		lines := strings.Split(result, "\n")
@@ -208,7 +209,7 @@ func TestBuildForecastURLFromLocation(t *testing.T) {
 			location:    "Valencia,ES",
 			apiKey:      "test_key",
 			units:       "metric",
-			expectedURL: "https://api.openweathermap.org/data/2.5/forecast?q=Valencia,ES&appid=test_key&units=metric",
+			expectedURL: "https://api.openweathermap.org/data/2.5/forecast?q=Valencia%2CES&appid=test_key&units=metric",
 		},
 		{
 			name:        "Coordinates",
@@ -222,7 +223,7 @@ func TestBuildForecastURLFromLocation(t *testing.T) {
 			location:    "New York,US",
 			apiKey:      "test_key",
 			units:       "imperial",
-			expectedURL: "https://api.openweathermap.org/data/2.5/forecast?q=New York,US&appid=test_key&units=imperial",
+			expectedURL: "https://api.openweathermap.org/data/2.5/forecast?q=New+York%2CUS&appid=test_key&units=imperial",
 		},
 	}
 
@@ -246,3 +247,74 @@ func TestRealAPIExample(t *testing.T) {
 		t.Errorf("Expected URL: %s\nGot: %s", expectedURL, result)
 	}
 }
+
+func TestFormatForecastAsMarkdown_LocalTimezone(t *testing.T) {
+	forecastData := loadForecastFixture(t, "valencia_forecast_response.json")
+
+	// Output must not depend on the server's own timezone
+	originalLocal := time.Local
+	defer func() { time.Local = originalLocal }()
+
+	time.Local = time.FixedZone("HST", -10*3600)
+	result := FormatForecastAsMarkdown(forecastData, "Valencia", "metric", "en")
+	time.Local = time.FixedZone("JST", 9*3600)
+	if other := FormatForecastAsMarkdown(forecastData, "Valencia", "metric", "en"); other != result {
+		t.Error("Forecast output changed with the server timezone")
+	}
+
+	expected := []string{
+		"**Tue 11:00 AM**:", // 09:00 UTC in Valencia (UTC+2)
+		"**Tue Sep 2**:",
+		"- **Sunrise:** 7:30 AM",
+		"- **Sunset:** 8:31 PM",
+		"- **Timezone:** UTC+02:00 (all times local)",
+	}
+	for _, want := range expected {
+		if !strings.Contains(result, want) {
+			t.Errorf("Expected to find '%s' in forecast output:\n%s", want, result)
+		}
+	}
+}
+
+func TestFormatForecast_PrecipitationWindPressure(t *testing.T) {
+	forecastData := loadForecastFixture(t, "valencia_forecast_response.json")
+
+	opts := defaultForecastOptions()
+	opts.Hours, opts.Days = 21, 2
+	opts.Fields = fieldSet([]string{fieldPrecipitation, fieldWind, fieldPressure, fieldVisibility})
+
+	testCases := []struct {
+		units    string
+		expected []string
+	}{
+		{unitsMetric, []string{
+			"**Tue 11:00 AM**: wind 1.9 m/s SW (gusts 2.2 m/s), pressure 1018 hPa (ground 1016 hPa), visibility 10.0 km",
+			"**Tue 2:00 PM**: wind 5.4 m/s SE, pressure 1017 hPa (ground 1014 hPa, falling)",
+			"**Wed 2:00 AM**: wind 4.2 m/s NNW (gusts 5.0 m/s), pressure 1016 hPa (ground 1015 hPa, steady), visibility 10.0 km (20% chance rain, rain 0.2 mm)",
+			"**Tue Sep 2**: wind up to 8.5 m/s (gusts 9.7 m/s), pressure 1016 hPa, falling (-2.0 hPa), visibility down to 10.0 km",
+			"**Wed Sep 3**: 20% chance precipitation, rain 0.2 mm, wind up to 4.2 m/s (gusts 5.0 m/s)",
+		}},
+		{unitsImperial, []string{
+			"pressure 30.06 inHg (ground 30.00 inHg)",
+			"(20% chance rain, rain 0.01 in)",
+			"pressure 30.00 inHg, falling (-0.06 inHg)",
+		}},
+	}
+
+	for _, tc := range testCases {
+		result := FormatForecastWithOptions(forecastData, "", tc.units, "en", opts)
+		for _, want := range tc.expected {
+			if !strings.Contains(result, want) {
+				t.Errorf("Expected to find '%s' in %s output:\n%s", want, tc.units, result)
+			}
+		}
+	}
+
+	report := NewForecastReport(forecastData, "", unitsMetric, opts)
+	if slot := report.Slots[0]; slot.WindGust == nil || *slot.WindGust != 2.19 || slot.GroundPressure == nil || *slot.GroundPressure != 1016 {
+		t.Errorf("Unexpected slot: %+v", slot)
+	}
+	if summary := report.Summaries[1]; summary.Rain != 0.24 || summary.PressureTrend != "steady" || summary.MaxWindGust != 4.98 {
+		t.Errorf("Unexpected summary: %+v", summary)
+	}
+}
@@ -2,6 +2,7 @@ package tools
 
 import (
 	"context"
+	"encoding/json"
 	"os"
 	"strings"
 	"testing"
@@ -205,10 +206,13 @@ func TestFormatWeatherAsMarkdown(t *testing.T) {
 			TempMax   float64 `json:"temp_max"`
 			Pressure  int     `json:"pressure"`
 			Humidity  int     `json:"humidity"`
+			SeaLevel  int     `json:"sea_level"`
+			GrndLevel int     `json:"grnd_level"`
 		}{Temp: 22.5, FeelsLike: 21.8, TempMin: 20.0, TempMax: 25.0, Pressure: 1013, Humidity: 65},
 		Wind: struct {
 			Speed float64 `json:"speed"`
 			Deg   int     `json:"deg"`
+			Gust  float64 `json:"gust"`
 		}{Speed: 3.5, Deg: 220},
 		Visibility: 10000,
 		Clouds: struct {
@@ -224,7 +228,7 @@ func TestFormatWeatherAsMarkdown(t *testing.T) {
 		Name: "Mountain View",
 	}
 
-	result := FormatWeatherAsMarkdown(weatherData, "Test Location", "metric")
+	result := FormatWeatherAsMarkdown(weatherData, "Test Location", "metric", "en")
 
 	// Check for expected sections
 	expectedSections := []string{
@@ -295,6 +299,8 @@ func TestWeatherTool_RealData(t *testing.T) {
 			TempMax   float64 `json:"temp_max"`
 			Pressure  int     `json:"pressure"`
 			Humidity  int     `json:"humidity"`
+			SeaLevel  int     `json:"sea_level"`
+			GrndLevel int     `json:"grnd_level"`
 		}{
 			Temp:      302.58, // Kelvin - should be converted to Celsius in real usage
 			FeelsLike: 302.2,
@@ -306,6 +312,7 @@ func TestWeatherTool_RealData(t *testing.T) {
 		Wind: struct {
 			Speed float64 `json:"speed"`
 			Deg   int     `json:"deg"`
+			Gust  float64 `json:"gust"`
 		}{Speed: 7.2, Deg: 90},
 		Visibility: 10000,
 		Clouds: struct {
@@ -322,7 +329,7 @@ func TestWeatherTool_RealData(t *testing.T) {
 		Cod:  200,
 	}
 
-	result := FormatWeatherAsMarkdown(realWeatherData, "Valencia, Spain", "metric")
+	result := FormatWeatherAsMarkdown(realWeatherData, "Valencia, Spain", "metric", "en")
 
 	// Verify key information from real data is present
 	expectedContent := []string{
@@ -368,14 +375,14 @@ func TestBuildWeatherURLFromLocation(t *testing.T) {
 
 	// Test city name
 	cityURL := buildWeatherURLFromLocation("London,UK", apiKey, "metric")
-	expectedCityURL := "https://api.openweathermap.org/data/2.5/weather?q=London,UK&appid=test_key&units=metric"
+	expectedCityURL := "https://api.openweathermap.org/data/2.5/weather?q=London%2CUK&appid=test_key&units=metric"
 	if cityURL != expectedCityURL {
 		t.Errorf("buildWeatherURLFromLocation city = %s, expected %s", cityURL, expectedCityURL)
 	}
 
 	// Test invalid coordinates (should be treated as city)
 	invalidURL := buildWeatherURLFromLocation("invalid,coords", apiKey, "imperial")
-	expectedInvalidURL := "https://api.openweathermap.org/data/2.5/weather?q=invalid,coords&appid=test_key&units=imperial"
+	expectedInvalidURL := "https://api.openweathermap.org/data/2.5/weather?q=invalid%2Ccoords&appid=test_key&units=imperial"
 	if invalidURL != expectedInvalidURL {
 		t.Errorf("buildWeatherURLFromLocation invalid = %s, expected %s", invalidURL, expectedInvalidURL)
 	}
@@ -387,7 +394,8 @@ func TestDetermineUnitsFromLocation(t *testing.T) {
 		expectedUnits string
 	}{
 		{"New York,US", "imperial"},
-		{"London,UK", "metric"},
+		{"London,UK", "uk"},
+		{"Edinburgh, Scotland", "uk"},
 		{"Paris,France", "metric"},
 		{"Los Angeles, CA", "imperial"},
 		{"Miami,FL", "imperial"},
@@ -397,6 +405,11 @@ func TestDetermineUnitsFromLocation(t *testing.T) {
 		{"Vancouver,Canada", "metric"},
 		{"UNITED STATES", "imperial"},
 		{"USA", "imperial"},
+		{"Monrovia,LR", "imperial"},
+		{"Yangon, Myanmar", "imperial"},
+		{"San Juan,PR", "imperial"},
+		{"Hagatna,GU", "imperial"},
+		{"Madrid,ES", "metric"},
 		{"40.7128,-74.0060", "metric"}, // coordinates default to metric
 	}
 
@@ -409,7 +422,7 @@ func TestDetermineUnitsFromLocation(t *testing.T) {
 }
 
 func TestFormatWeatherAsMarkdown_ImperialUnits(t *testing.T) {
-	// Test with US location data (imperial units)
+	// OpenWeatherMap data is always fetched in metric and converted for display
 	weatherData := WeatherData{
 		Coord: struct {
 			Lon float64 `json:"lon"`
@@ -428,11 +441,14 @@ func TestFormatWeatherAsMarkdown_ImperialUnits(t *testing.T) {
 			TempMax   float64 `json:"temp_max"`
 			Pressure  int     `json:"pressure"`
 			Humidity  int     `json:"humidity"`
-		}{Temp: 72.5, FeelsLike: 75.2, TempMin: 68.0, TempMax: 76.0, Pressure: 30, Humidity: 55},
+			SeaLevel  int     `json:"sea_level"`
+			GrndLevel int     `json:"grnd_level"`
+		}{Temp: 22.5, FeelsLike: 24.0, TempMin: 20.0, TempMax: 24.4444, Pressure: 1016, Humidity: 55},
 		Wind: struct {
 			Speed float64 `json:"speed"`
 			Deg   int     `json:"deg"`
-		}{Speed: 8.5, Deg: 180},
+			Gust  float64 `json:"gust"`
+		}{Speed: 3.8, Deg: 180},
 		Visibility: 16093, // ~10 miles in meters
 		Sys: struct {
 			Type    int    `json:"type"`
@@ -444,7 +460,7 @@ func TestFormatWeatherAsMarkdown_ImperialUnits(t *testing.T) {
 		Name: "New York",
 	}
 
-	result := FormatWeatherAsMarkdown(weatherData, "New York,US", "imperial")
+	result := FormatWeatherAsMarkdown(weatherData, "New York,US", "imperial", "en")
 
 	// Check for imperial units
 	expectedImperialContent := []string{
@@ -471,3 +487,67 @@ func TestFormatWeatherAsMarkdown_ImperialUnits(t *testing.T) {
 		}
 	}
 }
+
+func TestFormatWeatherAsMarkdown_LocalSunTimes(t *testing.T) {
+	var weatherData WeatherData
+	if err := json.Unmarshal(loadFixture(t, "valencia_weather_response.json"), &weatherData); err != nil {
+		t.Fatalf("Failed to unmarshal fixture: %v", err)
+	}
+
+	result := FormatWeatherAsMarkdown(weatherData, "Valencia", "metric", "en")
+
+	expected := []string{
+		"- **Sunrise:** 7:27 AM",
+		"- **Sunset:** 8:28 PM",
+		"- **Observed:** Tue Sep 2 3:00 PM",
+		"- **Timezone:** UTC+02:00",
+	}
+	for _, want := range expected {
+		if !strings.Contains(result, want) {
+			t.Errorf("Expected to find '%s' in weather output:\n%s", want, result)
+		}
+	}
+}
+
+func TestOffsetLocation(t *testing.T) {
+	testCases := []struct {
+		offset   int
+		expected string
+	}{
+		{0, "UTC"},
+		{7200, "UTC+02:00"},
+		{-18000, "UTC-05:00"},
+		{19800, "UTC+05:30"},
+		{-12600, "UTC-03:30"},
+	}
+
+	for _, tc := range testCases {
+		if got := offsetLocation(tc.offset).String(); got != tc.expected {
+			t.Errorf("offsetLocation(%d) = %s, expected %s", tc.offset, got, tc.expected)
+		}
+	}
+}
+
+func TestFormatWeatherAsMarkdown_PressureAndGusts(t *testing.T) {
+	var weatherData WeatherData
+	if err := json.Unmarshal(loadFixture(t, "valencia_weather_response.json"), &weatherData); err != nil {
+		t.Fatalf("Failed to parse fixture: %v", err)
+	}
+
+	testCases := []struct {
+		units    string
+		expected []string
+	}{
+		{"metric", []string{"**Pressure:** 1015 hPa at sea level, 1013 hPa at ground level", "**Wind:** 4.1 m/s ESE (110°), gusts 6.2 m/s"}},
+		{"imperial", []string{"**Pressure:** 29.97 inHg at sea level, 29.91 inHg at ground level", "**Wind:** 9.2 mph ESE (110°), gusts 13.9 mph"}},
+	}
+
+	for _, tc := range testCases {
+		result := FormatWeatherAsMarkdown(weatherData, "", tc.units, "en")
+		for _, want := range tc.expected {
+			if !strings.Contains(result, want) {
+				t.Errorf("Expected to find '%s' in %s output:\n%s", want, tc.units, result)
+			}
+		}
+	}
+}